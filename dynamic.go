@@ -0,0 +1,36 @@
+package protobson
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// UnmarshalDynamic parses the BSON-encoded data into a new dynamic message of the message
+// type of full name name, as found in files, using DefaultRegistry. This is useful when only
+// descriptors are available at runtime, for instance from a FileDescriptorSet loaded with
+// protodesc.NewFiles.
+func UnmarshalDynamic(data []byte, files *protoregistry.Files, name protoreflect.FullName) (*dynamicpb.Message, error) {
+	return UnmarshalDynamicWithRegistry(DefaultRegistry, data, files, name)
+}
+
+// UnmarshalDynamicWithRegistry is like UnmarshalDynamic but uses the provided registry r.
+func UnmarshalDynamicWithRegistry(r *bsoncodec.Registry, data []byte, files *protoregistry.Files, name protoreflect.FullName) (*dynamicpb.Message, error) {
+	d, err := files.FindDescriptorByName(name)
+	if err != nil {
+		return nil, err
+	}
+	md, ok := d.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a message", name)
+	}
+	m := dynamicpb.NewMessage(md)
+	if err := bson.UnmarshalWithRegistry(r, data, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
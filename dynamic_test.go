@@ -0,0 +1,95 @@
+package protobson
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.vallahaye.net/protobson/internal/testpb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"gotest.tools/v3/assert"
+)
+
+// newDynamicFiles returns the descriptors of the test messages in package pkg, as if they
+// were loaded at runtime from a FileDescriptorSet.
+func newDynamicFiles(t *testing.T, pkg string) *protoregistry.Files {
+	t.Helper()
+	fdp := protodesc.ToFileDescriptorProto(testpb.File_testpb_proto)
+	fdp.Package = proto.String(pkg)
+	var rename func(mds []*descriptorpb.DescriptorProto)
+	rename = func(mds []*descriptorpb.DescriptorProto) {
+		for _, md := range mds {
+			for _, fd := range md.Field {
+				if fd.TypeName != nil {
+					fd.TypeName = proto.String(strings.Replace(fd.GetTypeName(), ".protobson.test.", "."+pkg+".", 1))
+				}
+			}
+			rename(md.NestedType)
+		}
+	}
+	rename(fdp.MessageType)
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	assert.NilError(t, err)
+	files := new(protoregistry.Files)
+	assert.NilError(t, files.RegisterFile(fd))
+	return files
+}
+
+func TestUnmarshalDynamic(t *testing.T) {
+	msg := &testpb.KitchenSink{
+		Int64Field:           -2,
+		Uint64Field:          4,
+		FloatField:           1.5,
+		StringField:          "foo",
+		BytesField:           []byte("bar"),
+		EnumField:            testpb.Status_STATUS_ACTIVE,
+		MessageField:         &testpb.Record{Name: "baz", CreatedAt: timestamppb.New(time.Date(2022, 5, 30, 11, 43, 26, 0, time.UTC)), Version: wrapperspb.Int64(7)},
+		OptionalField:        proto.String(""),
+		RepeatedField:        []uint64{1, 2, 3},
+		RepeatedMessageField: []*testpb.Record{{Name: "qux"}, {}},
+		MapField:             map[string]int32{"a": 1, "b": 2},
+		IntKeyMapField:       map[int64]*testpb.Record{-1: {Name: "quux", Checksum: wrapperspb.Bytes([]byte("corge"))}},
+		Choice:               &testpb.KitchenSink_ChoiceMessage{ChoiceMessage: &testpb.Record{Name: "grault"}},
+		Uint64ValueField:     wrapperspb.UInt64(42),
+	}
+	want, err := bson.MarshalWithRegistry(DefaultRegistry, msg)
+	assert.NilError(t, err)
+	for _, pkg := range []string{"protobson.test", "protobson.dynamic"} {
+		t.Run(pkg, func(t *testing.T) {
+			files := newDynamicFiles(t, pkg)
+			m, err := UnmarshalDynamic(want, files, protoreflect.FullName(pkg).Append("KitchenSink"))
+			assert.NilError(t, err)
+			b, err := proto.Marshal(m)
+			assert.NilError(t, err)
+			got := &testpb.KitchenSink{}
+			assert.NilError(t, proto.Unmarshal(b, got))
+			assert.DeepEqual(t, msg, got, protocmp.Transform())
+			b, err = bson.MarshalWithRegistry(DefaultRegistry, m)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, bson.Raw(want), bson.Raw(b))
+		})
+	}
+	t.Run("Errors", func(t *testing.T) {
+		files := newDynamicFiles(t, "protobson.dynamic")
+		_, err := UnmarshalDynamic(want, files, "protobson.dynamic.Status")
+		assert.ErrorContains(t, err, "protobson.dynamic.Status is not a message")
+		_, err = UnmarshalDynamic(want, files, "protobson.dynamic.Missing")
+		assert.ErrorIs(t, err, protoregistry.NotFound)
+		b, err := bson.Marshal(bson.D{{Key: "v", Value: bson.D{}}})
+		assert.NilError(t, err)
+		var dec struct {
+			V *dynamicpb.Message `bson:"v"`
+		}
+		err = bson.UnmarshalWithRegistry(DefaultRegistry, b, &dec)
+		assert.ErrorContains(t, err, "cannot decode into a *dynamicpb.Message without descriptor")
+	})
+}
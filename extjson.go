@@ -0,0 +1,56 @@
+package protobson
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"google.golang.org/protobuf/proto"
+)
+
+// MarshalExtJSON returns the MongoDB Extended JSON encoding of m, as it would be stored
+// in the database using DefaultRegistry. If canonical is true, the canonical form of
+// Extended JSON is used, otherwise the relaxed form is used.
+func MarshalExtJSON(m proto.Message, canonical, escapeHTML bool) ([]byte, error) {
+	return MarshalExtJSONWithRegistry(DefaultRegistry, m, canonical, escapeHTML)
+}
+
+// MarshalExtJSONWithRegistry is like MarshalExtJSON but uses the provided registry r.
+func MarshalExtJSONWithRegistry(r *bsoncodec.Registry, m proto.Message, canonical, escapeHTML bool) ([]byte, error) {
+	return bson.MarshalExtJSONWithRegistry(r, m, canonical, escapeHTML)
+}
+
+// MarshalExtJSONIndent is like MarshalExtJSON but applies Indent to format the output.
+// Each JSON element in the output will begin on a new line beginning with prefix
+// followed by one or more copies of indent according to the indentation nesting.
+func MarshalExtJSONIndent(m proto.Message, canonical, escapeHTML bool, prefix, indent string) ([]byte, error) {
+	return MarshalExtJSONIndentWithRegistry(DefaultRegistry, m, canonical, escapeHTML, prefix, indent)
+}
+
+// MarshalExtJSONIndentWithRegistry is like MarshalExtJSONIndent but uses the provided
+// registry r.
+func MarshalExtJSONIndentWithRegistry(r *bsoncodec.Registry, m proto.Message, canonical, escapeHTML bool, prefix, indent string) ([]byte, error) {
+	b, err := MarshalExtJSONWithRegistry(r, m, canonical, escapeHTML)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, b, prefix, indent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalExtJSON parses the MongoDB Extended JSON-encoded data into m using
+// DefaultRegistry. If canonical is true, only canonical Extended JSON values are
+// accepted. m is reset before any field is decoded.
+func UnmarshalExtJSON(data []byte, canonical bool, m proto.Message) error {
+	return UnmarshalExtJSONWithRegistry(DefaultRegistry, data, canonical, m)
+}
+
+// UnmarshalExtJSONWithRegistry is like UnmarshalExtJSON but uses the provided registry r.
+func UnmarshalExtJSONWithRegistry(r *bsoncodec.Registry, data []byte, canonical bool, m proto.Message) error {
+	proto.Reset(m)
+	return bson.UnmarshalExtJSONWithRegistry(r, data, canonical, m)
+}
@@ -0,0 +1,96 @@
+package protobson
+
+import (
+	"testing"
+	"time"
+
+	"go.vallahaye.net/protobson/internal/testpb"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"gotest.tools/v3/assert"
+)
+
+func TestExtJSON(t *testing.T) {
+	rec := &testpb.Record{
+		Name:      "foo",
+		Count:     42,
+		CreatedAt: timestamppb.New(time.Date(2022, 5, 30, 11, 43, 26, 0, time.UTC)),
+		Version:   wrapperspb.Int64(7),
+		Checksum:  wrapperspb.Bytes([]byte("bar")),
+		Children: []*testpb.Record{
+			{Name: "baz"},
+		},
+	}
+	for _, params := range []struct {
+		name      string
+		canonical bool
+		want      string
+	}{
+		{
+			"Canonical",
+			true,
+			`{"name":"foo","count":{"$numberLong":"42"},"createdAt":{"$date":{"$numberLong":"1653911006000"}},"version":{"$numberLong":"7"},"checksum":{"$binary":{"base64":"YmFy","subType":"00"}},"parent":null,"children":[{"name":"baz","count":{"$numberLong":"0"},"createdAt":null,"version":null,"checksum":null,"parent":null,"children":null}]}`,
+		},
+		{
+			"Relaxed",
+			false,
+			`{"name":"foo","count":42,"createdAt":{"$date":"2022-05-30T11:43:26Z"},"version":7,"checksum":{"$binary":{"base64":"YmFy","subType":"00"}},"parent":null,"children":[{"name":"baz","count":0,"createdAt":null,"version":null,"checksum":null,"parent":null,"children":null}]}`,
+		},
+	} {
+		t.Run(params.name, func(t *testing.T) {
+			got, err := MarshalExtJSON(rec, params.canonical, false)
+			assert.NilError(t, err)
+			assert.Equal(t, params.want, string(got))
+			dec := &testpb.Record{Name: "stale"}
+			err = UnmarshalExtJSON(got, params.canonical, dec)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, rec, dec, protocmp.Transform())
+		})
+	}
+}
+
+func TestMarshalExtJSONIndent(t *testing.T) {
+	got, err := MarshalExtJSONIndent(&testpb.Record{Name: "foo"}, false, false, "", "  ")
+	assert.NilError(t, err)
+	assert.Equal(t, `{
+  "name": "foo",
+  "count": 0,
+  "createdAt": null,
+  "version": null,
+  "checksum": null,
+  "parent": null,
+  "children": null
+}`, string(got))
+}
+
+func TestExtJSONWithRegistry(t *testing.T) {
+	reg := NewRegistryBuilder(protobsonoptions.Registry().
+		SetMessage(protobsonoptions.MessageCodec().SetUseProtoNames(true)).
+		SetTimestamp(protobsonoptions.TimestampCodec().SetFormat(protobsonoptions.TimestampFormatString))).
+		Build()
+	rec := &testpb.Record{
+		Name:      "foo",
+		CreatedAt: timestamppb.New(time.Date(2022, 5, 30, 11, 43, 26, 0, time.UTC)),
+	}
+	got, err := MarshalExtJSONWithRegistry(reg, rec, false, false)
+	assert.NilError(t, err)
+	assert.Equal(t, `{"name":"foo","count":0,"created_at":"2022-05-30T11:43:26Z","version":null,"checksum":null,"parent":null,"children":null}`, string(got))
+	dec := &testpb.Record{Name: "stale"}
+	err = UnmarshalExtJSONWithRegistry(reg, got, false, dec)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, rec, dec, protocmp.Transform())
+
+	got, err = MarshalExtJSONIndentWithRegistry(reg, &testpb.Record{Name: "foo"}, false, false, "", "  ")
+	assert.NilError(t, err)
+	assert.Equal(t, `{
+  "name": "foo",
+  "count": 0,
+  "created_at": null,
+  "version": null,
+  "checksum": null,
+  "parent": null,
+  "children": null
+}`, string(got))
+}
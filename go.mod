@@ -4,6 +4,7 @@ go 1.23
 
 require (
 	go.mongodb.org/mongo-driver v1.17.9
+	go.mongodb.org/mongo-driver/v2 v2.8.0
 	google.golang.org/genproto v0.0.0-20221207170731-23e4bf6bdc37
 	google.golang.org/protobuf v1.36.11
 	gotest.tools/v3 v3.5.2
@@ -0,0 +1,58 @@
+// Package bsontest contains helpers shared by the protobson codec tests.
+package bsontest
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"gotest.tools/v3/assert"
+)
+
+// EncodeValue encodes val with codec c and returns the resulting BSON value.
+func EncodeValue(t *testing.T, c bsoncodec.ValueEncoder, val interface{}) bson.RawValue {
+	t.Helper()
+	rv, err := TryEncodeValue(c, val)
+	assert.NilError(t, err)
+	return rv
+}
+
+// TryEncodeValue encodes val with codec c and returns the resulting BSON value or the encoding error.
+func TryEncodeValue(c bsoncodec.ValueEncoder, val interface{}) (bson.RawValue, error) {
+	reg := bson.NewRegistryBuilder().RegisterTypeEncoder(reflect.TypeOf(val), c).Build()
+	b, err := bson.MarshalWithRegistry(reg, bson.D{{Key: "v", Value: val}})
+	if err != nil {
+		return bson.RawValue{}, err
+	}
+	return bson.Raw(b).Lookup("v"), nil
+}
+
+// DecodeValue decodes the BSON value rv into a new value of type typ with codec c.
+func DecodeValue(t *testing.T, c bsoncodec.ValueDecoder, rv bson.RawValue, typ reflect.Type) (interface{}, error) {
+	t.Helper()
+	b, err := bson.Marshal(bson.D{{Key: "v", Value: rv}})
+	assert.NilError(t, err)
+	reg := bson.NewRegistryBuilder().RegisterTypeDecoder(typ, c).Build()
+	got := reflect.New(reflect.StructOf([]reflect.StructField{{Name: "V", Type: typ, Tag: `bson:"v"`}}))
+	err = bson.UnmarshalWithRegistry(reg, b, got.Interface())
+	return got.Elem().Field(0).Interface(), err
+}
+
+// RawValue returns val marshaled as a BSON value with the default registry.
+func RawValue(t *testing.T, val interface{}) bson.RawValue {
+	t.Helper()
+	b, err := bson.Marshal(bson.D{{Key: "v", Value: val}})
+	assert.NilError(t, err)
+	return bson.Raw(b).Lookup("v")
+}
+
+// Decimal128 parses s as a Decimal128, panicking on error.
+func Decimal128(s string) primitive.Decimal128 {
+	d, err := primitive.ParseDecimal128(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
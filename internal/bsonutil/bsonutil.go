@@ -0,0 +1,168 @@
+// Package bsonutil contains helpers shared by the protobson codecs to read and
+// write BSON values.
+package bsonutil
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// ReadDocument reads an embedded document from vr, calling fn for each of its elements.
+// fn must consume the element value it is given.
+func ReadDocument(vr bsonrw.ValueReader, fn func(key string, vr bsonrw.ValueReader) error) error {
+	dr, err := vr.ReadDocument()
+	if err != nil {
+		return err
+	}
+	for {
+		key, evr, err := dr.ReadElement()
+		if errors.Is(err, bsonrw.ErrEOD) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(key, evr); err != nil {
+			return err
+		}
+	}
+}
+
+// ReadInt64 reads an integral number from vr, accepting Int32, Int64 and Double values
+// without a fractional part.
+func ReadInt64(vr bsonrw.ValueReader) (int64, error) {
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.Int64:
+		return vr.ReadInt64()
+	case bsontype.Int32:
+		i32, err := vr.ReadInt32()
+		return int64(i32), err
+	case bsontype.Double:
+		f, err := vr.ReadDouble()
+		if err != nil {
+			return 0, err
+		}
+		if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, fmt.Errorf("%v cannot be represented as a 64-bit integer", f)
+		}
+		return int64(f), nil
+	default:
+		return 0, fmt.Errorf("cannot decode %v into a 64-bit integer", bsonTyp)
+	}
+}
+
+// TimestampToTime converts the time component t of a BSON timestamp, counting seconds since the
+// Unix epoch, into a time.Time. The increment component of BSON timestamps is an ordinal
+// distinguishing the operations of a same second, not a fraction of it, and is thus ignored.
+func TimestampToTime(t uint32) time.Time {
+	return time.Unix(int64(t), 0).UTC()
+}
+
+// ReadArray reads an array from vr, calling fn for each of its values.
+// fn must consume the value it is given.
+func ReadArray(vr bsonrw.ValueReader, fn func(i int, vr bsonrw.ValueReader) error) error {
+	ar, err := vr.ReadArray()
+	if err != nil {
+		return err
+	}
+	for i := 0; ; i++ {
+		evr, err := ar.ReadValue()
+		if errors.Is(err, bsonrw.ErrEOA) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(i, evr); err != nil {
+			return err
+		}
+	}
+}
+
+// ReadFloat64 reads a number from vr, accepting Double, Int32 and Int64 values.
+func ReadFloat64(vr bsonrw.ValueReader) (float64, error) {
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.Double:
+		return vr.ReadDouble()
+	case bsontype.Int32:
+		i32, err := vr.ReadInt32()
+		return float64(i32), err
+	case bsontype.Int64:
+		i64, err := vr.ReadInt64()
+		return float64(i64), err
+	default:
+		return 0, fmt.Errorf("cannot decode %v into a 64-bit float", bsonTyp)
+	}
+}
+
+// WriteElement writes the element key of the document being written by dw, using write to
+// write its value.
+func WriteElement(dw bsonrw.DocumentWriter, key string, write func(vw bsonrw.ValueWriter) error) error {
+	vw, err := dw.WriteDocumentElement(key)
+	if err != nil {
+		return err
+	}
+	return write(vw)
+}
+
+// WriteStrings writes ss as an array of strings.
+func WriteStrings(vw bsonrw.ValueWriter, ss []string) error {
+	aw, err := vw.WriteArray()
+	if err != nil {
+		return err
+	}
+	for _, s := range ss {
+		evw, err := aw.WriteArrayElement()
+		if err != nil {
+			return err
+		}
+		if err := evw.WriteString(s); err != nil {
+			return err
+		}
+	}
+	return aw.WriteArrayEnd()
+}
+
+// ReadStrings reads an array of strings from vr.
+func ReadStrings(vr bsonrw.ValueReader) ([]string, error) {
+	var ss []string
+	err := ReadArray(vr, func(_ int, vr bsonrw.ValueReader) error {
+		s, err := vr.ReadString()
+		ss = append(ss, s)
+		return err
+	})
+	return ss, err
+}
+
+// ReadBytes reads binary data from vr, accepting Binary values of any subtype and strings.
+// Strings are taken as is, unless base64Strings is true in which case they are base64 decoded,
+// with or without padding and in either the standard or URL-safe alphabet.
+func ReadBytes(vr bsonrw.ValueReader, base64Strings bool) ([]byte, error) {
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.Binary:
+		b, _, err := vr.ReadBinary()
+		return b, err
+	case bsontype.String:
+		s, err := vr.ReadString()
+		if err != nil || !base64Strings {
+			return []byte(s), err
+		}
+		enc := base64.StdEncoding
+		if strings.ContainsAny(s, "-_") {
+			enc = base64.URLEncoding
+		}
+		if len(s)%4 != 0 {
+			enc = enc.WithPadding(base64.NoPadding)
+		}
+		return enc.DecodeString(s)
+	default:
+		return nil, fmt.Errorf("cannot decode %v into bytes", bsonTyp)
+	}
+}
@@ -0,0 +1,60 @@
+package bsonutil
+
+import (
+	"bytes"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// The helpers of this file copy raw BSON between readers, writers and bytes, which the v1 and
+// v2 drivers expose differently: protobsonv2 has its own version of this file.
+
+// CopyDocumentToBytes reads a document from vr and returns its bytes.
+func CopyDocumentToBytes(vr bsonrw.ValueReader) ([]byte, error) {
+	return bsonrw.Copier{}.CopyDocumentToBytes(vr)
+}
+
+// CopyDocumentElements writes the elements of the document doc to dw.
+func CopyDocumentElements(dw bsonrw.DocumentWriter, doc []byte) error {
+	return bsonrw.Copier{}.CopyBytesToDocumentWriter(dw, doc)
+}
+
+// CopyValue writes the value of type t and bytes b to vw.
+func CopyValue(vw bsonrw.ValueWriter, t bsontype.Type, b []byte) error {
+	return bsonrw.Copier{}.CopyValueFromBytes(vw, t, b)
+}
+
+// NewValueReader returns a ValueReader reading the value of type t and bytes b.
+func NewValueReader(t bsontype.Type, b []byte) (bsonrw.ValueReader, error) {
+	return bsonrw.NewBSONValueReader(t, b), nil
+}
+
+// MarshalValue returns the type and bytes of the BSON value of v encoded with reg.
+func MarshalValue(reg *bsoncodec.Registry, v interface{}) (bsontype.Type, []byte, error) {
+	return bson.MarshalValueWithRegistry(reg, v)
+}
+
+// EncodeRawValue returns the BSON value written by encode using reg.
+func EncodeRawValue(reg *bsoncodec.Registry, encode func(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter) error) (bson.RawValue, error) {
+	var buf bytes.Buffer
+	vw, err := bsonrw.NewBSONValueWriter(&buf)
+	if err != nil {
+		return bson.RawValue{}, err
+	}
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return bson.RawValue{}, err
+	}
+	if err := WriteElement(dw, "v", func(vw bsonrw.ValueWriter) error {
+		return encode(bsoncodec.EncodeContext{Registry: reg}, vw)
+	}); err != nil {
+		return bson.RawValue{}, err
+	}
+	if err := dw.WriteDocumentEnd(); err != nil {
+		return bson.RawValue{}, err
+	}
+	return bson.Raw(buf.Bytes()).Lookup("v"), nil
+}
@@ -0,0 +1,46 @@
+package bsonutil
+
+import (
+	"fmt"
+	"math/big"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var bigTen = big.NewInt(10)
+
+// DecimalToBigInt returns d multiplied by 10^scale, failing if the result is not an integer.
+func DecimalToBigInt(d primitive.Decimal128, scale int) (*big.Int, error) {
+	bi, exp, err := d.BigInt()
+	if err != nil {
+		return nil, err
+	}
+	exp += scale
+	if exp >= 0 {
+		return bi.Mul(bi, new(big.Int).Exp(bigTen, big.NewInt(int64(exp)), nil)), nil
+	}
+	q, r := new(big.Int).QuoRem(bi, new(big.Int).Exp(bigTen, big.NewInt(int64(-exp)), nil), new(big.Int))
+	if r.Sign() != 0 {
+		return nil, fmt.Errorf("%v cannot be represented with %d decimal places", d, scale)
+	}
+	return q, nil
+}
+
+// DecimalFromBigInt returns bi divided by 10^scale as a Decimal128, without trailing zeros.
+func DecimalFromBigInt(bi *big.Int, scale int) (primitive.Decimal128, error) {
+	bi = new(big.Int).Set(bi)
+	exp := -scale
+	r := new(big.Int)
+	for exp < 0 && bi.Sign() != 0 {
+		q, _ := new(big.Int).QuoRem(bi, bigTen, r)
+		if r.Sign() != 0 {
+			break
+		}
+		bi, exp = q, exp+1
+	}
+	d, ok := primitive.ParseDecimal128FromBigInt(bi, exp)
+	if !ok {
+		return primitive.Decimal128{}, fmt.Errorf("%v * 10^%d cannot be represented as a Decimal128", bi, exp)
+	}
+	return d, nil
+}
@@ -0,0 +1,90 @@
+package bsonutil
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/protobsonoptions"
+)
+
+// WriteFloat64 writes f to vw, using the BSON representation nonFinite for NaN and infinite values.
+func WriteFloat64(vw bsonrw.ValueWriter, f float64, nonFinite protobsonoptions.NonFiniteFormat) error {
+	if !math.IsNaN(f) && !math.IsInf(f, 0) {
+		return vw.WriteDouble(f)
+	}
+	switch nonFinite {
+	case protobsonoptions.NonFiniteFormatString:
+		switch {
+		case math.IsNaN(f):
+			return vw.WriteString("NaN")
+		case f > 0:
+			return vw.WriteString("Infinity")
+		default:
+			return vw.WriteString("-Infinity")
+		}
+	case protobsonoptions.NonFiniteFormatReject:
+		return fmt.Errorf("%v cannot be encoded", f)
+	default:
+		return vw.WriteDouble(f)
+	}
+}
+
+// WriteFloat32 writes f to vw like WriteFloat64. If round is true, f is widened to the double
+// nearest to its shortest decimal representation rather than to its exact value.
+func WriteFloat32(vw bsonrw.ValueWriter, f float32, nonFinite protobsonoptions.NonFiniteFormat, round bool) error {
+	f64 := float64(f)
+	if round && !math.IsNaN(f64) && !math.IsInf(f64, 0) {
+		f64, _ = strconv.ParseFloat(strconv.FormatFloat(f64, 'g', -1, 32), 64)
+	}
+	return WriteFloat64(vw, f64, nonFinite)
+}
+
+// ReadAnyFloat64 reads a number from vr, accepting Double, Int32, Int64, Decimal128 and
+// numeric String values, including the "NaN", "Infinity" and "-Infinity" strings.
+func ReadAnyFloat64(vr bsonrw.ValueReader) (float64, error) {
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.Decimal128:
+		d, err := vr.ReadDecimal128()
+		if err != nil {
+			return 0, err
+		}
+		return parseFloat64(d.String())
+	case bsontype.String:
+		s, err := vr.ReadString()
+		if err != nil {
+			return 0, err
+		}
+		return parseFloat64(s)
+	default:
+		return ReadFloat64(vr)
+	}
+}
+
+// ReadAnyFloat32 reads a number from vr like ReadAnyFloat64, failing if it overflows a float32.
+func ReadAnyFloat32(vr bsonrw.ValueReader) (float32, error) {
+	f, err := ReadAnyFloat64(vr)
+	if err != nil {
+		return 0, err
+	}
+	f32 := float32(f)
+	if math.IsInf(float64(f32), 0) && !math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%v overflows float32", f)
+	}
+	return f32, nil
+}
+
+// parseFloat64 parses s as a double, failing if it overflows. Underflows are rounded to zero.
+func parseFloat64(s string) (float64, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		if math.IsInf(f, 0) {
+			return 0, fmt.Errorf("%s overflows float64", s)
+		}
+		return f, nil
+	}
+	return f, err
+}
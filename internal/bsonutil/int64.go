@@ -0,0 +1,55 @@
+package bsonutil
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/protobsonoptions"
+)
+
+// WriteInt64 writes i to vw using the BSON representation f.
+func WriteInt64(vw bsonrw.ValueWriter, i int64, f protobsonoptions.Int64Format) error {
+	switch f {
+	case protobsonoptions.Int64FormatDecimal128:
+		d, err := DecimalFromBigInt(big.NewInt(i), 0)
+		if err != nil {
+			return err
+		}
+		return vw.WriteDecimal128(d)
+	case protobsonoptions.Int64FormatString:
+		return vw.WriteString(strconv.FormatInt(i, 10))
+	default:
+		return vw.WriteInt64(i)
+	}
+}
+
+// ReadAnyInt64 reads a signed 64-bit integer from vr, accepting Int32, Int64, integral Double,
+// Decimal128 and decimal String values.
+func ReadAnyInt64(vr bsonrw.ValueReader) (int64, error) {
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.Decimal128:
+		d, err := vr.ReadDecimal128()
+		if err != nil {
+			return 0, err
+		}
+		bi, err := DecimalToBigInt(d, 0)
+		if err != nil {
+			return 0, err
+		}
+		if !bi.IsInt64() {
+			return 0, fmt.Errorf("%v overflows int64", d)
+		}
+		return bi.Int64(), nil
+	case bsontype.String:
+		s, err := vr.ReadString()
+		if err != nil {
+			return 0, err
+		}
+		return strconv.ParseInt(s, 10, 64)
+	default:
+		return ReadInt64(vr)
+	}
+}
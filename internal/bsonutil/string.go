@@ -0,0 +1,109 @@
+package bsonutil
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.vallahaye.net/protobson/protobsonoptions"
+)
+
+// WriteString writes s to vw using the BSON representation f. Empty strings are neither valid
+// ObjectIDs nor UUIDs.
+func WriteString(vw bsonrw.ValueWriter, s string, f protobsonoptions.StringFormat) error {
+	switch f {
+	case protobsonoptions.StringFormatObjectID:
+		oid, err := primitive.ObjectIDFromHex(s)
+		if err != nil {
+			return fmt.Errorf("invalid ObjectID %q", s)
+		}
+		return vw.WriteObjectID(oid)
+	case protobsonoptions.StringFormatUUID:
+		uuid, err := ParseUUID(s)
+		if err != nil {
+			return err
+		}
+		return vw.WriteBinaryWithSubtype(uuid[:], bsontype.BinaryUUID)
+	default:
+		return vw.WriteString(s)
+	}
+}
+
+// ReadString reads a string in the BSON representation f from vr. ObjectIDs and UUIDs are
+// also accepted as strings, and are returned in their canonical form.
+func ReadString(vr bsonrw.ValueReader, f protobsonoptions.StringFormat) (string, error) {
+	switch f {
+	case protobsonoptions.StringFormatObjectID:
+		switch bsonTyp := vr.Type(); bsonTyp {
+		case bsontype.ObjectID:
+			oid, err := vr.ReadObjectID()
+			return oid.Hex(), err
+		case bsontype.String:
+			s, err := vr.ReadString()
+			if err != nil {
+				return "", err
+			}
+			oid, err := primitive.ObjectIDFromHex(s)
+			if err != nil {
+				return "", fmt.Errorf("invalid ObjectID %q", s)
+			}
+			return oid.Hex(), nil
+		default:
+			return "", fmt.Errorf("cannot decode %v into an ObjectID string", bsonTyp)
+		}
+	case protobsonoptions.StringFormatUUID:
+		switch bsonTyp := vr.Type(); bsonTyp {
+		case bsontype.Binary:
+			b, subtype, err := vr.ReadBinary()
+			if err != nil {
+				return "", err
+			}
+			if subtype != bsontype.BinaryUUID || len(b) != 16 {
+				return "", fmt.Errorf("cannot decode binary of subtype %d and length %d into a UUID string", subtype, len(b))
+			}
+			return FormatUUID(b), nil
+		case bsontype.String:
+			s, err := vr.ReadString()
+			if err != nil {
+				return "", err
+			}
+			uuid, err := ParseUUID(s)
+			if err != nil {
+				return "", err
+			}
+			return FormatUUID(uuid[:]), nil
+		default:
+			return "", fmt.Errorf("cannot decode %v into a UUID string", bsonTyp)
+		}
+	default:
+		return vr.ReadString()
+	}
+}
+
+// ParseUUID parses s as a UUID in its hyphenated form, or as 32 hexadecimal digits.
+func ParseUUID(s string) ([16]byte, error) {
+	var uuid [16]byte
+	h := s
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return uuid, fmt.Errorf("invalid UUID %q", s)
+		}
+		h = strings.ReplaceAll(s, "-", "")
+	}
+	if len(h) != 32 {
+		return uuid, fmt.Errorf("invalid UUID %q", s)
+	}
+	if _, err := hex.Decode(uuid[:], []byte(h)); err != nil {
+		return uuid, fmt.Errorf("invalid UUID %q", s)
+	}
+	return uuid, nil
+}
+
+// FormatUUID formats the 16 bytes of b as a lowercase hyphenated UUID.
+func FormatUUID(b []byte) string {
+	h := hex.EncodeToString(b)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
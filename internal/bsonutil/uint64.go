@@ -0,0 +1,77 @@
+package bsonutil
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/protobsonoptions"
+)
+
+// WriteUInt64 writes u to vw using the BSON representation f.
+func WriteUInt64(vw bsonrw.ValueWriter, u uint64, f protobsonoptions.UInt64Format) error {
+	switch f {
+	case protobsonoptions.UInt64FormatDecimal128:
+		d, err := DecimalFromBigInt(new(big.Int).SetUint64(u), 0)
+		if err != nil {
+			return err
+		}
+		return vw.WriteDecimal128(d)
+	case protobsonoptions.UInt64FormatString:
+		return vw.WriteString(fmt.Sprintf("%020d", u))
+	default:
+		if u > math.MaxInt64 {
+			return fmt.Errorf("%d overflows int64", u)
+		}
+		return vw.WriteInt64(int64(u))
+	}
+}
+
+// ReadUInt64 reads an unsigned 64-bit integer from vr, accepting Int32, Int64, Double,
+// Decimal128 and decimal String values.
+func ReadUInt64(vr bsonrw.ValueReader) (uint64, error) {
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.Int64, bsontype.Int32:
+		i64, err := ReadInt64(vr)
+		if err != nil {
+			return 0, err
+		}
+		if i64 < 0 {
+			return 0, fmt.Errorf("%d overflows uint64", i64)
+		}
+		return uint64(i64), nil
+	case bsontype.Double:
+		f, err := vr.ReadDouble()
+		if err != nil {
+			return 0, err
+		}
+		if f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+			return 0, fmt.Errorf("%v cannot be represented as an unsigned 64-bit integer", f)
+		}
+		return uint64(f), nil
+	case bsontype.Decimal128:
+		d, err := vr.ReadDecimal128()
+		if err != nil {
+			return 0, err
+		}
+		bi, err := DecimalToBigInt(d, 0)
+		if err != nil {
+			return 0, err
+		}
+		if !bi.IsUint64() {
+			return 0, fmt.Errorf("%v overflows uint64", d)
+		}
+		return bi.Uint64(), nil
+	case bsontype.String:
+		s, err := vr.ReadString()
+		if err != nil {
+			return 0, err
+		}
+		return strconv.ParseUint(s, 10, 64)
+	default:
+		return 0, fmt.Errorf("cannot decode %v into an unsigned 64-bit integer", bsonTyp)
+	}
+}
@@ -0,0 +1,244 @@
+// Command v2gen generates the packages of protobsonv2 from their counterparts built on the v1
+// driver, by rewriting their use of the v1 bson packages into the bson package of the v2 driver
+// (go.mongodb.org/mongo-driver/v2). It is run from protobsonv2 by go generate.
+//
+// Both drivers share most of their API under different package names: bsoncodec, bsonrw and
+// primitive identifiers are moved to the v2 bson package as is, while bsontype identifiers are
+// prefixed with Type. The source files the v2 driver cannot be generated for are written by
+// hand in the destination package, where they take precedence over the generated ones.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// packages maps the directories of the v1 packages to those of their v2 counterparts, relative
+// to the module root.
+var packages = []struct {
+	src, dst string
+}{
+	{"internal/bsonutil", "protobsonv2/internal/bsonutil"},
+	{"protobsoncodec", "protobsonv2/protobsoncodec"},
+	{"protobsoncodec/googleapis", "protobsonv2/protobsoncodec/googleapis"},
+	{"protobsoncodec/known", "protobsonv2/protobsoncodec/known"},
+}
+
+// v1Packages maps the import paths of the v1 bson packages to those of the v2 driver.
+var v1Packages = map[string]string{
+	"go.mongodb.org/mongo-driver/bson":                     "go.mongodb.org/mongo-driver/v2/bson",
+	"go.mongodb.org/mongo-driver/bson/bsoncodec":           "go.mongodb.org/mongo-driver/v2/bson",
+	"go.mongodb.org/mongo-driver/bson/bsonrw":              "go.mongodb.org/mongo-driver/v2/bson",
+	"go.mongodb.org/mongo-driver/bson/bsontype":            "go.mongodb.org/mongo-driver/v2/bson",
+	"go.mongodb.org/mongo-driver/bson/primitive":           "go.mongodb.org/mongo-driver/v2/bson",
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore":         "go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore",
+	"go.vallahaye.net/protobson/internal/bsonutil":         "go.vallahaye.net/protobson/protobsonv2/internal/bsonutil",
+	"go.vallahaye.net/protobson/protobsoncodec":            "go.vallahaye.net/protobson/protobsonv2/protobsoncodec",
+	"go.vallahaye.net/protobson/protobsoncodec/known":      "go.vallahaye.net/protobson/protobsonv2/protobsoncodec/known",
+	"go.vallahaye.net/protobson/protobsoncodec/googleapis": "go.vallahaye.net/protobson/protobsonv2/protobsoncodec/googleapis",
+}
+
+// commentIdent matches the v1 identifiers mentioned in comments.
+var commentIdent = regexp.MustCompile(`\b(bsoncodec|bsonrw|bsontype|primitive)\.([A-Z]\w*)`)
+
+const headerPrefix = "// Code generated by v2gen"
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("v2gen: ")
+	root, err := moduleRoot()
+	if err != nil {
+		log.Fatal(err)
+	}
+	files, err := generate(root)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, pkg := range packages {
+		stale, err := generatedFiles(filepath.Join(root, pkg.dst))
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, name := range stale {
+			if err := os.Remove(name); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+	for name, src := range files {
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(name, src, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// moduleRoot returns the root directory of the module, the closest directory holding a go.mod
+// file.
+func moduleRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("go.mod not found")
+		}
+		dir = parent
+	}
+}
+
+// generate returns the contents of the files to generate by path, for the module of root
+// directory root.
+func generate(root string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	for _, pkg := range packages {
+		handwritten, err := handwrittenFiles(filepath.Join(root, pkg.dst))
+		if err != nil {
+			return nil, err
+		}
+		names, err := filepath.Glob(filepath.Join(root, pkg.src, "*.go"))
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			base := filepath.Base(name)
+			if strings.HasSuffix(base, "_test.go") || handwritten[base] {
+				continue
+			}
+			src, err := rewriteFile(name, filepath.ToSlash(filepath.Join(pkg.src, base)))
+			if err != nil {
+				return nil, err
+			}
+			files[filepath.Join(root, pkg.dst, base)] = src
+		}
+	}
+	return files, nil
+}
+
+// generatedFiles returns the paths of the files previously generated in dir.
+func generatedFiles(dir string) ([]string, error) {
+	var generated []string
+	names, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.HasPrefix(src, []byte(headerPrefix)) {
+			generated = append(generated, name)
+		}
+	}
+	return generated, nil
+}
+
+// handwrittenFiles returns the base names of the Go files of dir which were not generated.
+func handwrittenFiles(dir string) (map[string]bool, error) {
+	handwritten := make(map[string]bool)
+	names, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	generated, err := generatedFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(generated)
+	for _, name := range names {
+		if i := sort.SearchStrings(generated, name); i < len(generated) && generated[i] == name {
+			continue
+		}
+		handwritten[filepath.Base(name)] = true
+	}
+	return handwritten, nil
+}
+
+// rewriteFile returns the v2 version of the Go file of path name, whose path relative to the
+// module root is rel.
+func rewriteFile(name, rel string) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	// Local names of the imported v1 bson packages, mapped to their import path.
+	bsonNames := make(map[string]string)
+	for _, spec := range f.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		v2Path, ok := v1Packages[path]
+		if !ok {
+			continue
+		}
+		if strings.HasPrefix(path, "go.mongodb.org/mongo-driver/bson") {
+			if spec.Name != nil {
+				return nil, fmt.Errorf("%s: renamed import of %s", rel, path)
+			}
+			bsonNames[filepath.Base(path)] = path
+		}
+		spec.Path.Value = strconv.Quote(v2Path)
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		x, ok := sel.X.(*ast.Ident)
+		if !ok || x.Obj != nil {
+			return true
+		}
+		if _, ok := bsonNames[x.Name]; !ok {
+			return true
+		}
+		sel.Sel.Name = v2Name(x.Name, sel.Sel.Name)
+		x.Name = "bson"
+		return false
+	})
+	for _, cg := range f.Comments {
+		for _, c := range cg.List {
+			c.Text = commentIdent.ReplaceAllStringFunc(c.Text, func(s string) string {
+				pkg, name, _ := strings.Cut(s, ".")
+				return "bson." + v2Name(pkg, name)
+			})
+		}
+	}
+	// The v1 bson packages now all import the v2 bson package, which SortImports deduplicates.
+	ast.SortImports(fset, f)
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s from %s. DO NOT EDIT.\n\n", headerPrefix, rel)
+	if err := format.Node(&buf, fset, f); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// v2Name returns the name in the v2 bson package of the identifier name of the v1 package pkg.
+func v2Name(pkg, name string) string {
+	if pkg == "bsontype" && name != "Type" {
+		return "Type" + name
+	}
+	return name
+}
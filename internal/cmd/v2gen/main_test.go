@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	root, err := moduleRoot()
+	assert.NilError(t, err)
+	files, err := generate(root)
+	assert.NilError(t, err)
+	assert.Assert(t, len(files) > 0)
+	for name, want := range files {
+		got, err := os.ReadFile(name)
+		assert.NilError(t, err, "run go generate in protobsonv2")
+		assert.Equal(t, string(want), string(got), "%s is out of date, run go generate in protobsonv2", name)
+	}
+	for _, pkg := range packages {
+		generated, err := generatedFiles(filepath.Join(root, pkg.dst))
+		assert.NilError(t, err)
+		for _, name := range generated {
+			_, ok := files[name]
+			assert.Assert(t, ok, "%s is stale, run go generate in protobsonv2", name)
+		}
+	}
+}
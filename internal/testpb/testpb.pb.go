@@ -0,0 +1,719 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: testpb.proto
+
+package testpb
+
+import (
+	_ "go.vallahaye.net/protobson/protobsonpb"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Status int32
+
+const (
+	Status_STATUS_UNSPECIFIED Status = 0
+	Status_STATUS_ACTIVE      Status = 1
+	Status_STATUS_ARCHIVED    Status = 2
+)
+
+// Enum value maps for Status.
+var (
+	Status_name = map[int32]string{
+		0: "STATUS_UNSPECIFIED",
+		1: "STATUS_ACTIVE",
+		2: "STATUS_ARCHIVED",
+	}
+	Status_value = map[string]int32{
+		"STATUS_UNSPECIFIED": 0,
+		"STATUS_ACTIVE":      1,
+		"STATUS_ARCHIVED":    2,
+	}
+)
+
+func (x Status) Enum() *Status {
+	p := new(Status)
+	*p = x
+	return p
+}
+
+func (x Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_testpb_proto_enumTypes[0].Descriptor()
+}
+
+func (Status) Type() protoreflect.EnumType {
+	return &file_testpb_proto_enumTypes[0]
+}
+
+func (x Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Status.Descriptor instead.
+func (Status) EnumDescriptor() ([]byte, []int) {
+	return file_testpb_proto_rawDescGZIP(), []int{0}
+}
+
+type Record struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Version       *wrapperspb.Int64Value `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	Checksum      *wrapperspb.BytesValue `protobuf:"bytes,5,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	Parent        *Record                `protobuf:"bytes,6,opt,name=parent,proto3" json:"parent,omitempty"`
+	Children      []*Record              `protobuf:"bytes,7,rep,name=children,proto3" json:"children,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Record) Reset() {
+	*x = Record{}
+	mi := &file_testpb_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Record) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Record) ProtoMessage() {}
+
+func (x *Record) ProtoReflect() protoreflect.Message {
+	mi := &file_testpb_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Record.ProtoReflect.Descriptor instead.
+func (*Record) Descriptor() ([]byte, []int) {
+	return file_testpb_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Record) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Record) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *Record) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Record) GetVersion() *wrapperspb.Int64Value {
+	if x != nil {
+		return x.Version
+	}
+	return nil
+}
+
+func (x *Record) GetChecksum() *wrapperspb.BytesValue {
+	if x != nil {
+		return x.Checksum
+	}
+	return nil
+}
+
+func (x *Record) GetParent() *Record {
+	if x != nil {
+		return x.Parent
+	}
+	return nil
+}
+
+func (x *Record) GetChildren() []*Record {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+type KitchenSink struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Int32Field           int32                  `protobuf:"varint,1,opt,name=int32_field,json=int32Field,proto3" json:"int32_field,omitempty"`
+	Int64Field           int64                  `protobuf:"varint,2,opt,name=int64_field,json=int64Field,proto3" json:"int64_field,omitempty"`
+	Uint32Field          uint32                 `protobuf:"varint,3,opt,name=uint32_field,json=uint32Field,proto3" json:"uint32_field,omitempty"`
+	Uint64Field          uint64                 `protobuf:"varint,4,opt,name=uint64_field,json=uint64Field,proto3" json:"uint64_field,omitempty"`
+	Sint32Field          int32                  `protobuf:"zigzag32,5,opt,name=sint32_field,json=sint32Field,proto3" json:"sint32_field,omitempty"`
+	Sint64Field          int64                  `protobuf:"zigzag64,6,opt,name=sint64_field,json=sint64Field,proto3" json:"sint64_field,omitempty"`
+	Fixed32Field         uint32                 `protobuf:"fixed32,7,opt,name=fixed32_field,json=fixed32Field,proto3" json:"fixed32_field,omitempty"`
+	Fixed64Field         uint64                 `protobuf:"fixed64,8,opt,name=fixed64_field,json=fixed64Field,proto3" json:"fixed64_field,omitempty"`
+	Sfixed32Field        int32                  `protobuf:"fixed32,9,opt,name=sfixed32_field,json=sfixed32Field,proto3" json:"sfixed32_field,omitempty"`
+	Sfixed64Field        int64                  `protobuf:"fixed64,10,opt,name=sfixed64_field,json=sfixed64Field,proto3" json:"sfixed64_field,omitempty"`
+	FloatField           float32                `protobuf:"fixed32,11,opt,name=float_field,json=floatField,proto3" json:"float_field,omitempty"`
+	DoubleField          float64                `protobuf:"fixed64,12,opt,name=double_field,json=doubleField,proto3" json:"double_field,omitempty"`
+	BoolField            bool                   `protobuf:"varint,13,opt,name=bool_field,json=boolField,proto3" json:"bool_field,omitempty"`
+	StringField          string                 `protobuf:"bytes,14,opt,name=string_field,json=stringField,proto3" json:"string_field,omitempty"`
+	BytesField           []byte                 `protobuf:"bytes,15,opt,name=bytes_field,json=bytesField,proto3" json:"bytes_field,omitempty"`
+	EnumField            Status                 `protobuf:"varint,16,opt,name=enum_field,json=enumField,proto3,enum=protobson.test.Status" json:"enum_field,omitempty"`
+	MessageField         *Record                `protobuf:"bytes,17,opt,name=message_field,json=messageField,proto3" json:"message_field,omitempty"`
+	OptionalField        *string                `protobuf:"bytes,18,opt,name=optional_field,json=optionalField,proto3,oneof" json:"optional_field,omitempty"`
+	RepeatedField        []uint64               `protobuf:"varint,19,rep,packed,name=repeated_field,json=repeatedField,proto3" json:"repeated_field,omitempty"`
+	RepeatedMessageField []*Record              `protobuf:"bytes,20,rep,name=repeated_message_field,json=repeatedMessageField,proto3" json:"repeated_message_field,omitempty"`
+	MapField             map[string]int32       `protobuf:"bytes,21,rep,name=map_field,json=mapField,proto3" json:"map_field,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	IntKeyMapField       map[int64]*Record      `protobuf:"bytes,22,rep,name=int_key_map_field,json=intKeyMapField,proto3" json:"int_key_map_field,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Types that are valid to be assigned to Choice:
+	//
+	//	*KitchenSink_ChoiceString
+	//	*KitchenSink_ChoiceMessage
+	Choice           isKitchenSink_Choice    `protobuf_oneof:"choice"`
+	Uint64ValueField *wrapperspb.UInt64Value `protobuf:"bytes,25,opt,name=uint64_value_field,json=uint64ValueField,proto3" json:"uint64_value_field,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *KitchenSink) Reset() {
+	*x = KitchenSink{}
+	mi := &file_testpb_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KitchenSink) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KitchenSink) ProtoMessage() {}
+
+func (x *KitchenSink) ProtoReflect() protoreflect.Message {
+	mi := &file_testpb_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KitchenSink.ProtoReflect.Descriptor instead.
+func (*KitchenSink) Descriptor() ([]byte, []int) {
+	return file_testpb_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *KitchenSink) GetInt32Field() int32 {
+	if x != nil {
+		return x.Int32Field
+	}
+	return 0
+}
+
+func (x *KitchenSink) GetInt64Field() int64 {
+	if x != nil {
+		return x.Int64Field
+	}
+	return 0
+}
+
+func (x *KitchenSink) GetUint32Field() uint32 {
+	if x != nil {
+		return x.Uint32Field
+	}
+	return 0
+}
+
+func (x *KitchenSink) GetUint64Field() uint64 {
+	if x != nil {
+		return x.Uint64Field
+	}
+	return 0
+}
+
+func (x *KitchenSink) GetSint32Field() int32 {
+	if x != nil {
+		return x.Sint32Field
+	}
+	return 0
+}
+
+func (x *KitchenSink) GetSint64Field() int64 {
+	if x != nil {
+		return x.Sint64Field
+	}
+	return 0
+}
+
+func (x *KitchenSink) GetFixed32Field() uint32 {
+	if x != nil {
+		return x.Fixed32Field
+	}
+	return 0
+}
+
+func (x *KitchenSink) GetFixed64Field() uint64 {
+	if x != nil {
+		return x.Fixed64Field
+	}
+	return 0
+}
+
+func (x *KitchenSink) GetSfixed32Field() int32 {
+	if x != nil {
+		return x.Sfixed32Field
+	}
+	return 0
+}
+
+func (x *KitchenSink) GetSfixed64Field() int64 {
+	if x != nil {
+		return x.Sfixed64Field
+	}
+	return 0
+}
+
+func (x *KitchenSink) GetFloatField() float32 {
+	if x != nil {
+		return x.FloatField
+	}
+	return 0
+}
+
+func (x *KitchenSink) GetDoubleField() float64 {
+	if x != nil {
+		return x.DoubleField
+	}
+	return 0
+}
+
+func (x *KitchenSink) GetBoolField() bool {
+	if x != nil {
+		return x.BoolField
+	}
+	return false
+}
+
+func (x *KitchenSink) GetStringField() string {
+	if x != nil {
+		return x.StringField
+	}
+	return ""
+}
+
+func (x *KitchenSink) GetBytesField() []byte {
+	if x != nil {
+		return x.BytesField
+	}
+	return nil
+}
+
+func (x *KitchenSink) GetEnumField() Status {
+	if x != nil {
+		return x.EnumField
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+func (x *KitchenSink) GetMessageField() *Record {
+	if x != nil {
+		return x.MessageField
+	}
+	return nil
+}
+
+func (x *KitchenSink) GetOptionalField() string {
+	if x != nil && x.OptionalField != nil {
+		return *x.OptionalField
+	}
+	return ""
+}
+
+func (x *KitchenSink) GetRepeatedField() []uint64 {
+	if x != nil {
+		return x.RepeatedField
+	}
+	return nil
+}
+
+func (x *KitchenSink) GetRepeatedMessageField() []*Record {
+	if x != nil {
+		return x.RepeatedMessageField
+	}
+	return nil
+}
+
+func (x *KitchenSink) GetMapField() map[string]int32 {
+	if x != nil {
+		return x.MapField
+	}
+	return nil
+}
+
+func (x *KitchenSink) GetIntKeyMapField() map[int64]*Record {
+	if x != nil {
+		return x.IntKeyMapField
+	}
+	return nil
+}
+
+func (x *KitchenSink) GetChoice() isKitchenSink_Choice {
+	if x != nil {
+		return x.Choice
+	}
+	return nil
+}
+
+func (x *KitchenSink) GetChoiceString() string {
+	if x != nil {
+		if x, ok := x.Choice.(*KitchenSink_ChoiceString); ok {
+			return x.ChoiceString
+		}
+	}
+	return ""
+}
+
+func (x *KitchenSink) GetChoiceMessage() *Record {
+	if x != nil {
+		if x, ok := x.Choice.(*KitchenSink_ChoiceMessage); ok {
+			return x.ChoiceMessage
+		}
+	}
+	return nil
+}
+
+func (x *KitchenSink) GetUint64ValueField() *wrapperspb.UInt64Value {
+	if x != nil {
+		return x.Uint64ValueField
+	}
+	return nil
+}
+
+type isKitchenSink_Choice interface {
+	isKitchenSink_Choice()
+}
+
+type KitchenSink_ChoiceString struct {
+	ChoiceString string `protobuf:"bytes,23,opt,name=choice_string,json=choiceString,proto3,oneof"`
+}
+
+type KitchenSink_ChoiceMessage struct {
+	ChoiceMessage *Record `protobuf:"bytes,24,opt,name=choice_message,json=choiceMessage,proto3,oneof"`
+}
+
+func (*KitchenSink_ChoiceString) isKitchenSink_Choice() {}
+
+func (*KitchenSink_ChoiceMessage) isKitchenSink_Choice() {}
+
+type Blobs struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Uuid          []byte                 `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Md5           *wrapperspb.BytesValue `protobuf:"bytes,3,opt,name=md5,proto3" json:"md5,omitempty"`
+	Encrypted     [][]byte               `protobuf:"bytes,4,rep,name=encrypted,proto3" json:"encrypted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Blobs) Reset() {
+	*x = Blobs{}
+	mi := &file_testpb_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Blobs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Blobs) ProtoMessage() {}
+
+func (x *Blobs) ProtoReflect() protoreflect.Message {
+	mi := &file_testpb_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Blobs.ProtoReflect.Descriptor instead.
+func (*Blobs) Descriptor() ([]byte, []int) {
+	return file_testpb_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Blobs) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Blobs) GetUuid() []byte {
+	if x != nil {
+		return x.Uuid
+	}
+	return nil
+}
+
+func (x *Blobs) GetMd5() *wrapperspb.BytesValue {
+	if x != nil {
+		return x.Md5
+	}
+	return nil
+}
+
+func (x *Blobs) GetEncrypted() [][]byte {
+	if x != nil {
+		return x.Encrypted
+	}
+	return nil
+}
+
+type Identifiers struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RequestId     string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	ParentIds     []string               `protobuf:"bytes,3,rep,name=parent_ids,json=parentIds,proto3" json:"parent_ids,omitempty"`
+	LegacyId      string                 `protobuf:"bytes,4,opt,name=legacy_id,json=legacyId,proto3" json:"legacy_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Identifiers) Reset() {
+	*x = Identifiers{}
+	mi := &file_testpb_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Identifiers) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Identifiers) ProtoMessage() {}
+
+func (x *Identifiers) ProtoReflect() protoreflect.Message {
+	mi := &file_testpb_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Identifiers.ProtoReflect.Descriptor instead.
+func (*Identifiers) Descriptor() ([]byte, []int) {
+	return file_testpb_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Identifiers) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Identifiers) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *Identifiers) GetParentIds() []string {
+	if x != nil {
+		return x.ParentIds
+	}
+	return nil
+}
+
+func (x *Identifiers) GetLegacyId() string {
+	if x != nil {
+		return x.LegacyId
+	}
+	return ""
+}
+
+var File_testpb_proto protoreflect.FileDescriptor
+
+const file_testpb_proto_rawDesc = "" +
+	"\n" +
+	"\ftestpb.proto\x12\x0eprotobson.test\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/wrappers.proto\x1a\x19protobsonpb/options.proto\"\xc1\x02\n" +
+	"\x06Record\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x125\n" +
+	"\aversion\x18\x04 \x01(\v2\x1b.google.protobuf.Int64ValueR\aversion\x127\n" +
+	"\bchecksum\x18\x05 \x01(\v2\x1b.google.protobuf.BytesValueR\bchecksum\x12.\n" +
+	"\x06parent\x18\x06 \x01(\v2\x16.protobson.test.RecordR\x06parent\x122\n" +
+	"\bchildren\x18\a \x03(\v2\x16.protobson.test.RecordR\bchildren\"\xbc\n" +
+	"\n" +
+	"\vKitchenSink\x12\x1f\n" +
+	"\vint32_field\x18\x01 \x01(\x05R\n" +
+	"int32Field\x12\x1f\n" +
+	"\vint64_field\x18\x02 \x01(\x03R\n" +
+	"int64Field\x12!\n" +
+	"\fuint32_field\x18\x03 \x01(\rR\vuint32Field\x12!\n" +
+	"\fuint64_field\x18\x04 \x01(\x04R\vuint64Field\x12!\n" +
+	"\fsint32_field\x18\x05 \x01(\x11R\vsint32Field\x12!\n" +
+	"\fsint64_field\x18\x06 \x01(\x12R\vsint64Field\x12#\n" +
+	"\rfixed32_field\x18\a \x01(\aR\ffixed32Field\x12#\n" +
+	"\rfixed64_field\x18\b \x01(\x06R\ffixed64Field\x12%\n" +
+	"\x0esfixed32_field\x18\t \x01(\x0fR\rsfixed32Field\x12%\n" +
+	"\x0esfixed64_field\x18\n" +
+	" \x01(\x10R\rsfixed64Field\x12\x1f\n" +
+	"\vfloat_field\x18\v \x01(\x02R\n" +
+	"floatField\x12!\n" +
+	"\fdouble_field\x18\f \x01(\x01R\vdoubleField\x12\x1d\n" +
+	"\n" +
+	"bool_field\x18\r \x01(\bR\tboolField\x12!\n" +
+	"\fstring_field\x18\x0e \x01(\tR\vstringField\x12\x1f\n" +
+	"\vbytes_field\x18\x0f \x01(\fR\n" +
+	"bytesField\x125\n" +
+	"\n" +
+	"enum_field\x18\x10 \x01(\x0e2\x16.protobson.test.StatusR\tenumField\x12;\n" +
+	"\rmessage_field\x18\x11 \x01(\v2\x16.protobson.test.RecordR\fmessageField\x12*\n" +
+	"\x0eoptional_field\x18\x12 \x01(\tH\x01R\roptionalField\x88\x01\x01\x12%\n" +
+	"\x0erepeated_field\x18\x13 \x03(\x04R\rrepeatedField\x12L\n" +
+	"\x16repeated_message_field\x18\x14 \x03(\v2\x16.protobson.test.RecordR\x14repeatedMessageField\x12F\n" +
+	"\tmap_field\x18\x15 \x03(\v2).protobson.test.KitchenSink.MapFieldEntryR\bmapField\x12Z\n" +
+	"\x11int_key_map_field\x18\x16 \x03(\v2/.protobson.test.KitchenSink.IntKeyMapFieldEntryR\x0eintKeyMapField\x12%\n" +
+	"\rchoice_string\x18\x17 \x01(\tH\x00R\fchoiceString\x12?\n" +
+	"\x0echoice_message\x18\x18 \x01(\v2\x16.protobson.test.RecordH\x00R\rchoiceMessage\x12J\n" +
+	"\x12uint64_value_field\x18\x19 \x01(\v2\x1c.google.protobuf.UInt64ValueR\x10uint64ValueField\x1a;\n" +
+	"\rMapFieldEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1aY\n" +
+	"\x13IntKeyMapFieldEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x03R\x03key\x12,\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.protobson.test.RecordR\x05value:\x028\x01B\b\n" +
+	"\x06choiceB\x11\n" +
+	"\x0f_optional_field\"\x94\x01\n" +
+	"\x05Blobs\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12\x1a\n" +
+	"\x04uuid\x18\x02 \x01(\fB\x06\x82\x80\x19\x02\b\x04R\x04uuid\x125\n" +
+	"\x03md5\x18\x03 \x01(\v2\x1b.google.protobuf.BytesValueB\x06\x82\x80\x19\x02\b\x05R\x03md5\x12$\n" +
+	"\tencrypted\x18\x04 \x03(\fB\x06\x82\x80\x19\x02\b\x06R\tencrypted\"\x90\x01\n" +
+	"\vIdentifiers\x12\x16\n" +
+	"\x02id\x18\x01 \x01(\tB\x06\x82\x80\x19\x02\x10\x01R\x02id\x12%\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tB\x06\x82\x80\x19\x02\x10\x02R\trequestId\x12%\n" +
+	"\n" +
+	"parent_ids\x18\x03 \x03(\tB\x06\x82\x80\x19\x02\x10\x01R\tparentIds\x12\x1b\n" +
+	"\tlegacy_id\x18\x04 \x01(\tR\blegacyId*H\n" +
+	"\x06Status\x12\x16\n" +
+	"\x12STATUS_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rSTATUS_ACTIVE\x10\x01\x12\x13\n" +
+	"\x0fSTATUS_ARCHIVED\x10\x02B,Z*go.vallahaye.net/protobson/internal/testpbb\x06proto3"
+
+var (
+	file_testpb_proto_rawDescOnce sync.Once
+	file_testpb_proto_rawDescData []byte
+)
+
+func file_testpb_proto_rawDescGZIP() []byte {
+	file_testpb_proto_rawDescOnce.Do(func() {
+		file_testpb_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_testpb_proto_rawDesc), len(file_testpb_proto_rawDesc)))
+	})
+	return file_testpb_proto_rawDescData
+}
+
+var file_testpb_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_testpb_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_testpb_proto_goTypes = []any{
+	(Status)(0),                    // 0: protobson.test.Status
+	(*Record)(nil),                 // 1: protobson.test.Record
+	(*KitchenSink)(nil),            // 2: protobson.test.KitchenSink
+	(*Blobs)(nil),                  // 3: protobson.test.Blobs
+	(*Identifiers)(nil),            // 4: protobson.test.Identifiers
+	nil,                            // 5: protobson.test.KitchenSink.MapFieldEntry
+	nil,                            // 6: protobson.test.KitchenSink.IntKeyMapFieldEntry
+	(*timestamppb.Timestamp)(nil),  // 7: google.protobuf.Timestamp
+	(*wrapperspb.Int64Value)(nil),  // 8: google.protobuf.Int64Value
+	(*wrapperspb.BytesValue)(nil),  // 9: google.protobuf.BytesValue
+	(*wrapperspb.UInt64Value)(nil), // 10: google.protobuf.UInt64Value
+}
+var file_testpb_proto_depIdxs = []int32{
+	7,  // 0: protobson.test.Record.created_at:type_name -> google.protobuf.Timestamp
+	8,  // 1: protobson.test.Record.version:type_name -> google.protobuf.Int64Value
+	9,  // 2: protobson.test.Record.checksum:type_name -> google.protobuf.BytesValue
+	1,  // 3: protobson.test.Record.parent:type_name -> protobson.test.Record
+	1,  // 4: protobson.test.Record.children:type_name -> protobson.test.Record
+	0,  // 5: protobson.test.KitchenSink.enum_field:type_name -> protobson.test.Status
+	1,  // 6: protobson.test.KitchenSink.message_field:type_name -> protobson.test.Record
+	1,  // 7: protobson.test.KitchenSink.repeated_message_field:type_name -> protobson.test.Record
+	5,  // 8: protobson.test.KitchenSink.map_field:type_name -> protobson.test.KitchenSink.MapFieldEntry
+	6,  // 9: protobson.test.KitchenSink.int_key_map_field:type_name -> protobson.test.KitchenSink.IntKeyMapFieldEntry
+	1,  // 10: protobson.test.KitchenSink.choice_message:type_name -> protobson.test.Record
+	10, // 11: protobson.test.KitchenSink.uint64_value_field:type_name -> google.protobuf.UInt64Value
+	9,  // 12: protobson.test.Blobs.md5:type_name -> google.protobuf.BytesValue
+	1,  // 13: protobson.test.KitchenSink.IntKeyMapFieldEntry.value:type_name -> protobson.test.Record
+	14, // [14:14] is the sub-list for method output_type
+	14, // [14:14] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_testpb_proto_init() }
+func file_testpb_proto_init() {
+	if File_testpb_proto != nil {
+		return
+	}
+	file_testpb_proto_msgTypes[1].OneofWrappers = []any{
+		(*KitchenSink_ChoiceString)(nil),
+		(*KitchenSink_ChoiceMessage)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_testpb_proto_rawDesc), len(file_testpb_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_testpb_proto_goTypes,
+		DependencyIndexes: file_testpb_proto_depIdxs,
+		EnumInfos:         file_testpb_proto_enumTypes,
+		MessageInfos:      file_testpb_proto_msgTypes,
+	}.Build()
+	File_testpb_proto = out.File
+	file_testpb_proto_goTypes = nil
+	file_testpb_proto_depIdxs = nil
+}
@@ -0,0 +1,34 @@
+package protobson
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.vallahaye.net/protobson/protobsoncodec"
+	"google.golang.org/protobuf/proto"
+)
+
+// UnmarshalWithNulls parses the BSON-encoded data into m using DefaultRegistry, and returns
+// the dotted paths of the fields explicitly set to null in data, such as "parent.version".
+// The paths can be used to build $unset updates.
+func UnmarshalWithNulls(data []byte, m proto.Message) ([]string, error) {
+	return UnmarshalWithNullsWithRegistry(DefaultRegistry, data, m)
+}
+
+// UnmarshalWithNullsWithRegistry is like UnmarshalWithNulls but uses the provided registry r,
+// in which the type of m must be decoded by a *protobsoncodec.MessageCodec.
+func UnmarshalWithNullsWithRegistry(r *bsoncodec.Registry, data []byte, m proto.Message) ([]string, error) {
+	v := reflect.New(reflect.TypeOf(m)).Elem()
+	v.Set(reflect.ValueOf(m))
+	decoder, err := r.LookupDecoder(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	c, ok := decoder.(*protobsoncodec.MessageCodec)
+	if !ok {
+		return nil, fmt.Errorf("%T is not decoded by a MessageCodec", m)
+	}
+	return c.DecodeWithNulls(bsoncodec.DecodeContext{Registry: r}, bsonrw.NewBSONDocumentReader(data), v)
+}
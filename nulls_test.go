@@ -0,0 +1,28 @@
+package protobson
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.vallahaye.net/protobson/internal/testpb"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"gotest.tools/v3/assert"
+)
+
+func TestUnmarshalWithNulls(t *testing.T) {
+	b, err := bson.Marshal(bson.D{
+		{Key: "name", Value: "a"},
+		{Key: "createdAt", Value: nil},
+		{Key: "parent", Value: bson.D{{Key: "version", Value: int64(2)}, {Key: "parent", Value: bson.D{{Key: "checksum", Value: nil}}}}},
+	})
+	assert.NilError(t, err)
+	got := &testpb.Record{}
+	nulls, err := UnmarshalWithNulls(b, got)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, &testpb.Record{Name: "a", Parent: &testpb.Record{Version: wrapperspb.Int64(2), Parent: &testpb.Record{}}}, got, protocmp.Transform())
+	assert.DeepEqual(t, []string{"createdAt", "parent.parent.checksum"}, nulls)
+
+	_, err = UnmarshalWithNullsWithRegistry(bson.DefaultRegistry, b, got)
+	assert.ErrorContains(t, err, "*testpb.Record is not decoded by a MessageCodec")
+}
@@ -0,0 +1,144 @@
+package googleapis
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// anyTypeKey is the key holding the type URL of expanded *anypb.Any values, as in protojson.
+const anyTypeKey = "@type"
+
+// writeAny writes a as a document holding its type URL under "@type". If the type of a is
+// known to resolver, its message is expanded using the registry of ec: messages encoded as
+// documents are inlined, other values are held under "value". Otherwise, the serialized
+// message is held as binary under "value".
+func writeAny(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, a *anypb.Any, resolver *protoregistry.Types) error {
+	if a == nil {
+		return vw.WriteNull()
+	}
+	typ, data := bsontype.Binary, bsoncore.AppendBinary(nil, bsontype.BinaryGeneric, a.Value)
+	m, err := anypb.UnmarshalNew(a, proto.UnmarshalOptions{Resolver: resolver})
+	switch {
+	case err == nil:
+		typ, data, err = bsonutil.MarshalValue(ec.Registry, m)
+		if err != nil {
+			return fmt.Errorf("%s: %w", a.TypeUrl, err)
+		}
+	case !errors.Is(err, protoregistry.NotFound):
+		return fmt.Errorf("%s: %w", a.TypeUrl, err)
+	}
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+	evw, err := dw.WriteDocumentElement(anyTypeKey)
+	if err != nil {
+		return err
+	}
+	if err := evw.WriteString(a.TypeUrl); err != nil {
+		return err
+	}
+	if typ == bsontype.EmbeddedDocument {
+		if err := bsonutil.CopyDocumentElements(dw, data); err != nil {
+			return err
+		}
+	} else {
+		evw, err := dw.WriteDocumentElement("value")
+		if err != nil {
+			return err
+		}
+		if err := bsonutil.CopyValue(evw, typ, data); err != nil {
+			return err
+		}
+	}
+	return dw.WriteDocumentEnd()
+}
+
+// readAny reads a document written by writeAny. Messages are decoded using the registry of
+// dc, so expanded documents must have been written with a compatible registry.
+func readAny(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, resolver *protoregistry.Types) (*anypb.Any, error) {
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.EmbeddedDocument:
+	case bsontype.Null:
+		return nil, vr.ReadNull()
+	default:
+		return nil, fmt.Errorf("cannot decode %v into a *anypb.Any", bsonTyp)
+	}
+	b, err := bsonutil.CopyDocumentToBytes(vr)
+	if err != nil {
+		return nil, err
+	}
+	doc := bson.Raw(b)
+	typeURL, ok := doc.Lookup(anyTypeKey).StringValueOK()
+	if !ok {
+		return nil, fmt.Errorf("missing %q in a *anypb.Any document", anyTypeKey)
+	}
+	elems, err := doc.Elements()
+	if err != nil {
+		return nil, err
+	}
+	value := doc.Lookup("value")
+	hasValue := len(elems) == 2 && value.Type != 0 && value.Type != bsontype.EmbeddedDocument
+	mt, err := resolver.FindMessageByURL(typeURL)
+	if errors.Is(err, protoregistry.NotFound) {
+		_, data, ok := value.BinaryOK()
+		if len(elems) != 2 || !ok {
+			return nil, fmt.Errorf("%s: cannot decode an unresolvable expanded message", typeURL)
+		}
+		return &anypb.Any{TypeUrl: typeURL, Value: data}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := mt.New().Interface()
+	mv := reflect.New(reflect.TypeOf(m))
+	// As written by writeAny, values of messages not encoded as documents are held under
+	// "value", which is never the case of documents.
+	if hasValue && !encodesAsDocument(dc.Registry, m) {
+		err = value.UnmarshalWithRegistry(dc.Registry, mv.Interface())
+	} else {
+		err = bson.RawValue{Type: bsontype.EmbeddedDocument, Value: withoutKey(elems, anyTypeKey)}.UnmarshalWithRegistry(dc.Registry, mv.Interface())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", typeURL, err)
+	}
+	if !mv.Elem().IsNil() {
+		m = mv.Elem().Interface().(proto.Message)
+	}
+	a := &anypb.Any{}
+	if err := anypb.MarshalFrom(a, m, proto.MarshalOptions{Deterministic: true}); err != nil {
+		return nil, err
+	}
+	a.TypeUrl = typeURL
+	return a, nil
+}
+
+// encodesAsDocument reports whether the empty message m is encoded as a document by reg. This
+// is assumed to be the case of all messages of its type, as writeAny does.
+func encodesAsDocument(reg *bsoncodec.Registry, m proto.Message) bool {
+	typ, _, err := bsonutil.MarshalValue(reg, m)
+	return err == nil && typ == bsontype.EmbeddedDocument
+}
+
+// withoutKey returns the document made of elems without the element of key key.
+func withoutKey(elems []bson.RawElement, key string) bson.Raw {
+	idx, doc := bsoncore.AppendDocumentStart(nil)
+	for _, elem := range elems {
+		if elem.Key() != key {
+			doc = append(doc, elem...)
+		}
+	}
+	doc, _ = bsoncore.AppendDocumentEnd(doc, idx)
+	return doc
+}
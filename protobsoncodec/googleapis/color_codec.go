@@ -0,0 +1,171 @@
+package googleapis
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/type/color"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var colorPattern = regexp.MustCompile(`^#([0-9A-Fa-f]{6}|[0-9A-Fa-f]{8})$`)
+
+// Color type.
+var TypeColor = reflect.TypeOf((*color.Color)(nil))
+
+// ColorCodec is the Codec used for *color.Color values.
+type ColorCodec struct {
+	Format protobsonoptions.ColorFormat
+}
+
+// EncodeValue is the ValueEncoderFunc for *color.Color.
+func (c *ColorCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
+	if !v.IsValid() || v.Type() != TypeColor {
+		return bsoncodec.ValueEncoderError{
+			Name:     "ColorCodec.EncodeValue",
+			Types:    []reflect.Type{TypeColor},
+			Received: v,
+		}
+	}
+	col := v.Interface().(*color.Color)
+	if col == nil {
+		return vw.WriteNull()
+	}
+	if err := validateColor(col); err != nil {
+		return err
+	}
+	if c.Format == protobsonoptions.ColorFormatHex {
+		b := []byte{colorByte(col.Red), colorByte(col.Green), colorByte(col.Blue)}
+		if col.Alpha != nil {
+			b = append(b, colorByte(col.Alpha.Value))
+		}
+		return vw.WriteString(fmt.Sprintf("#%X", b))
+	}
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+	keys := []string{"red", "green", "blue"}
+	components := []float32{col.Red, col.Green, col.Blue}
+	if col.Alpha != nil {
+		keys, components = append(keys, "alpha"), append(components, col.Alpha.Value)
+	}
+	for i, key := range keys {
+		evw, err := dw.WriteDocumentElement(key)
+		if err != nil {
+			return err
+		}
+		if err := evw.WriteDouble(float64(components[i])); err != nil {
+			return err
+		}
+	}
+	return dw.WriteDocumentEnd()
+}
+
+// DecodeValue is the ValueDecoderFunc for *color.Color.
+func (c *ColorCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) error {
+	if !v.CanSet() || v.Type() != TypeColor {
+		return bsoncodec.ValueDecoderError{
+			Name:     "ColorCodec.DecodeValue",
+			Types:    []reflect.Type{TypeColor},
+			Received: v,
+		}
+	}
+	var col *color.Color
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.String:
+		s, err := vr.ReadString()
+		if err != nil {
+			return err
+		}
+		if !colorPattern.MatchString(s) {
+			return fmt.Errorf("invalid color %q", s)
+		}
+		b, err := hex.DecodeString(s[1:])
+		if err != nil {
+			return err
+		}
+		col = &color.Color{
+			Red:   float32(b[0]) / math.MaxUint8,
+			Green: float32(b[1]) / math.MaxUint8,
+			Blue:  float32(b[2]) / math.MaxUint8,
+		}
+		if len(b) == 4 {
+			col.Alpha = wrapperspb.Float(float32(b[3]) / math.MaxUint8)
+		}
+	case bsontype.EmbeddedDocument:
+		col = &color.Color{}
+		err := bsonutil.ReadDocument(vr, func(key string, vr bsonrw.ValueReader) error {
+			if key == "alpha" && vr.Type() == bsontype.Null {
+				return vr.ReadNull()
+			}
+			f, err := bsonutil.ReadFloat64(vr)
+			if err != nil {
+				return err
+			}
+			switch key {
+			case "red":
+				col.Red = float32(f)
+			case "green":
+				col.Green = float32(f)
+			case "blue":
+				col.Blue = float32(f)
+			case "alpha":
+				col.Alpha = wrapperspb.Float(float32(f))
+			default:
+				return fmt.Errorf("unexpected key %q in a *color.Color document", key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if err := validateColor(col); err != nil {
+			return err
+		}
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+		col = nil
+	case bsontype.Undefined:
+		if err := vr.ReadUndefined(); err != nil {
+			return err
+		}
+		col = &color.Color{}
+	default:
+		return fmt.Errorf("cannot decode %v into a *color.Color", bsonTyp)
+	}
+	v.Set(reflect.ValueOf(col))
+	return nil
+}
+
+// NewColorCodec returns a ColorCodec with options opts.
+func NewColorCodec(opts ...*protobsonoptions.ColorCodecOptions) *ColorCodec {
+	mergedOpts := protobsonoptions.MergeColorCodecOptions(opts...)
+	return &ColorCodec{
+		Format: *mergedOpts.Format,
+	}
+}
+
+// validateColor reports whether all components of col are in the [0, 1] interval.
+func validateColor(col *color.Color) error {
+	for _, f := range []float32{col.Red, col.Green, col.Blue, col.GetAlpha().GetValue()} {
+		if !(f >= 0 && f <= 1) {
+			return fmt.Errorf("invalid color component %v", f)
+		}
+	}
+	return nil
+}
+
+func colorByte(f float32) byte {
+	return byte(math.Round(float64(f) * math.MaxUint8))
+}
@@ -0,0 +1,138 @@
+package googleapis
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsontest"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/type/color"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"gotest.tools/v3/assert"
+)
+
+func TestColorCodec(t *testing.T) {
+	col := &color.Color{Red: 1, Green: 0.2, Blue: 0, Alpha: wrapperspb.Float(0.6)}
+	t.Run("EncodeToBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			col  *color.Color
+			vw   *bsonrwtest.ValueReaderWriter
+			want bsonrwtest.Invoked
+		}{
+			{
+				nil,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.Null},
+				bsonrwtest.WriteNull,
+			},
+			{
+				col,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.String},
+				bsonrwtest.WriteString,
+			},
+		} {
+			t.Run(params.vw.Type().String(), func(t *testing.T) {
+				c := NewColorCodec()
+				v := reflect.ValueOf(params.col)
+				err := c.EncodeValue(bsoncodec.EncodeContext{}, params.vw, v)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, params.vw.Invoked)
+			})
+		}
+	})
+	t.Run("DecodeFromBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			vr   *bsonrwtest.ValueReaderWriter
+			want *color.Color
+		}{
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.String,
+					Return:   "#ff3300",
+				},
+				&color.Color{Red: 1, Green: 0.2, Blue: 0},
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Null,
+				},
+				nil,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Undefined,
+				},
+				&color.Color{},
+			},
+		} {
+			t.Run(params.vr.Type().String(), func(t *testing.T) {
+				c := NewColorCodec()
+				got := reflect.New(reflect.TypeOf(params.want)).Elem()
+				err := c.DecodeValue(bsoncodec.DecodeContext{}, params.vr, got)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, got.Interface(), protocmp.Transform())
+			})
+		}
+	})
+	t.Run("Formats", func(t *testing.T) {
+		for _, params := range []struct {
+			name   string
+			format protobsonoptions.ColorFormat
+			col    *color.Color
+			want   interface{}
+		}{
+			{
+				"Hex",
+				protobsonoptions.ColorFormatHex,
+				col,
+				"#FF330099",
+			},
+			{
+				"HexOpaque",
+				protobsonoptions.ColorFormatHex,
+				&color.Color{Red: 1, Green: 0.2, Blue: 0},
+				"#FF3300",
+			},
+			{
+				"Document",
+				protobsonoptions.ColorFormatDocument,
+				col,
+				bson.D{{Key: "red", Value: 1.0}, {Key: "green", Value: float64(float32(0.2))}, {Key: "blue", Value: 0.0}, {Key: "alpha", Value: float64(float32(0.6))}},
+			},
+			{
+				"DocumentOpaque",
+				protobsonoptions.ColorFormatDocument,
+				&color.Color{Red: 1, Green: 0.2, Blue: 0},
+				bson.D{{Key: "red", Value: 1.0}, {Key: "green", Value: float64(float32(0.2))}, {Key: "blue", Value: 0.0}},
+			},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				c := NewColorCodec(protobsonoptions.ColorCodec().SetFormat(params.format))
+				got := bsontest.EncodeValue(t, c, params.col)
+				assert.DeepEqual(t, bsontest.RawValue(t, params.want), got)
+				dec, err := bsontest.DecodeValue(t, c, got, TypeColor)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.col, dec, protocmp.Transform())
+			})
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		c := NewColorCodec()
+		for _, col := range []*color.Color{
+			{Red: 1.5},
+			{Blue: -0.1},
+			{Alpha: wrapperspb.Float(2)},
+		} {
+			_, err := bsontest.TryEncodeValue(c, col)
+			assert.ErrorContains(t, err, "invalid color component")
+		}
+		for _, val := range []interface{}{"FF3300", "#FF330", "#GG3300", bson.D{{Key: "red", Value: 2.0}}} {
+			_, err := bsontest.DecodeValue(t, c, bsontest.RawValue(t, val), TypeColor)
+			assert.Assert(t, err != nil)
+		}
+	})
+}
@@ -0,0 +1,224 @@
+package googleapis
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/type/date"
+)
+
+// Date type.
+var TypeDate = reflect.TypeOf((*date.Date)(nil))
+
+// DateCodec is the Codec used for *date.Date values.
+type DateCodec struct {
+	Format protobsonoptions.DateFormat
+}
+
+// EncodeValue is the ValueEncoderFunc for *date.Date.
+func (c *DateCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
+	if !v.IsValid() || v.Type() != TypeDate {
+		return bsoncodec.ValueEncoderError{
+			Name:     "DateCodec.EncodeValue",
+			Types:    []reflect.Type{TypeDate},
+			Received: v,
+		}
+	}
+	d := v.Interface().(*date.Date)
+	if d == nil {
+		return vw.WriteNull()
+	}
+	if err := validateDate(d); err != nil {
+		return err
+	}
+	switch c.Format {
+	case protobsonoptions.DateFormatString:
+		return vw.WriteString(formatDate(d))
+	case protobsonoptions.DateFormatInt:
+		return vw.WriteInt32(d.Year*10000 + d.Month*100 + d.Day)
+	default:
+		if d.Year != 0 && d.Month != 0 && d.Day != 0 {
+			return vw.WriteDateTime(time.Date(int(d.Year), time.Month(d.Month), int(d.Day), 0, 0, 0, 0, time.UTC).UnixMilli())
+		}
+		dw, err := vw.WriteDocument()
+		if err != nil {
+			return err
+		}
+		for _, elem := range []struct {
+			key string
+			val int32
+		}{
+			{"year", d.Year},
+			{"month", d.Month},
+			{"day", d.Day},
+		} {
+			evw, err := dw.WriteDocumentElement(elem.key)
+			if err != nil {
+				return err
+			}
+			if err := evw.WriteInt32(elem.val); err != nil {
+				return err
+			}
+		}
+		return dw.WriteDocumentEnd()
+	}
+}
+
+// DecodeValue is the ValueDecoderFunc for *date.Date.
+func (c *DateCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) error {
+	if !v.CanSet() || v.Type() != TypeDate {
+		return bsoncodec.ValueDecoderError{
+			Name:     "DateCodec.DecodeValue",
+			Types:    []reflect.Type{TypeDate},
+			Received: v,
+		}
+	}
+	var d *date.Date
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.DateTime:
+		msec, err := vr.ReadDateTime()
+		if err != nil {
+			return err
+		}
+		t := time.UnixMilli(msec).UTC()
+		if h, m, s := t.Clock(); h != 0 || m != 0 || s != 0 || t.Nanosecond() != 0 {
+			return fmt.Errorf("%v is not a date at midnight UTC", t)
+		}
+		if t.Year() < 1 {
+			return fmt.Errorf("invalid date %v", t.Format("2006-01-02"))
+		}
+		d = &date.Date{Year: int32(t.Year()), Month: int32(t.Month()), Day: int32(t.Day())}
+		if err := validateDate(d); err != nil {
+			return err
+		}
+	case bsontype.Int32, bsontype.Int64:
+		i64, err := bsonutil.ReadInt64(vr)
+		if err != nil {
+			return err
+		}
+		d = &date.Date{Year: int32(i64 / 10000), Month: int32(i64 / 100 % 100), Day: int32(i64 % 100)}
+		if err := validateDate(d); err != nil {
+			return err
+		}
+	case bsontype.String:
+		s, err := vr.ReadString()
+		if err != nil {
+			return err
+		}
+		d, err = parseDate(s)
+		if err != nil {
+			return err
+		}
+		if err := validateDate(d); err != nil {
+			return err
+		}
+	case bsontype.EmbeddedDocument:
+		d = &date.Date{}
+		err := bsonutil.ReadDocument(vr, func(key string, vr bsonrw.ValueReader) error {
+			i64, err := bsonutil.ReadInt64(vr)
+			if err != nil {
+				return err
+			}
+			switch key {
+			case "year":
+				d.Year = int32(i64)
+			case "month":
+				d.Month = int32(i64)
+			case "day":
+				d.Day = int32(i64)
+			default:
+				return fmt.Errorf("unexpected key %q in a *date.Date document", key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if err := validateDate(d); err != nil {
+			return err
+		}
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+		d = nil
+	case bsontype.Undefined:
+		if err := vr.ReadUndefined(); err != nil {
+			return err
+		}
+		d = &date.Date{}
+	default:
+		return fmt.Errorf("cannot decode %v into a *date.Date", bsonTyp)
+	}
+	v.Set(reflect.ValueOf(d))
+	return nil
+}
+
+// NewDateCodec returns a DateCodec with options opts.
+func NewDateCodec(opts ...*protobsonoptions.DateCodecOptions) *DateCodec {
+	mergedOpts := protobsonoptions.MergeDateCodecOptions(opts...)
+	return &DateCodec{
+		Format: *mergedOpts.Format,
+	}
+}
+
+// validateDate reports whether d is a full date or one of the partial dates allowed by
+// google.type.Date.
+func validateDate(d *date.Date) error {
+	valid := d.Year >= 0 && d.Year <= 9999 && d.Month >= 0 && d.Month <= 12 && d.Day >= 0 &&
+		(d.Month != 0 || d.Day == 0) && (d.Year != 0 || (d.Month != 0 && d.Day != 0))
+	if valid && d.Day != 0 {
+		year := int(d.Year)
+		if year == 0 {
+			// Accept February 29th when the year is unspecified.
+			year = 2000
+		}
+		valid = int(d.Day) <= time.Date(year, time.Month(d.Month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	}
+	if !valid {
+		return fmt.Errorf("invalid date %04d-%02d-%02d", d.Year, d.Month, d.Day)
+	}
+	return nil
+}
+
+func formatDate(d *date.Date) string {
+	switch {
+	case d.Year == 0:
+		return fmt.Sprintf("--%02d-%02d", d.Month, d.Day)
+	case d.Month == 0:
+		return fmt.Sprintf("%04d", d.Year)
+	case d.Day == 0:
+		return fmt.Sprintf("%04d-%02d", d.Year, d.Month)
+	default:
+		return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+	}
+}
+
+func parseDate(s string) (*date.Date, error) {
+	var d date.Date
+	parts := strings.Split(s, "-")
+	widths := []int{4, 2, 2}
+	dst := []*int32{&d.Year, &d.Month, &d.Day}
+	if len(parts) == 4 && parts[0] == "" && parts[1] == "" {
+		parts, widths, dst = parts[2:], widths[1:], dst[1:]
+	}
+	if len(parts) > len(dst) {
+		return nil, fmt.Errorf("cannot parse %q as a date", s)
+	}
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || len(part) != widths[i] {
+			return nil, fmt.Errorf("cannot parse %q as a date", s)
+		}
+		*dst[i] = int32(n)
+	}
+	return &d, nil
+}
@@ -0,0 +1,185 @@
+package googleapis
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.vallahaye.net/protobson/internal/bsontest"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/type/date"
+	"google.golang.org/protobuf/testing/protocmp"
+	"gotest.tools/v3/assert"
+)
+
+func TestDateCodec(t *testing.T) {
+	d := &date.Date{Year: 2022, Month: 5, Day: 30}
+	t.Run("EncodeToBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			d    *date.Date
+			vw   *bsonrwtest.ValueReaderWriter
+			want bsonrwtest.Invoked
+		}{
+			{
+				nil,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.Null},
+				bsonrwtest.WriteNull,
+			},
+			{
+				d,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.DateTime},
+				bsonrwtest.WriteDateTime,
+			},
+		} {
+			t.Run(params.vw.Type().String(), func(t *testing.T) {
+				c := NewDateCodec()
+				v := reflect.ValueOf(params.d)
+				err := c.EncodeValue(bsoncodec.EncodeContext{}, params.vw, v)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, params.vw.Invoked)
+			})
+		}
+	})
+	t.Run("DecodeFromBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			vr   *bsonrwtest.ValueReaderWriter
+			want *date.Date
+		}{
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.DateTime,
+					Return:   int64(1653868800000),
+				},
+				d,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Int32,
+					Return:   int32(20220530),
+				},
+				d,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.String,
+					Return:   "2022-05-30",
+				},
+				d,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Null,
+				},
+				nil,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Undefined,
+				},
+				&date.Date{},
+			},
+		} {
+			t.Run(params.vr.Type().String(), func(t *testing.T) {
+				c := NewDateCodec()
+				got := reflect.New(reflect.TypeOf(params.want)).Elem()
+				err := c.DecodeValue(bsoncodec.DecodeContext{}, params.vr, got)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, got.Interface(), protocmp.Transform())
+			})
+		}
+	})
+	t.Run("Formats", func(t *testing.T) {
+		for _, params := range []struct {
+			name   string
+			format protobsonoptions.DateFormat
+			d      *date.Date
+			want   interface{}
+		}{
+			{
+				"DateTime",
+				protobsonoptions.DateFormatDateTime,
+				d,
+				primitive.NewDateTimeFromTime(time.Date(2022, 5, 30, 0, 0, 0, 0, time.UTC)),
+			},
+			{
+				"DateTimeYearOnly",
+				protobsonoptions.DateFormatDateTime,
+				&date.Date{Year: 2022},
+				bson.D{{Key: "year", Value: int32(2022)}, {Key: "month", Value: int32(0)}, {Key: "day", Value: int32(0)}},
+			},
+			{
+				"String",
+				protobsonoptions.DateFormatString,
+				d,
+				"2022-05-30",
+			},
+			{
+				"StringYearMonth",
+				protobsonoptions.DateFormatString,
+				&date.Date{Year: 2022, Month: 5},
+				"2022-05",
+			},
+			{
+				"StringMonthDay",
+				protobsonoptions.DateFormatString,
+				&date.Date{Month: 2, Day: 29},
+				"--02-29",
+			},
+			{
+				"Int",
+				protobsonoptions.DateFormatInt,
+				d,
+				int32(20220530),
+			},
+			{
+				"IntYearOnly",
+				protobsonoptions.DateFormatInt,
+				&date.Date{Year: 2022},
+				int32(20220000),
+			},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				c := NewDateCodec(protobsonoptions.DateCodec().SetFormat(params.format))
+				got := bsontest.EncodeValue(t, c, params.d)
+				assert.DeepEqual(t, bsontest.RawValue(t, params.want), got)
+				dec, err := bsontest.DecodeValue(t, c, got, TypeDate)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.d, dec, protocmp.Transform())
+			})
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		c := NewDateCodec()
+		for _, d := range []*date.Date{
+			{},
+			{Year: 2022, Day: 1},
+			{Month: 5},
+			{Year: 2022, Month: 2, Day: 29},
+			{Year: 2022, Month: 13, Day: 1},
+		} {
+			err := c.EncodeValue(bsoncodec.EncodeContext{}, &bsonrwtest.ValueReaderWriter{}, reflect.ValueOf(d))
+			assert.ErrorContains(t, err, "invalid date")
+		}
+		for _, val := range []interface{}{"2022-5-30", "2022-02-30", int32(20221301)} {
+			_, err := bsontest.DecodeValue(t, c, bsontest.RawValue(t, val), TypeDate)
+			assert.Assert(t, err != nil)
+		}
+		for _, params := range []struct {
+			dt      primitive.DateTime
+			wantErr string
+		}{
+			{primitive.NewDateTimeFromTime(time.Date(2022, 5, 30, 11, 43, 26, 0, time.UTC)), "not a date at midnight UTC"},
+			{primitive.NewDateTimeFromTime(time.Date(2022, 5, 30, 0, 0, 0, 1000000, time.UTC)), "not a date at midnight UTC"},
+			{primitive.NewDateTimeFromTime(time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)), "invalid date"},
+			{primitive.NewDateTimeFromTime(time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)), "invalid date"},
+		} {
+			_, err := bsontest.DecodeValue(t, c, bsontest.RawValue(t, params.dt), TypeDate)
+			assert.ErrorContains(t, err, params.wantErr)
+		}
+	})
+}
@@ -8,6 +8,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/genproto/googleapis/type/datetime"
 	"google.golang.org/protobuf/types/known/durationpb"
 )
@@ -16,7 +18,10 @@ import (
 var TypeDateTime = reflect.TypeOf((*datetime.DateTime)(nil))
 
 // DateTimeCodec is the Codec used for *datetime.DateTime values.
-type DateTimeCodec struct{}
+type DateTimeCodec struct {
+	Format          protobsonoptions.DateTimeFormat
+	DefaultLocation *time.Location
+}
 
 // EncodeValue is the ValueEncoderFunc for *datetime.DateTime.
 func (c *DateTimeCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
@@ -31,11 +36,16 @@ func (c *DateTimeCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueW
 	if dt == nil {
 		return vw.WriteNull()
 	}
-	t, err := dateTimeToTime(dt)
-	if err != nil {
-		return err
+	switch c.Format {
+	case protobsonoptions.DateTimeFormatDocument, protobsonoptions.DateTimeFormatCivil:
+		return c.encodeDocument(vw, dt)
+	default:
+		t, err := dateTimeToTime(dt, c.defaultLocation())
+		if err != nil {
+			return err
+		}
+		return vw.WriteDateTime(t.UnixMilli())
 	}
-	return vw.WriteDateTime(t.UnixMilli())
 }
 
 // DecodeValue is the ValueDecoderFunc for *datetime.DateTime.
@@ -61,6 +71,12 @@ func (c *DateTimeCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueR
 			return err
 		}
 		dt = timeToDateTime(time.UnixMilli(msec).UTC())
+	case bsontype.Timestamp:
+		t, _, err := vr.ReadTimestamp()
+		if err != nil {
+			return err
+		}
+		dt = timeToDateTime(bsonutil.TimestampToTime(t))
 	case bsontype.String:
 		s, err := vr.ReadString()
 		if err != nil {
@@ -71,6 +87,12 @@ func (c *DateTimeCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueR
 			return err
 		}
 		dt = timeToDateTime(t)
+	case bsontype.EmbeddedDocument:
+		var err error
+		dt, err = c.decodeDocument(vr)
+		if err != nil {
+			return err
+		}
 	case bsontype.Null:
 		if err := vr.ReadNull(); err != nil {
 			return err
@@ -88,20 +110,195 @@ func (c *DateTimeCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueR
 	return nil
 }
 
-// NewDateTimeCodec returns a DateTimeCodec.
-func NewDateTimeCodec() *DateTimeCodec {
-	return &DateTimeCodec{}
+// NewDateTimeCodec returns a DateTimeCodec with options opts.
+func NewDateTimeCodec(opts ...*protobsonoptions.DateTimeCodecOptions) *DateTimeCodec {
+	mergedOpts := protobsonoptions.MergeDateTimeCodecOptions(opts...)
+	return &DateTimeCodec{
+		Format:          *mergedOpts.Format,
+		DefaultLocation: mergedOpts.DefaultLocation,
+	}
+}
+
+func (c *DateTimeCodec) encodeDocument(vw bsonrw.ValueWriter, dt *datetime.DateTime) error {
+	if err := validateDateTime(dt); err != nil {
+		return err
+	}
+	t, err := dateTimeToTime(dt, c.defaultLocation())
+	if err != nil {
+		return err
+	}
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+	writeElement := func(key string, write func(vw bsonrw.ValueWriter) error) error {
+		evw, err := dw.WriteDocumentElement(key)
+		if err != nil {
+			return err
+		}
+		return write(evw)
+	}
+	writeString := func(key, s string) error {
+		return writeElement(key, func(vw bsonrw.ValueWriter) error { return vw.WriteString(s) })
+	}
+	writeInt32 := func(key string, i int32) error {
+		return writeElement(key, func(vw bsonrw.ValueWriter) error { return vw.WriteInt32(i) })
+	}
+	if c.Format == protobsonoptions.DateTimeFormatDocument {
+		if err := writeElement("instant", func(vw bsonrw.ValueWriter) error { return vw.WriteDateTime(t.UnixMilli()) }); err != nil {
+			return err
+		}
+		if err := writeInt32("nanos", int32(t.Nanosecond()%nanosPerMilli)); err != nil {
+			return err
+		}
+	}
+	if c.Format == protobsonoptions.DateTimeFormatCivil || dt.TimeOffset == nil {
+		if err := writeString("civil", formatCivilTime(dt)); err != nil {
+			return err
+		}
+	}
+	switch timeOffset := dt.TimeOffset.(type) {
+	case *datetime.DateTime_UtcOffset:
+		if err := writeInt32("offset", int32(timeOffset.UtcOffset.GetSeconds())); err != nil {
+			return err
+		}
+	case *datetime.DateTime_TimeZone:
+		if c.Format == protobsonoptions.DateTimeFormatDocument {
+			_, offset := t.Zone()
+			if err := writeInt32("offset", int32(offset)); err != nil {
+				return err
+			}
+		}
+		if err := writeString("tz", timeOffset.TimeZone.GetId()); err != nil {
+			return err
+		}
+		if version := timeOffset.TimeZone.GetVersion(); version != "" {
+			if err := writeString("tzVersion", version); err != nil {
+				return err
+			}
+		}
+	}
+	return dw.WriteDocumentEnd()
+}
+
+// decodeDocument decodes the documents written with both DateTimeFormatDocument and
+// DateTimeFormatCivil. The civil time takes precedence over the instant when both are present.
+func (c *DateTimeCodec) decodeDocument(vr bsonrw.ValueReader) (*datetime.DateTime, error) {
+	var (
+		instant, offset *int64
+		nanos           int64
+		civil           *string
+		tz              *datetime.TimeZone
+	)
+	err := bsonutil.ReadDocument(vr, func(key string, vr bsonrw.ValueReader) error {
+		switch key {
+		case "instant":
+			msec, err := vr.ReadDateTime()
+			instant = &msec
+			return err
+		case "nanos":
+			var err error
+			nanos, err = bsonutil.ReadInt64(vr)
+			if err == nil && (nanos < 0 || nanos >= nanosPerMilli) {
+				err = fmt.Errorf("nanos %d out of range", nanos)
+			}
+			return err
+		case "offset":
+			seconds, err := bsonutil.ReadInt64(vr)
+			offset = &seconds
+			return err
+		case "civil":
+			s, err := vr.ReadString()
+			civil = &s
+			return err
+		case "tz", "tzVersion":
+			s, err := vr.ReadString()
+			if tz == nil {
+				tz = &datetime.TimeZone{}
+			}
+			if key == "tz" {
+				tz.Id = s
+			} else {
+				tz.Version = s
+			}
+			return err
+		default:
+			return fmt.Errorf("unexpected key %q in a *datetime.DateTime document", key)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	var dt *datetime.DateTime
+	switch {
+	case civil != nil:
+		t, err := time.Parse(civilTimeLayout, *civil)
+		if err != nil {
+			return nil, err
+		}
+		dt = timeToDateTime(t)
+	case instant != nil:
+		loc := c.defaultLocation()
+		switch {
+		case offset != nil:
+			loc = time.FixedZone("", int(*offset))
+		case tz != nil:
+			loc, err = time.LoadLocation(tz.Id)
+			if err != nil {
+				return nil, err
+			}
+		}
+		dt = timeToDateTime(time.UnixMilli(*instant).Add(time.Duration(nanos)).In(loc))
+	default:
+		return nil, fmt.Errorf("missing instant or civil time in a *datetime.DateTime document")
+	}
+	switch {
+	case tz != nil:
+		dt.TimeOffset = &datetime.DateTime_TimeZone{TimeZone: tz}
+	case offset != nil:
+		dt.TimeOffset = &datetime.DateTime_UtcOffset{UtcOffset: durationpb.New(time.Duration(*offset) * time.Second)}
+	default:
+		dt.TimeOffset = nil
+	}
+	return dt, nil
+}
+
+// validateDateTime reports whether the civil time of dt is valid, as time.Date would otherwise
+// silently normalize it.
+func validateDateTime(dt *datetime.DateTime) error {
+	t := time.Date(int(dt.Year), time.Month(dt.Month), int(dt.Day), int(dt.Hours), int(dt.Minutes), int(dt.Seconds), int(dt.Nanos), time.UTC)
+	if formatCivilTime(timeToDateTime(t)) != formatCivilTime(dt) {
+		return fmt.Errorf("invalid date-time %s", formatCivilTime(dt))
+	}
+	return nil
+}
+
+const (
+	nanosPerMilli   = 1000000
+	civilTimeLayout = "2006-01-02T15:04:05.999999999"
+)
+
+func formatCivilTime(dt *datetime.DateTime) string {
+	return fmt.Sprintf("%04d-%02d-%02dT%02d:%02d:%02d.%09d", dt.Year, dt.Month, dt.Day, dt.Hours, dt.Minutes, dt.Seconds, dt.Nanos)
+}
+
+// defaultLocation returns the location of date-times without a time offset, time.UTC unless
+// DefaultLocation is set.
+func (c *DateTimeCodec) defaultLocation() *time.Location {
+	if c.DefaultLocation == nil {
+		return time.UTC
+	}
+	return c.DefaultLocation
 }
 
-func dateTimeToTime(dt *datetime.DateTime) (time.Time, error) {
-	loc := time.Local
+func dateTimeToTime(dt *datetime.DateTime, loc *time.Location) (time.Time, error) {
 	if dt.TimeOffset != nil {
 		switch timeOffset := dt.TimeOffset.(type) {
 		case *datetime.DateTime_UtcOffset:
-			loc = time.FixedZone("", int(timeOffset.UtcOffset.Seconds))
+			loc = time.FixedZone("", int(timeOffset.UtcOffset.GetSeconds()))
 		case *datetime.DateTime_TimeZone:
 			var err error
-			loc, err = time.LoadLocation(timeOffset.TimeZone.Id)
+			loc, err = time.LoadLocation(timeOffset.TimeZone.GetId())
 			if err != nil {
 				return time.Time{}, err
 			}
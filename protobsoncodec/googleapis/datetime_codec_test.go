@@ -5,9 +5,14 @@ import (
 	"testing"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.vallahaye.net/protobson/internal/bsontest"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/genproto/googleapis/type/datetime"
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/durationpb"
@@ -78,6 +83,16 @@ func TestDateTimeCodec(t *testing.T) {
 				},
 				dt,
 			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Timestamp,
+					Return: bsoncore.Value{
+						Type: bsontype.Timestamp,
+						Data: bsoncore.AppendTimestamp(nil, 1653911006, 0),
+					},
+				},
+				dt,
+			},
 			{
 				&bsonrwtest.ValueReaderWriter{
 					BSONType: bsontype.Null,
@@ -100,6 +115,89 @@ func TestDateTimeCodec(t *testing.T) {
 			})
 		}
 	})
+	t.Run("Formats", func(t *testing.T) {
+		paris, err := time.LoadLocation("Europe/Paris")
+		assert.NilError(t, err)
+		civil := func(offset *datetime.DateTime) *datetime.DateTime {
+			return &datetime.DateTime{Year: 2022, Month: 5, Day: 30, Hours: 13, Minutes: 43, Seconds: 26, Nanos: 123456789, TimeOffset: offset.GetTimeOffset()}
+		}
+		instant := primitive.NewDateTimeFromTime(time.Date(2022, 5, 30, 11, 43, 26, 0, time.UTC).Add(123 * time.Millisecond))
+		for _, params := range []struct {
+			name   string
+			format protobsonoptions.DateTimeFormat
+			dt     *datetime.DateTime
+			want   interface{}
+		}{
+			{
+				"DocumentUtcOffset",
+				protobsonoptions.DateTimeFormatDocument,
+				civil(&datetime.DateTime{TimeOffset: &datetime.DateTime_UtcOffset{UtcOffset: durationpb.New(2 * time.Hour)}}),
+				bson.D{{Key: "instant", Value: instant}, {Key: "nanos", Value: int32(456789)}, {Key: "offset", Value: int32(7200)}},
+			},
+			{
+				"DocumentTimeZone",
+				protobsonoptions.DateTimeFormatDocument,
+				civil(&datetime.DateTime{TimeOffset: &datetime.DateTime_TimeZone{TimeZone: &datetime.TimeZone{Id: "Europe/Paris", Version: "2022a"}}}),
+				bson.D{{Key: "instant", Value: instant}, {Key: "nanos", Value: int32(456789)}, {Key: "offset", Value: int32(7200)}, {Key: "tz", Value: "Europe/Paris"}, {Key: "tzVersion", Value: "2022a"}},
+			},
+			{
+				"DocumentCivil",
+				protobsonoptions.DateTimeFormatDocument,
+				civil(nil),
+				bson.D{{Key: "instant", Value: instant}, {Key: "nanos", Value: int32(456789)}, {Key: "civil", Value: "2022-05-30T13:43:26.123456789"}},
+			},
+			{
+				"CivilUtcOffset",
+				protobsonoptions.DateTimeFormatCivil,
+				civil(&datetime.DateTime{TimeOffset: &datetime.DateTime_UtcOffset{UtcOffset: durationpb.New(2 * time.Hour)}}),
+				bson.D{{Key: "civil", Value: "2022-05-30T13:43:26.123456789"}, {Key: "offset", Value: int32(7200)}},
+			},
+			{
+				"CivilTimeZone",
+				protobsonoptions.DateTimeFormatCivil,
+				civil(&datetime.DateTime{TimeOffset: &datetime.DateTime_TimeZone{TimeZone: &datetime.TimeZone{Id: "Europe/Paris"}}}),
+				bson.D{{Key: "civil", Value: "2022-05-30T13:43:26.123456789"}, {Key: "tz", Value: "Europe/Paris"}},
+			},
+			{
+				"CivilCivil",
+				protobsonoptions.DateTimeFormatCivil,
+				civil(nil),
+				bson.D{{Key: "civil", Value: "2022-05-30T13:43:26.123456789"}},
+			},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				c := NewDateTimeCodec(protobsonoptions.DateTimeCodec().SetFormat(params.format).SetDefaultLocation(paris))
+				got := bsontest.EncodeValue(t, c, params.dt)
+				assert.DeepEqual(t, bsontest.RawValue(t, params.want), got)
+				dec, err := bsontest.DecodeValue(t, c, got, TypeDateTime)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.dt, dec, protocmp.Transform())
+			})
+		}
+	})
+	t.Run("DefaultLocation", func(t *testing.T) {
+		dt := &datetime.DateTime{Year: 2022, Month: 5, Day: 30, Hours: 11, Minutes: 43, Seconds: 26}
+		got := bsontest.EncodeValue(t, NewDateTimeCodec(), dt)
+		assert.DeepEqual(t, bsontest.RawValue(t, primitive.DateTime(1653911006000)), got)
+		tokyo, err := time.LoadLocation("Asia/Tokyo")
+		assert.NilError(t, err)
+		got = bsontest.EncodeValue(t, NewDateTimeCodec(protobsonoptions.DateTimeCodec().SetDefaultLocation(tokyo)), dt)
+		assert.DeepEqual(t, bsontest.RawValue(t, primitive.DateTime(1653911006000-9*60*60*1000)), got)
+	})
+	t.Run("ZeroValue", func(t *testing.T) {
+		got := bsontest.EncodeValue(t, &DateTimeCodec{}, dt)
+		assert.DeepEqual(t, bsontest.RawValue(t, primitive.DateTime(1653911006000)), got)
+		decoded, err := bsontest.DecodeValue(t, &DateTimeCodec{}, got, TypeDateTime)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, dt, decoded, protocmp.Transform())
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		c := NewDateTimeCodec(protobsonoptions.DateTimeCodec().SetFormat(protobsonoptions.DateTimeFormatCivil))
+		_, err := bsontest.TryEncodeValue(c, &datetime.DateTime{Year: 2022, Month: 2, Day: 30})
+		assert.ErrorContains(t, err, "invalid date-time")
+		_, err = bsontest.DecodeValue(t, c, bsontest.RawValue(t, bson.D{{Key: "offset", Value: int32(0)}}), TypeDateTime)
+		assert.ErrorContains(t, err, "missing instant or civil time")
+	})
 }
 
 func TestDateTimeToTime(t *testing.T) {
@@ -121,6 +219,18 @@ func TestDateTimeToTime(t *testing.T) {
 			},
 			want: time.Date(2022, 5, 30, 11, 43, 26, 0, time.FixedZone("", 0)),
 		},
+		{
+			dt: &datetime.DateTime{
+				Year:       2022,
+				Month:      5,
+				Day:        30,
+				Hours:      11,
+				Minutes:    43,
+				Seconds:    26,
+				TimeOffset: &datetime.DateTime_UtcOffset{},
+			},
+			want: time.Date(2022, 5, 30, 11, 43, 26, 0, time.FixedZone("", 0)),
+		},
 		{
 			dt: &datetime.DateTime{
 				Year:    2022,
@@ -168,7 +278,7 @@ func TestDateTimeToTime(t *testing.T) {
 			want: time.Date(2022, 5, 30, 13, 43, 26, 0, time.FixedZone("", 2*60*60)),
 		},
 	} {
-		got, err := dateTimeToTime(params.dt)
+		got, err := dateTimeToTime(params.dt, time.UTC)
 		assert.NilError(t, err)
 		assert.DeepEqual(t, params.want, got)
 	}
@@ -0,0 +1,55 @@
+package googleapis
+
+import (
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/type/dayofweek"
+)
+
+// DayOfWeek type.
+var TypeDayOfWeek = reflect.TypeOf(dayofweek.DayOfWeek(0))
+
+// DayOfWeekCodec is the Codec used for dayofweek.DayOfWeek values.
+type DayOfWeekCodec struct {
+	Format protobsonoptions.EnumFormat
+}
+
+// EncodeValue is the ValueEncoderFunc for dayofweek.DayOfWeek.
+func (c *DayOfWeekCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
+	if !v.IsValid() || v.Type() != TypeDayOfWeek {
+		return bsoncodec.ValueEncoderError{
+			Name:     "DayOfWeekCodec.EncodeValue",
+			Types:    []reflect.Type{TypeDayOfWeek},
+			Received: v,
+		}
+	}
+	return writeEnum(vw, c.Format, int32(v.Interface().(dayofweek.DayOfWeek)), dayofweek.DayOfWeek_name)
+}
+
+// DecodeValue is the ValueDecoderFunc for dayofweek.DayOfWeek.
+func (c *DayOfWeekCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) error {
+	if !v.CanSet() || v.Type() != TypeDayOfWeek {
+		return bsoncodec.ValueDecoderError{
+			Name:     "DayOfWeekCodec.DecodeValue",
+			Types:    []reflect.Type{TypeDayOfWeek},
+			Received: v,
+		}
+	}
+	n, err := readEnum(vr, "dayofweek.DayOfWeek", dayofweek.DayOfWeek_name, dayofweek.DayOfWeek_value)
+	if err != nil {
+		return err
+	}
+	v.Set(reflect.ValueOf(dayofweek.DayOfWeek(n)))
+	return nil
+}
+
+// NewDayOfWeekCodec returns a DayOfWeekCodec with options opts.
+func NewDayOfWeekCodec(opts ...*protobsonoptions.DayOfWeekCodecOptions) *DayOfWeekCodec {
+	mergedOpts := protobsonoptions.MergeDayOfWeekCodecOptions(opts...)
+	return &DayOfWeekCodec{
+		Format: *mergedOpts.Format,
+	}
+}
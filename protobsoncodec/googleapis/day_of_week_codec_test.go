@@ -0,0 +1,124 @@
+package googleapis
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsontest"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/type/dayofweek"
+	"gotest.tools/v3/assert"
+)
+
+func TestDayOfWeekCodec(t *testing.T) {
+	t.Run("EncodeToBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			format protobsonoptions.EnumFormat
+			vw     *bsonrwtest.ValueReaderWriter
+			want   bsonrwtest.Invoked
+		}{
+			{
+				protobsonoptions.EnumFormatNumber,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.Int32},
+				bsonrwtest.WriteInt32,
+			},
+			{
+				protobsonoptions.EnumFormatName,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.String},
+				bsonrwtest.WriteString,
+			},
+		} {
+			t.Run(params.vw.Type().String(), func(t *testing.T) {
+				c := NewDayOfWeekCodec(protobsonoptions.DayOfWeekCodec().SetFormat(params.format))
+				v := reflect.ValueOf(dayofweek.DayOfWeek_SUNDAY)
+				err := c.EncodeValue(bsoncodec.EncodeContext{}, params.vw, v)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, params.vw.Invoked)
+			})
+		}
+	})
+	t.Run("DecodeFromBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			vr   *bsonrwtest.ValueReaderWriter
+			want dayofweek.DayOfWeek
+		}{
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Int32,
+					Return:   int32(1),
+				},
+				dayofweek.DayOfWeek_MONDAY,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Int64,
+					Return:   int64(7),
+				},
+				dayofweek.DayOfWeek_SUNDAY,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.String,
+					Return:   "SUNDAY",
+				},
+				dayofweek.DayOfWeek_SUNDAY,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Null,
+				},
+				0,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Undefined,
+				},
+				0,
+			},
+		} {
+			t.Run(params.vr.Type().String(), func(t *testing.T) {
+				c := NewDayOfWeekCodec()
+				got := reflect.New(TypeDayOfWeek).Elem()
+				err := c.DecodeValue(bsoncodec.DecodeContext{}, params.vr, got)
+				assert.NilError(t, err)
+				assert.Equal(t, params.want, got.Interface())
+			})
+		}
+	})
+	t.Run("Formats", func(t *testing.T) {
+		for _, params := range []struct {
+			format protobsonoptions.EnumFormat
+			want   interface{}
+		}{
+			{protobsonoptions.EnumFormatNumber, int32(7)},
+			{protobsonoptions.EnumFormatName, "SUNDAY"},
+		} {
+			c := NewDayOfWeekCodec(protobsonoptions.DayOfWeekCodec().SetFormat(params.format))
+			got := bsontest.EncodeValue(t, c, dayofweek.DayOfWeek_SUNDAY)
+			assert.DeepEqual(t, bsontest.RawValue(t, params.want), got)
+			dec, err := bsontest.DecodeValue(t, c, got, TypeDayOfWeek)
+			assert.NilError(t, err)
+			assert.Equal(t, dayofweek.DayOfWeek_SUNDAY, dec)
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		c := NewDayOfWeekCodec()
+		_, err := bsontest.TryEncodeValue(c, dayofweek.DayOfWeek(13))
+		assert.ErrorContains(t, err, "invalid enum value")
+		for _, val := range []interface{}{int32(13), int32(-1), "MONDAYS", 1.5} {
+			_, err := bsontest.DecodeValue(t, c, bsontest.RawValue(t, val), TypeDayOfWeek)
+			assert.Assert(t, err != nil)
+		}
+		for _, val := range []interface{}{int64(1<<32 + 1), 1.5, 4294967297.0, math.NaN()} {
+			_, err := bsontest.DecodeValue(t, c, bsontest.RawValue(t, val), TypeDayOfWeek)
+			assert.ErrorContains(t, err, "is not a valid")
+		}
+		dec, err := bsontest.DecodeValue(t, c, bsontest.RawValue(t, 5.0), TypeDayOfWeek)
+		assert.NilError(t, err)
+		assert.Equal(t, dayofweek.DayOfWeek_FRIDAY, dec)
+	})
+}
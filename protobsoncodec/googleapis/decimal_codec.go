@@ -0,0 +1,185 @@
+package googleapis
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/genproto/googleapis/type/decimal"
+)
+
+// decimalPattern matches the DecimalString grammar of google.type.Decimal.
+var decimalPattern = regexp.MustCompile(`^([+-]?)(?:(\d+)\.?(\d*)|\.(\d+))(?:[eE]([+-]?\d+))?$`)
+
+// Decimal type.
+var TypeDecimal = reflect.TypeOf((*decimal.Decimal)(nil))
+
+// DecimalCodec is the Codec used for *decimal.Decimal values.
+type DecimalCodec struct{}
+
+// EncodeValue is the ValueEncoderFunc for *decimal.Decimal.
+func (c *DecimalCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
+	if !v.IsValid() || v.Type() != TypeDecimal {
+		return bsoncodec.ValueEncoderError{
+			Name:     "DecimalCodec.EncodeValue",
+			Types:    []reflect.Type{TypeDecimal},
+			Received: v,
+		}
+	}
+	d := v.Interface().(*decimal.Decimal)
+	if d == nil {
+		return vw.WriteNull()
+	}
+	d128, err := parseDecimal(d.Value)
+	if err != nil {
+		return err
+	}
+	return vw.WriteDecimal128(d128)
+}
+
+// DecodeValue is the ValueDecoderFunc for *decimal.Decimal.
+func (c *DecimalCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) error {
+	if !v.CanSet() || v.Type() != TypeDecimal {
+		return bsoncodec.ValueDecoderError{
+			Name:     "DecimalCodec.DecodeValue",
+			Types:    []reflect.Type{TypeDecimal},
+			Received: v,
+		}
+	}
+	var d *decimal.Decimal
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.Decimal128:
+		d128, err := vr.ReadDecimal128()
+		if err != nil {
+			return err
+		}
+		s, err := formatDecimal(d128)
+		if err != nil {
+			return err
+		}
+		d = &decimal.Decimal{Value: s}
+	case bsontype.Double:
+		f, err := vr.ReadDouble()
+		if err != nil {
+			return err
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("cannot decode %v into a *decimal.Decimal", f)
+		}
+		s, err := normalizeDecimal(strconv.FormatFloat(f, 'g', -1, 64))
+		if err != nil {
+			return err
+		}
+		d = &decimal.Decimal{Value: s}
+	case bsontype.Int32:
+		i32, err := vr.ReadInt32()
+		if err != nil {
+			return err
+		}
+		d = &decimal.Decimal{Value: strconv.FormatInt(int64(i32), 10)}
+	case bsontype.Int64:
+		i64, err := vr.ReadInt64()
+		if err != nil {
+			return err
+		}
+		d = &decimal.Decimal{Value: strconv.FormatInt(i64, 10)}
+	case bsontype.String:
+		s, err := vr.ReadString()
+		if err != nil {
+			return err
+		}
+		s, err = normalizeDecimal(s)
+		if err != nil {
+			return err
+		}
+		d = &decimal.Decimal{Value: s}
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+		d = nil
+	case bsontype.Undefined:
+		if err := vr.ReadUndefined(); err != nil {
+			return err
+		}
+		d = &decimal.Decimal{}
+	default:
+		return fmt.Errorf("cannot decode %v into a *decimal.Decimal", bsonTyp)
+	}
+	v.Set(reflect.ValueOf(d))
+	return nil
+}
+
+// NewDecimalCodec returns a DecimalCodec.
+func NewDecimalCodec() *DecimalCodec {
+	return &DecimalCodec{}
+}
+
+// parseDecimal converts a DecimalString into a Decimal128, failing rather than rounding if
+// s has more significant digits or a wider exponent than a Decimal128 can hold.
+func parseDecimal(s string) (primitive.Decimal128, error) {
+	m := decimalPattern.FindStringSubmatch(s)
+	if m == nil {
+		return primitive.Decimal128{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	sign, integer, fraction := m[1], m[2], m[3]
+	if integer == "" {
+		fraction = m[4]
+	}
+	var exp int
+	if m[5] != "" {
+		var err error
+		exp, err = strconv.Atoi(m[5])
+		if err != nil {
+			return primitive.Decimal128{}, fmt.Errorf("decimal %q exponent out of range", s)
+		}
+	}
+	bi, _ := new(big.Int).SetString(sign+integer+fraction, 10)
+	d, ok := primitive.ParseDecimal128FromBigInt(bi, exp-len(fraction))
+	if !ok {
+		return primitive.Decimal128{}, fmt.Errorf("decimal %q exceeds the precision of a Decimal128", s)
+	}
+	return d, nil
+}
+
+// formatDecimal converts d into a normalized DecimalString. Trailing zeros in the fraction are kept.
+func formatDecimal(d primitive.Decimal128) (string, error) {
+	bi, exp, err := d.BigInt()
+	if err != nil {
+		return "", fmt.Errorf("cannot decode %v into a *decimal.Decimal", d)
+	}
+	var sign string
+	if bi.Sign() < 0 {
+		sign = "-"
+		bi.Neg(bi)
+	}
+	digits := bi.String()
+	switch {
+	case exp == 0:
+		return sign + digits, nil
+	case exp > 0 || -exp > len(digits)+34:
+		return sign + digits + "e" + strconv.Itoa(exp), nil
+	}
+	if n := -exp - len(digits) + 1; n > 0 {
+		digits = strings.Repeat("0", n) + digits
+	}
+	point := len(digits) + exp
+	return sign + digits[:point] + "." + digits[point:], nil
+}
+
+// normalizeDecimal validates s and returns its normalized DecimalString.
+func normalizeDecimal(s string) (string, error) {
+	d, err := parseDecimal(s)
+	if err != nil {
+		return "", err
+	}
+	return formatDecimal(d)
+}
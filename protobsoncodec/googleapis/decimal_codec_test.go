@@ -0,0 +1,147 @@
+package googleapis
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsontest"
+	"google.golang.org/genproto/googleapis/type/decimal"
+	"google.golang.org/protobuf/testing/protocmp"
+	"gotest.tools/v3/assert"
+)
+
+func TestDecimalCodec(t *testing.T) {
+	d := &decimal.Decimal{Value: "12.34"}
+	t.Run("EncodeToBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			d    *decimal.Decimal
+			vw   *bsonrwtest.ValueReaderWriter
+			want bsonrwtest.Invoked
+		}{
+			{
+				nil,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.Null},
+				bsonrwtest.WriteNull,
+			},
+			{
+				d,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.Decimal128},
+				bsonrwtest.WriteDecimal128,
+			},
+		} {
+			t.Run(params.vw.Type().String(), func(t *testing.T) {
+				c := NewDecimalCodec()
+				v := reflect.ValueOf(params.d)
+				err := c.EncodeValue(bsoncodec.EncodeContext{}, params.vw, v)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, params.vw.Invoked)
+			})
+		}
+	})
+	t.Run("DecodeFromBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			vr   *bsonrwtest.ValueReaderWriter
+			want *decimal.Decimal
+		}{
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Decimal128,
+					Return:   bsontest.Decimal128("12.34"),
+				},
+				d,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Double,
+					Return:   float64(12.34),
+				},
+				d,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Int32,
+					Return:   int32(12),
+				},
+				&decimal.Decimal{Value: "12"},
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Int64,
+					Return:   int64(-12),
+				},
+				&decimal.Decimal{Value: "-12"},
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.String,
+					Return:   "+12.34",
+				},
+				d,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Null,
+				},
+				nil,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Undefined,
+				},
+				&decimal.Decimal{},
+			},
+		} {
+			t.Run(params.vr.Type().String(), func(t *testing.T) {
+				c := NewDecimalCodec()
+				got := reflect.New(reflect.TypeOf(params.want)).Elem()
+				err := c.DecodeValue(bsoncodec.DecodeContext{}, params.vr, got)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, got.Interface(), protocmp.Transform())
+			})
+		}
+	})
+	t.Run("RoundTrip", func(t *testing.T) {
+		c := NewDecimalCodec()
+		for _, params := range []struct {
+			value string
+			want  string
+		}{
+			{"12.34", "12.34"},
+			{"-0.001", "-0.001"},
+			{".5", "0.5"},
+			{"1.20", "1.20"},
+			{"2.5e0", "2.5"},
+			{"2.5E8", "25e7"},
+			{"1e-40", "1e-40"},
+			{"1234567890123456789012345678901234", "1234567890123456789012345678901234"},
+		} {
+			got := bsontest.EncodeValue(t, c, &decimal.Decimal{Value: params.value})
+			assert.DeepEqual(t, bsontest.RawValue(t, bsontest.Decimal128(params.value)), got)
+			dec, err := bsontest.DecodeValue(t, c, got, TypeDecimal)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, &decimal.Decimal{Value: params.want}, dec, protocmp.Transform())
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		c := NewDecimalCodec()
+		for _, params := range []struct {
+			value string
+			want  string
+		}{
+			{"", "invalid decimal"},
+			{"1,5", "invalid decimal"},
+			{"NaN", "invalid decimal"},
+			{"12345678901234567890123456789012345", "exceeds the precision"},
+			{"1e-6200", "exceeds the precision"},
+			{"1e99999999999999999999", "exponent out of range"},
+		} {
+			_, err := bsontest.TryEncodeValue(c, &decimal.Decimal{Value: params.value})
+			assert.ErrorContains(t, err, params.want)
+		}
+		_, err := bsontest.DecodeValue(t, c, bsontest.RawValue(t, bsontest.Decimal128("NaN")), TypeDecimal)
+		assert.Assert(t, err != nil)
+	})
+}
@@ -0,0 +1,72 @@
+package googleapis
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"go.vallahaye.net/protobson/protobsonoptions"
+)
+
+// writeEnum writes the enum value n, named by names, in format f.
+func writeEnum(vw bsonrw.ValueWriter, f protobsonoptions.EnumFormat, n int32, names map[int32]string) error {
+	name, ok := names[n]
+	if !ok {
+		return fmt.Errorf("invalid enum value %d", n)
+	}
+	if f == protobsonoptions.EnumFormatName {
+		return vw.WriteString(name)
+	}
+	return vw.WriteInt32(n)
+}
+
+// readEnum reads an enum value from its number, which must fit in 32 bits and be integral when
+// stored as a double, or from its case-insensitive name. Null and Undefined are read as the
+// zero value.
+func readEnum(vr bsonrw.ValueReader, typeName string, names map[int32]string, values map[string]int32) (int32, error) {
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.Int32, bsontype.Int64:
+		n, err := bsonutil.ReadInt64(vr)
+		if err != nil {
+			return 0, err
+		}
+		if n != int64(int32(n)) {
+			return 0, fmt.Errorf("%d is not a valid %s", n, typeName)
+		}
+		if _, ok := names[int32(n)]; !ok {
+			return 0, fmt.Errorf("%d is not a valid %s", n, typeName)
+		}
+		return int32(n), nil
+	case bsontype.Double:
+		f, err := vr.ReadDouble()
+		if err != nil {
+			return 0, err
+		}
+		if f != math.Trunc(f) || f < math.MinInt32 || f > math.MaxInt32 {
+			return 0, fmt.Errorf("%v is not a valid %s", f, typeName)
+		}
+		if _, ok := names[int32(f)]; !ok {
+			return 0, fmt.Errorf("%v is not a valid %s", f, typeName)
+		}
+		return int32(f), nil
+	case bsontype.String:
+		s, err := vr.ReadString()
+		if err != nil {
+			return 0, err
+		}
+		n, ok := values[strings.ToUpper(s)]
+		if !ok {
+			return 0, fmt.Errorf("%q is not a valid %s", s, typeName)
+		}
+		return n, nil
+	case bsontype.Null:
+		return 0, vr.ReadNull()
+	case bsontype.Undefined:
+		return 0, vr.ReadUndefined()
+	default:
+		return 0, fmt.Errorf("cannot decode %v into a %s", bsonTyp, typeName)
+	}
+}
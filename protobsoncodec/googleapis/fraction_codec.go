@@ -0,0 +1,155 @@
+package googleapis
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"google.golang.org/genproto/googleapis/type/fraction"
+)
+
+// Fraction type.
+var TypeFraction = reflect.TypeOf((*fraction.Fraction)(nil))
+
+// FractionCodec is the Codec used for *fraction.Fraction values.
+//
+// Fractions are encoded reduced to lowest terms with a positive denominator, as
+// {numerator: <int64>, denominator: <int64>, value: <decimal>} documents. value holds the
+// quotient rounded to 34 significant digits and is only meant for sorting and comparing.
+type FractionCodec struct{}
+
+// EncodeValue is the ValueEncoderFunc for *fraction.Fraction.
+func (c *FractionCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
+	if !v.IsValid() || v.Type() != TypeFraction {
+		return bsoncodec.ValueEncoderError{
+			Name:     "FractionCodec.EncodeValue",
+			Types:    []reflect.Type{TypeFraction},
+			Received: v,
+		}
+	}
+	f := v.Interface().(*fraction.Fraction)
+	if f == nil {
+		return vw.WriteNull()
+	}
+	if f.Denominator == 0 {
+		return fmt.Errorf("invalid fraction %d/0", f.Numerator)
+	}
+	r := big.NewRat(f.Numerator, f.Denominator)
+	if !r.Num().IsInt64() || !r.Denom().IsInt64() {
+		return fmt.Errorf("fraction %d/%d cannot be reduced to 64-bit integers", f.Numerator, f.Denominator)
+	}
+	value, err := fractionValue(r)
+	if err != nil {
+		return err
+	}
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+	for _, key := range []string{"numerator", "denominator"} {
+		evw, err := dw.WriteDocumentElement(key)
+		if err != nil {
+			return err
+		}
+		i := r.Num()
+		if key == "denominator" {
+			i = r.Denom()
+		}
+		if err := evw.WriteInt64(i.Int64()); err != nil {
+			return err
+		}
+	}
+	evw, err := dw.WriteDocumentElement("value")
+	if err != nil {
+		return err
+	}
+	if err := evw.WriteDecimal128(value); err != nil {
+		return err
+	}
+	return dw.WriteDocumentEnd()
+}
+
+// DecodeValue is the ValueDecoderFunc for *fraction.Fraction.
+func (c *FractionCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) error {
+	if !v.CanSet() || v.Type() != TypeFraction {
+		return bsoncodec.ValueDecoderError{
+			Name:     "FractionCodec.DecodeValue",
+			Types:    []reflect.Type{TypeFraction},
+			Received: v,
+		}
+	}
+	var f *fraction.Fraction
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.EmbeddedDocument:
+		f = &fraction.Fraction{}
+		err := bsonutil.ReadDocument(vr, func(key string, vr bsonrw.ValueReader) error {
+			var err error
+			switch key {
+			case "numerator":
+				f.Numerator, err = bsonutil.ReadInt64(vr)
+			case "denominator":
+				f.Denominator, err = bsonutil.ReadInt64(vr)
+			case "value":
+				err = vr.Skip()
+			default:
+				err = fmt.Errorf("unexpected key %q in a *fraction.Fraction document", key)
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if f.Denominator == 0 {
+			return fmt.Errorf("invalid fraction %d/0", f.Numerator)
+		}
+	case bsontype.Int32, bsontype.Int64:
+		n, err := bsonutil.ReadInt64(vr)
+		if err != nil {
+			return err
+		}
+		f = &fraction.Fraction{Numerator: n, Denominator: 1}
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+		f = nil
+	case bsontype.Undefined:
+		if err := vr.ReadUndefined(); err != nil {
+			return err
+		}
+		f = &fraction.Fraction{}
+	default:
+		return fmt.Errorf("cannot decode %v into a *fraction.Fraction", bsonTyp)
+	}
+	v.Set(reflect.ValueOf(f))
+	return nil
+}
+
+// NewFractionCodec returns a FractionCodec.
+func NewFractionCodec() *FractionCodec {
+	return &FractionCodec{}
+}
+
+// fractionValue returns r rounded to 34 significant digits, without trailing zeros.
+func fractionValue(r *big.Rat) (primitive.Decimal128, error) {
+	if r.Sign() == 0 {
+		return bsonutil.DecimalFromBigInt(new(big.Int), 0)
+	}
+	d, err := primitive.ParseDecimal128(new(big.Float).SetPrec(128).SetRat(r).Text('e', 33))
+	if err != nil {
+		return primitive.Decimal128{}, err
+	}
+	bi, exp, err := d.BigInt()
+	if err != nil {
+		return primitive.Decimal128{}, err
+	}
+	if exp >= 0 {
+		return d, nil
+	}
+	return bsonutil.DecimalFromBigInt(bi, -exp)
+}
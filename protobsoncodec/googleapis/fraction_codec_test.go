@@ -0,0 +1,121 @@
+package googleapis
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsontest"
+	"google.golang.org/genproto/googleapis/type/fraction"
+	"google.golang.org/protobuf/testing/protocmp"
+	"gotest.tools/v3/assert"
+)
+
+func TestFractionCodec(t *testing.T) {
+	f := &fraction.Fraction{Numerator: 1, Denominator: 3}
+	t.Run("EncodeToBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			f    *fraction.Fraction
+			vw   *bsonrwtest.ValueReaderWriter
+			want bsonrwtest.Invoked
+		}{
+			{
+				nil,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.Null},
+				bsonrwtest.WriteNull,
+			},
+			{
+				f,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.EmbeddedDocument},
+				bsonrwtest.WriteDocumentEnd,
+			},
+		} {
+			t.Run(params.vw.Type().String(), func(t *testing.T) {
+				c := NewFractionCodec()
+				v := reflect.ValueOf(params.f)
+				err := c.EncodeValue(bsoncodec.EncodeContext{}, params.vw, v)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, params.vw.Invoked)
+			})
+		}
+	})
+	t.Run("DecodeFromBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			vr   *bsonrwtest.ValueReaderWriter
+			want *fraction.Fraction
+		}{
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Int32,
+					Return:   int32(2),
+				},
+				&fraction.Fraction{Numerator: 2, Denominator: 1},
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Null,
+				},
+				nil,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Undefined,
+				},
+				&fraction.Fraction{},
+			},
+		} {
+			t.Run(params.vr.Type().String(), func(t *testing.T) {
+				c := NewFractionCodec()
+				got := reflect.New(reflect.TypeOf(params.want)).Elem()
+				err := c.DecodeValue(bsoncodec.DecodeContext{}, params.vr, got)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, got.Interface(), protocmp.Transform())
+			})
+		}
+	})
+	t.Run("RoundTrip", func(t *testing.T) {
+		c := NewFractionCodec()
+		for _, params := range []struct {
+			f    *fraction.Fraction
+			want *fraction.Fraction
+			doc  bson.D
+		}{
+			{
+				f,
+				f,
+				bson.D{{Key: "numerator", Value: int64(1)}, {Key: "denominator", Value: int64(3)}, {Key: "value", Value: bsontest.Decimal128("0.3333333333333333333333333333333333")}},
+			},
+			{
+				&fraction.Fraction{Numerator: 6, Denominator: -4},
+				&fraction.Fraction{Numerator: -3, Denominator: 2},
+				bson.D{{Key: "numerator", Value: int64(-3)}, {Key: "denominator", Value: int64(2)}, {Key: "value", Value: bsontest.Decimal128("-1.5")}},
+			},
+			{
+				&fraction.Fraction{Numerator: 0, Denominator: 7},
+				&fraction.Fraction{Numerator: 0, Denominator: 1},
+				bson.D{{Key: "numerator", Value: int64(0)}, {Key: "denominator", Value: int64(1)}, {Key: "value", Value: bsontest.Decimal128("0")}},
+			},
+		} {
+			got := bsontest.EncodeValue(t, c, params.f)
+			assert.DeepEqual(t, bsontest.RawValue(t, params.doc), got)
+			dec, err := bsontest.DecodeValue(t, c, got, TypeFraction)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, params.want, dec, protocmp.Transform())
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		c := NewFractionCodec()
+		_, err := bsontest.TryEncodeValue(c, &fraction.Fraction{Numerator: 1})
+		assert.ErrorContains(t, err, "invalid fraction")
+		for _, f := range []*fraction.Fraction{{Numerator: math.MinInt64, Denominator: -1}, {Numerator: 1, Denominator: math.MinInt64}} {
+			_, err = bsontest.TryEncodeValue(c, f)
+			assert.ErrorContains(t, err, "cannot be reduced to 64-bit integers")
+		}
+		_, err = bsontest.DecodeValue(t, c, bsontest.RawValue(t, bson.D{{Key: "numerator", Value: int64(1)}}), TypeFraction)
+		assert.ErrorContains(t, err, "invalid fraction")
+	})
+}
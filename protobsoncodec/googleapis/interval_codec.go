@@ -0,0 +1,198 @@
+package googleapis
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	knowncodec "go.vallahaye.net/protobson/protobsoncodec/known"
+	"google.golang.org/genproto/googleapis/type/interval"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Interval type.
+var TypeInterval = reflect.TypeOf((*interval.Interval)(nil))
+
+// IntervalCodec is the Codec used for *interval.Interval values.
+//
+// Intervals are encoded as {start: <timestamp>, end: <timestamp>} documents describing the
+// half-open range [start, end), whose bounds are encoded by the *timestamppb.Timestamp Codec of
+// the registry, as BSON dates by default. An unspecified bound is encoded as null and leaves the
+// interval unbounded on that side.
+//
+// The IntervalOverlapsFilter and IntervalContainsFilter query filters compare bounds as BSON
+// dates, at millisecond precision, and thus only match intervals whose bounds are stored as
+// BSON dates.
+type IntervalCodec struct{}
+
+// EncodeValue is the ValueEncoderFunc for *interval.Interval.
+func (c *IntervalCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
+	if !v.IsValid() || v.Type() != TypeInterval {
+		return bsoncodec.ValueEncoderError{
+			Name:     "IntervalCodec.EncodeValue",
+			Types:    []reflect.Type{TypeInterval},
+			Received: v,
+		}
+	}
+	iv := v.Interface().(*interval.Interval)
+	if iv == nil {
+		return vw.WriteNull()
+	}
+	if err := validateInterval(iv); err != nil {
+		return err
+	}
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+	for _, bound := range []struct {
+		key string
+		ts  *timestamppb.Timestamp
+	}{
+		{"start", iv.StartTime},
+		{"end", iv.EndTime},
+	} {
+		evw, err := dw.WriteDocumentElement(bound.key)
+		if err != nil {
+			return err
+		}
+		if bound.ts == nil {
+			err = evw.WriteNull()
+		} else {
+			err = encodeTimestamp(ec, evw, bound.ts)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return dw.WriteDocumentEnd()
+}
+
+// DecodeValue is the ValueDecoderFunc for *interval.Interval.
+func (c *IntervalCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) error {
+	if !v.CanSet() || v.Type() != TypeInterval {
+		return bsoncodec.ValueDecoderError{
+			Name:     "IntervalCodec.DecodeValue",
+			Types:    []reflect.Type{TypeInterval},
+			Received: v,
+		}
+	}
+	var iv *interval.Interval
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.EmbeddedDocument:
+		iv = &interval.Interval{}
+		err := bsonutil.ReadDocument(vr, func(key string, vr bsonrw.ValueReader) error {
+			var err error
+			switch key {
+			case "start", "startTime", "start_time":
+				err = decodeTimestamp(dc, vr, &iv.StartTime)
+			case "end", "endTime", "end_time":
+				err = decodeTimestamp(dc, vr, &iv.EndTime)
+			default:
+				err = fmt.Errorf("unexpected key %q in a *interval.Interval document", key)
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if err := validateInterval(iv); err != nil {
+			return err
+		}
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+		iv = nil
+	case bsontype.Undefined:
+		if err := vr.ReadUndefined(); err != nil {
+			return err
+		}
+		iv = &interval.Interval{}
+	default:
+		return fmt.Errorf("cannot decode %v into a *interval.Interval", bsonTyp)
+	}
+	v.Set(reflect.ValueOf(iv))
+	return nil
+}
+
+// NewIntervalCodec returns an IntervalCodec.
+func NewIntervalCodec() *IntervalCodec {
+	return &IntervalCodec{}
+}
+
+// IntervalOverlapsFilter returns a query filter matching documents whose interval stored
+// under field shares at least one instant with iv. A nil iv or unspecified bounds of iv
+// are unbounded.
+func IntervalOverlapsFilter(field string, iv *interval.Interval) bson.D {
+	var conds bson.A
+	if iv.GetEndTime() != nil {
+		conds = append(conds, boundFilter(field+".start", "$lt", iv.EndTime))
+	}
+	if iv.GetStartTime() != nil {
+		conds = append(conds, boundFilter(field+".end", "$gt", iv.StartTime))
+	}
+	return intervalFilter(field, conds...)
+}
+
+// IntervalContainsFilter returns a query filter matching documents whose interval stored
+// under field contains ts.
+func IntervalContainsFilter(field string, ts *timestamppb.Timestamp) bson.D {
+	return intervalFilter(field,
+		boundFilter(field+".start", "$lte", ts),
+		boundFilter(field+".end", "$gt", ts),
+	)
+}
+
+// intervalFilter combines conds with a condition requiring an interval to be stored under field,
+// as unspecified bounds also match missing fields.
+func intervalFilter(field string, conds ...interface{}) bson.D {
+	conds = append(conds, bson.D{{Key: field, Value: bson.D{{Key: "$type", Value: "object"}}}})
+	return bson.D{{Key: "$and", Value: bson.A(conds)}}
+}
+
+// boundFilter matches documents where the bound stored under key is unspecified or compares
+// to ts with operator op.
+func boundFilter(key, op string, ts *timestamppb.Timestamp) bson.D {
+	return bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: key, Value: nil}},
+		bson.D{{Key: key, Value: bson.D{{Key: op, Value: primitive.NewDateTimeFromTime(ts.AsTime())}}}},
+	}}}
+}
+
+func encodeTimestamp(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, ts *timestamppb.Timestamp) error {
+	encoder, err := ec.LookupEncoder(knowncodec.TypeTimestamp)
+	if err != nil {
+		return err
+	}
+	return encoder.EncodeValue(ec, vw, reflect.ValueOf(ts))
+}
+
+func decodeTimestamp(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, ts **timestamppb.Timestamp) error {
+	decoder, err := dc.LookupDecoder(knowncodec.TypeTimestamp)
+	if err != nil {
+		return err
+	}
+	return decoder.DecodeValue(dc, vr, reflect.ValueOf(ts).Elem())
+}
+
+// validateInterval reports whether the bounds of iv are valid timestamps with start <= end.
+func validateInterval(iv *interval.Interval) error {
+	for _, ts := range []*timestamppb.Timestamp{iv.StartTime, iv.EndTime} {
+		if ts == nil {
+			continue
+		}
+		if err := ts.CheckValid(); err != nil {
+			return err
+		}
+	}
+	if iv.StartTime != nil && iv.EndTime != nil && iv.EndTime.AsTime().Before(iv.StartTime.AsTime()) {
+		return fmt.Errorf("invalid interval: end %v is before start %v", iv.EndTime.AsTime(), iv.StartTime.AsTime())
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package googleapis
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.vallahaye.net/protobson/internal/bsontest"
+	knowncodec "go.vallahaye.net/protobson/protobsoncodec/known"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/type/interval"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gotest.tools/v3/assert"
+)
+
+// newIntervalRegistry returns a registry encoding the bounds of intervals with timestamp codec tc.
+func newIntervalRegistry(tc *knowncodec.TimestampCodec) *bsoncodec.Registry {
+	return bson.NewRegistryBuilder().
+		RegisterCodec(TypeInterval, NewIntervalCodec()).
+		RegisterCodec(knowncodec.TypeTimestamp, tc).
+		Build()
+}
+
+// roundTripInterval encodes iv with reg, checks that it is stored as want and decodes it back.
+func roundTripInterval(t *testing.T, reg *bsoncodec.Registry, iv *interval.Interval, want interface{}) {
+	t.Helper()
+	b, err := bson.MarshalWithRegistry(reg, bson.D{{Key: "v", Value: iv}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, bsontest.RawValue(t, want), bson.Raw(b).Lookup("v"))
+	var dec struct {
+		V *interval.Interval `bson:"v"`
+	}
+	assert.NilError(t, bson.UnmarshalWithRegistry(reg, b, &dec))
+	assert.DeepEqual(t, iv, dec.V, protocmp.Transform())
+}
+
+func TestIntervalCodec(t *testing.T) {
+	reg := newIntervalRegistry(knowncodec.NewTimestampCodec())
+	start := time.Date(2022, 5, 30, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2022, 5, 31, 0, 0, 0, 0, time.UTC)
+	iv := &interval.Interval{StartTime: timestamppb.New(start), EndTime: timestamppb.New(end)}
+	t.Run("EncodeToBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			iv   *interval.Interval
+			vw   *bsonrwtest.ValueReaderWriter
+			want bsonrwtest.Invoked
+		}{
+			{
+				nil,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.Null},
+				bsonrwtest.WriteNull,
+			},
+			{
+				iv,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.EmbeddedDocument},
+				bsonrwtest.WriteDocumentEnd,
+			},
+		} {
+			t.Run(params.vw.Type().String(), func(t *testing.T) {
+				c := NewIntervalCodec()
+				v := reflect.ValueOf(params.iv)
+				err := c.EncodeValue(bsoncodec.EncodeContext{Registry: reg}, params.vw, v)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, params.vw.Invoked)
+			})
+		}
+	})
+	t.Run("DecodeFromBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			vr   *bsonrwtest.ValueReaderWriter
+			want *interval.Interval
+		}{
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Null,
+				},
+				nil,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Undefined,
+				},
+				&interval.Interval{},
+			},
+		} {
+			t.Run(params.vr.Type().String(), func(t *testing.T) {
+				c := NewIntervalCodec()
+				got := reflect.New(reflect.TypeOf(params.want)).Elem()
+				err := c.DecodeValue(bsoncodec.DecodeContext{}, params.vr, got)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, got.Interface(), protocmp.Transform())
+			})
+		}
+	})
+	t.Run("RoundTrip", func(t *testing.T) {
+		for _, params := range []struct {
+			name string
+			iv   *interval.Interval
+			want interface{}
+		}{
+			{
+				"Bounded",
+				iv,
+				bson.D{{Key: "start", Value: primitive.NewDateTimeFromTime(start)}, {Key: "end", Value: primitive.NewDateTimeFromTime(end)}},
+			},
+			{
+				"Empty",
+				&interval.Interval{StartTime: timestamppb.New(start), EndTime: timestamppb.New(start)},
+				bson.D{{Key: "start", Value: primitive.NewDateTimeFromTime(start)}, {Key: "end", Value: primitive.NewDateTimeFromTime(start)}},
+			},
+			{
+				"Unbounded",
+				&interval.Interval{StartTime: timestamppb.New(start)},
+				bson.D{{Key: "start", Value: primitive.NewDateTimeFromTime(start)}, {Key: "end", Value: nil}},
+			},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				roundTripInterval(t, reg, params.iv, params.want)
+			})
+		}
+	})
+	t.Run("TimestampCodec", func(t *testing.T) {
+		reg := newIntervalRegistry(knowncodec.NewTimestampCodec(protobsonoptions.TimestampCodec().SetFormat(protobsonoptions.TimestampFormatString)))
+		precise := &interval.Interval{StartTime: timestamppb.New(start.Add(time.Nanosecond)), EndTime: timestamppb.New(end)}
+		roundTripInterval(t, reg, precise, bson.D{{Key: "start", Value: "2022-05-30T00:00:00.000000001Z"}, {Key: "end", Value: "2022-05-31T00:00:00Z"}})
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		reversed := &interval.Interval{StartTime: timestamppb.New(end), EndTime: timestamppb.New(start)}
+		_, err := bson.MarshalWithRegistry(reg, bson.D{{Key: "v", Value: reversed}})
+		assert.ErrorContains(t, err, "invalid interval")
+		b, err := bson.Marshal(bson.D{{Key: "v", Value: bson.D{
+			{Key: "start", Value: primitive.NewDateTimeFromTime(end)},
+			{Key: "end", Value: primitive.NewDateTimeFromTime(start)},
+		}}})
+		assert.NilError(t, err)
+		var dec struct {
+			V *interval.Interval `bson:"v"`
+		}
+		err = bson.UnmarshalWithRegistry(reg, b, &dec)
+		assert.ErrorContains(t, err, "invalid interval")
+	})
+	t.Run("Filters", func(t *testing.T) {
+		mid := primitive.NewDateTimeFromTime(start.Add(12 * time.Hour))
+		isInterval := bson.D{{Key: "period", Value: bson.D{{Key: "$type", Value: "object"}}}}
+		got := IntervalContainsFilter("period", timestamppb.New(start.Add(12*time.Hour)))
+		assert.DeepEqual(t, bson.D{{Key: "$and", Value: bson.A{
+			bson.D{{Key: "$or", Value: bson.A{
+				bson.D{{Key: "period.start", Value: nil}},
+				bson.D{{Key: "period.start", Value: bson.D{{Key: "$lte", Value: mid}}}},
+			}}},
+			bson.D{{Key: "$or", Value: bson.A{
+				bson.D{{Key: "period.end", Value: nil}},
+				bson.D{{Key: "period.end", Value: bson.D{{Key: "$gt", Value: mid}}}},
+			}}},
+			isInterval,
+		}}}, got)
+		got = IntervalOverlapsFilter("period", &interval.Interval{EndTime: timestamppb.New(start.Add(12 * time.Hour))})
+		assert.DeepEqual(t, bson.D{{Key: "$and", Value: bson.A{
+			bson.D{{Key: "$or", Value: bson.A{
+				bson.D{{Key: "period.start", Value: nil}},
+				bson.D{{Key: "period.start", Value: bson.D{{Key: "$lt", Value: mid}}}},
+			}}},
+			isInterval,
+		}}}, got)
+	})
+}
@@ -0,0 +1,213 @@
+package googleapis
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/type/latlng"
+)
+
+// LatLng type.
+var TypeLatLng = reflect.TypeOf((*latlng.LatLng)(nil))
+
+// LatLngCodec is the Codec used for *latlng.LatLng values.
+type LatLngCodec struct {
+	Format protobsonoptions.LatLngFormat
+}
+
+// EncodeValue is the ValueEncoderFunc for *latlng.LatLng.
+func (c *LatLngCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
+	if !v.IsValid() || v.Type() != TypeLatLng {
+		return bsoncodec.ValueEncoderError{
+			Name:     "LatLngCodec.EncodeValue",
+			Types:    []reflect.Type{TypeLatLng},
+			Received: v,
+		}
+	}
+	ll := v.Interface().(*latlng.LatLng)
+	if ll == nil {
+		return vw.WriteNull()
+	}
+	if err := validateLatLng(ll); err != nil {
+		return err
+	}
+	if c.Format == protobsonoptions.LatLngFormatLegacyPair {
+		return writeCoordinates(vw, ll)
+	}
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+	evw, err := dw.WriteDocumentElement("type")
+	if err != nil {
+		return err
+	}
+	if err := evw.WriteString("Point"); err != nil {
+		return err
+	}
+	evw, err = dw.WriteDocumentElement("coordinates")
+	if err != nil {
+		return err
+	}
+	if err := writeCoordinates(evw, ll); err != nil {
+		return err
+	}
+	return dw.WriteDocumentEnd()
+}
+
+// DecodeValue is the ValueDecoderFunc for *latlng.LatLng.
+func (c *LatLngCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) error {
+	if !v.CanSet() || v.Type() != TypeLatLng {
+		return bsoncodec.ValueDecoderError{
+			Name:     "LatLngCodec.DecodeValue",
+			Types:    []reflect.Type{TypeLatLng},
+			Received: v,
+		}
+	}
+	var ll *latlng.LatLng
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.Array:
+		var err error
+		ll, err = readCoordinates(vr)
+		if err != nil {
+			return err
+		}
+	case bsontype.EmbeddedDocument:
+		var err error
+		ll, err = decodeLatLngDocument(vr)
+		if err != nil {
+			return err
+		}
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+		ll = nil
+	case bsontype.Undefined:
+		if err := vr.ReadUndefined(); err != nil {
+			return err
+		}
+		ll = &latlng.LatLng{}
+	default:
+		return fmt.Errorf("cannot decode %v into a *latlng.LatLng", bsonTyp)
+	}
+	if ll != nil {
+		if err := validateLatLng(ll); err != nil {
+			return err
+		}
+	}
+	v.Set(reflect.ValueOf(ll))
+	return nil
+}
+
+// NewLatLngCodec returns a LatLngCodec with options opts.
+func NewLatLngCodec(opts ...*protobsonoptions.LatLngCodecOptions) *LatLngCodec {
+	mergedOpts := protobsonoptions.MergeLatLngCodecOptions(opts...)
+	return &LatLngCodec{
+		Format: *mergedOpts.Format,
+	}
+}
+
+// decodeLatLngDocument decodes both GeoJSON points and the {latitude, longitude} form written
+// by the MessageCodec.
+func decodeLatLngDocument(vr bsonrw.ValueReader) (*latlng.LatLng, error) {
+	var ll *latlng.LatLng
+	var latitude, longitude *float64
+	err := bsonutil.ReadDocument(vr, func(key string, vr bsonrw.ValueReader) error {
+		switch key {
+		case "type":
+			typ, err := vr.ReadString()
+			if err != nil {
+				return err
+			}
+			if typ != "Point" {
+				return fmt.Errorf("cannot decode GeoJSON %s into a *latlng.LatLng", typ)
+			}
+			return nil
+		case "coordinates":
+			var err error
+			ll, err = readCoordinates(vr)
+			return err
+		case "latitude", "longitude":
+			f, err := bsonutil.ReadFloat64(vr)
+			if err != nil {
+				return err
+			}
+			if key == "latitude" {
+				latitude = &f
+			} else {
+				longitude = &f
+			}
+			return nil
+		default:
+			return fmt.Errorf("unexpected key %q in a *latlng.LatLng document", key)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case ll != nil:
+		return ll, nil
+	case latitude != nil && longitude != nil:
+		return &latlng.LatLng{Latitude: *latitude, Longitude: *longitude}, nil
+	default:
+		return nil, fmt.Errorf("missing coordinates in a *latlng.LatLng document")
+	}
+}
+
+func writeCoordinates(vw bsonrw.ValueWriter, ll *latlng.LatLng) error {
+	aw, err := vw.WriteArray()
+	if err != nil {
+		return err
+	}
+	for _, f := range []float64{ll.Longitude, ll.Latitude} {
+		evw, err := aw.WriteArrayElement()
+		if err != nil {
+			return err
+		}
+		if err := evw.WriteDouble(f); err != nil {
+			return err
+		}
+	}
+	return aw.WriteArrayEnd()
+}
+
+func readCoordinates(vr bsonrw.ValueReader) (*latlng.LatLng, error) {
+	ll := &latlng.LatLng{}
+	var n int
+	err := bsonutil.ReadArray(vr, func(i int, vr bsonrw.ValueReader) error {
+		n++
+		f, err := bsonutil.ReadFloat64(vr)
+		if err != nil {
+			return err
+		}
+		switch i {
+		case 0:
+			ll.Longitude = f
+		case 1:
+			ll.Latitude = f
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if n != 2 {
+		return nil, fmt.Errorf("expected [longitude, latitude] coordinates, got %d values", n)
+	}
+	return ll, nil
+}
+
+// validateLatLng reports whether ll is within the WGS84 latitude and longitude ranges.
+func validateLatLng(ll *latlng.LatLng) error {
+	if !(ll.Latitude >= -90 && ll.Latitude <= 90) || !(ll.Longitude >= -180 && ll.Longitude <= 180) {
+		return fmt.Errorf("invalid coordinates latitude %v longitude %v", ll.Latitude, ll.Longitude)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package googleapis
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsontest"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/type/latlng"
+	"google.golang.org/protobuf/testing/protocmp"
+	"gotest.tools/v3/assert"
+)
+
+func TestLatLngCodec(t *testing.T) {
+	ll := &latlng.LatLng{Latitude: 48.8584, Longitude: 2.2945}
+	t.Run("EncodeToBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			ll   *latlng.LatLng
+			vw   *bsonrwtest.ValueReaderWriter
+			want bsonrwtest.Invoked
+		}{
+			{
+				nil,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.Null},
+				bsonrwtest.WriteNull,
+			},
+			{
+				ll,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.EmbeddedDocument},
+				bsonrwtest.WriteDocumentEnd,
+			},
+		} {
+			t.Run(params.vw.Type().String(), func(t *testing.T) {
+				c := NewLatLngCodec()
+				v := reflect.ValueOf(params.ll)
+				err := c.EncodeValue(bsoncodec.EncodeContext{}, params.vw, v)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, params.vw.Invoked)
+			})
+		}
+	})
+	t.Run("DecodeFromBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			vr   *bsonrwtest.ValueReaderWriter
+			want *latlng.LatLng
+		}{
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Null,
+				},
+				nil,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Undefined,
+				},
+				&latlng.LatLng{},
+			},
+		} {
+			t.Run(params.vr.Type().String(), func(t *testing.T) {
+				c := NewLatLngCodec()
+				got := reflect.New(reflect.TypeOf(params.want)).Elem()
+				err := c.DecodeValue(bsoncodec.DecodeContext{}, params.vr, got)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, got.Interface(), protocmp.Transform())
+			})
+		}
+	})
+	t.Run("Formats", func(t *testing.T) {
+		for _, params := range []struct {
+			name   string
+			format protobsonoptions.LatLngFormat
+			want   interface{}
+		}{
+			{
+				"GeoJSON",
+				protobsonoptions.LatLngFormatGeoJSON,
+				bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{2.2945, 48.8584}}},
+			},
+			{
+				"LegacyPair",
+				protobsonoptions.LatLngFormatLegacyPair,
+				bson.A{2.2945, 48.8584},
+			},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				c := NewLatLngCodec(protobsonoptions.LatLngCodec().SetFormat(params.format))
+				got := bsontest.EncodeValue(t, c, ll)
+				assert.DeepEqual(t, bsontest.RawValue(t, params.want), got)
+				dec, err := bsontest.DecodeValue(t, c, got, TypeLatLng)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, ll, dec, protocmp.Transform())
+			})
+		}
+	})
+	t.Run("DecodeShapes", func(t *testing.T) {
+		c := NewLatLngCodec()
+		for _, val := range []interface{}{
+			bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{int32(2), int64(48)}}},
+			bson.A{int32(2), 48.0},
+			bson.D{{Key: "latitude", Value: 48.0}, {Key: "longitude", Value: 2.0}},
+		} {
+			dec, err := bsontest.DecodeValue(t, c, bsontest.RawValue(t, val), TypeLatLng)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, &latlng.LatLng{Latitude: 48, Longitude: 2}, dec, protocmp.Transform())
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		c := NewLatLngCodec()
+		for _, ll := range []*latlng.LatLng{
+			{Latitude: 90.5},
+			{Longitude: -180.5},
+			{Latitude: math.NaN()},
+		} {
+			err := c.EncodeValue(bsoncodec.EncodeContext{}, &bsonrwtest.ValueReaderWriter{}, reflect.ValueOf(ll))
+			assert.ErrorContains(t, err, "invalid coordinates")
+		}
+		for _, val := range []interface{}{
+			bson.A{2.0},
+			bson.A{2.0, 95.0},
+			bson.D{{Key: "type", Value: "LineString"}, {Key: "coordinates", Value: bson.A{bson.A{2.0, 48.0}}}},
+			bson.D{{Key: "latitude", Value: 48.0}},
+		} {
+			_, err := bsontest.DecodeValue(t, c, bsontest.RawValue(t, val), TypeLatLng)
+			assert.Assert(t, err != nil)
+		}
+	})
+}
@@ -0,0 +1,247 @@
+package googleapis
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/type/money"
+)
+
+const nanosPerUnit = 1000000000
+
+// currencyDigits lists the ISO 4217 currencies whose minor unit isn't a hundredth of the major unit.
+var currencyDigits = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// Money type.
+var TypeMoney = reflect.TypeOf((*money.Money)(nil))
+
+// MoneyCodec is the Codec used for *money.Money values.
+type MoneyCodec struct {
+	Format protobsonoptions.MoneyFormat
+}
+
+// EncodeValue is the ValueEncoderFunc for *money.Money.
+func (c *MoneyCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
+	if !v.IsValid() || v.Type() != TypeMoney {
+		return bsoncodec.ValueEncoderError{
+			Name:     "MoneyCodec.EncodeValue",
+			Types:    []reflect.Type{TypeMoney},
+			Received: v,
+		}
+	}
+	m := v.Interface().(*money.Money)
+	if m == nil {
+		return vw.WriteNull()
+	}
+	if err := validateMoney(m); err != nil {
+		return err
+	}
+	nanos := moneyToNanos(m)
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+	evw, err := dw.WriteDocumentElement("currency")
+	if err != nil {
+		return err
+	}
+	if err := evw.WriteString(m.CurrencyCode); err != nil {
+		return err
+	}
+	evw, err = dw.WriteDocumentElement("amount")
+	if err != nil {
+		return err
+	}
+	switch c.Format {
+	case protobsonoptions.MoneyFormatMinorUnits:
+		digits := minorUnitDigits(m.CurrencyCode)
+		minor, rem := new(big.Int).QuoRem(nanos, pow10(9-digits), new(big.Int))
+		if rem.Sign() != 0 || !minor.IsInt64() {
+			return fmt.Errorf("%s cannot be represented as 64-bit integer minor units", formatMoney(m))
+		}
+		err = evw.WriteInt64(minor.Int64())
+	default:
+		var d primitive.Decimal128
+		d, err = bsonutil.DecimalFromBigInt(nanos, 9)
+		if err == nil {
+			err = evw.WriteDecimal128(d)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return dw.WriteDocumentEnd()
+}
+
+// DecodeValue is the ValueDecoderFunc for *money.Money.
+func (c *MoneyCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) error {
+	if !v.CanSet() || v.Type() != TypeMoney {
+		return bsoncodec.ValueDecoderError{
+			Name:     "MoneyCodec.DecodeValue",
+			Types:    []reflect.Type{TypeMoney},
+			Received: v,
+		}
+	}
+	var m *money.Money
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.EmbeddedDocument:
+		var err error
+		m, err = c.decodeDocument(vr)
+		if err != nil {
+			return err
+		}
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+		m = nil
+	case bsontype.Undefined:
+		if err := vr.ReadUndefined(); err != nil {
+			return err
+		}
+		m = &money.Money{}
+	default:
+		return fmt.Errorf("cannot decode %v into a *money.Money", bsonTyp)
+	}
+	v.Set(reflect.ValueOf(m))
+	return nil
+}
+
+// NewMoneyCodec returns a MoneyCodec with options opts.
+func NewMoneyCodec(opts ...*protobsonoptions.MoneyCodecOptions) *MoneyCodec {
+	mergedOpts := protobsonoptions.MergeMoneyCodecOptions(opts...)
+	return &MoneyCodec{
+		Format: *mergedOpts.Format,
+	}
+}
+
+// decodeDocument decodes both the {currency, amount} form and the {currencyCode, units, nanos}
+// form written by the MessageCodec.
+func (c *MoneyCodec) decodeDocument(vr bsonrw.ValueReader) (*money.Money, error) {
+	m := &money.Money{}
+	var amount *big.Int
+	err := bsonutil.ReadDocument(vr, func(key string, vr bsonrw.ValueReader) error {
+		var err error
+		switch key {
+		case "currency", "currencyCode", "currency_code":
+			m.CurrencyCode, err = vr.ReadString()
+		case "amount":
+			amount, err = c.readAmount(vr, m)
+		case "units":
+			m.Units, err = bsonutil.ReadInt64(vr)
+		case "nanos":
+			var nanos int64
+			nanos, err = bsonutil.ReadInt64(vr)
+			m.Nanos = int32(nanos)
+			if nanos != int64(m.Nanos) {
+				err = fmt.Errorf("nanos %d out of range", nanos)
+			}
+		default:
+			err = fmt.Errorf("unexpected key %q in a *money.Money document", key)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if amount != nil {
+		units, nanos := new(big.Int).QuoRem(amount, big.NewInt(nanosPerUnit), new(big.Int))
+		if !units.IsInt64() {
+			return nil, fmt.Errorf("%v units out of range for a *money.Money", units)
+		}
+		m.Units, m.Nanos = units.Int64(), int32(nanos.Int64())
+	}
+	if err := validateMoney(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// readAmount reads an amount and returns it in nanos of the major unit. The currency code of
+// m is used to interpret minor units, which requires it to be decoded first.
+func (c *MoneyCodec) readAmount(vr bsonrw.ValueReader, m *money.Money) (*big.Int, error) {
+	var d primitive.Decimal128
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.Int32, bsontype.Int64:
+		i64, err := bsonutil.ReadInt64(vr)
+		if err != nil {
+			return nil, err
+		}
+		unit := pow10(9)
+		if c.Format == protobsonoptions.MoneyFormatMinorUnits {
+			if m.CurrencyCode == "" {
+				return nil, fmt.Errorf("cannot decode minor units without a preceding currency")
+			}
+			unit = pow10(9 - minorUnitDigits(m.CurrencyCode))
+		}
+		return new(big.Int).Mul(big.NewInt(i64), unit), nil
+	case bsontype.Double:
+		f, err := vr.ReadDouble()
+		if err != nil {
+			return nil, err
+		}
+		d, err = primitive.ParseDecimal128(strconv.FormatFloat(f, 'f', -1, 64))
+		if err != nil {
+			return nil, err
+		}
+	case bsontype.String:
+		s, err := vr.ReadString()
+		if err != nil {
+			return nil, err
+		}
+		d, err = primitive.ParseDecimal128(s)
+		if err != nil {
+			return nil, err
+		}
+	case bsontype.Decimal128:
+		var err error
+		d, err = vr.ReadDecimal128()
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("cannot decode %v into a *money.Money amount", bsonTyp)
+	}
+	return bsonutil.DecimalToBigInt(d, 9)
+}
+
+// validateMoney reports whether the units and nanos of m have consistent signs and nanos are in range.
+func validateMoney(m *money.Money) error {
+	if m.Nanos <= -nanosPerUnit || m.Nanos >= nanosPerUnit || (m.Units > 0 && m.Nanos < 0) || (m.Units < 0 && m.Nanos > 0) {
+		return fmt.Errorf("invalid money %s", formatMoney(m))
+	}
+	return nil
+}
+
+func moneyToNanos(m *money.Money) *big.Int {
+	nanos := new(big.Int).Mul(big.NewInt(m.Units), big.NewInt(nanosPerUnit))
+	return nanos.Add(nanos, big.NewInt(int64(m.Nanos)))
+}
+
+func formatMoney(m *money.Money) string {
+	return fmt.Sprintf("%d units %d nanos %s", m.Units, m.Nanos, m.CurrencyCode)
+}
+
+func minorUnitDigits(currencyCode string) int {
+	if digits, ok := currencyDigits[currencyCode]; ok {
+		return digits
+	}
+	return 2
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
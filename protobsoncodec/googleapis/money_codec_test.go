@@ -0,0 +1,158 @@
+package googleapis
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsontest"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/type/money"
+	"google.golang.org/protobuf/testing/protocmp"
+	"gotest.tools/v3/assert"
+)
+
+func TestMoneyCodec(t *testing.T) {
+	m := &money.Money{CurrencyCode: "EUR", Units: 12, Nanos: 340000000}
+	t.Run("EncodeToBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			m    *money.Money
+			vw   *bsonrwtest.ValueReaderWriter
+			want bsonrwtest.Invoked
+		}{
+			{
+				nil,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.Null},
+				bsonrwtest.WriteNull,
+			},
+			{
+				m,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.EmbeddedDocument},
+				bsonrwtest.WriteDocumentEnd,
+			},
+		} {
+			t.Run(params.vw.Type().String(), func(t *testing.T) {
+				c := NewMoneyCodec()
+				v := reflect.ValueOf(params.m)
+				err := c.EncodeValue(bsoncodec.EncodeContext{}, params.vw, v)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, params.vw.Invoked)
+			})
+		}
+	})
+	t.Run("DecodeFromBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			vr   *bsonrwtest.ValueReaderWriter
+			want *money.Money
+		}{
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Null,
+				},
+				nil,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Undefined,
+				},
+				&money.Money{},
+			},
+		} {
+			t.Run(params.vr.Type().String(), func(t *testing.T) {
+				c := NewMoneyCodec()
+				got := reflect.New(reflect.TypeOf(params.want)).Elem()
+				err := c.DecodeValue(bsoncodec.DecodeContext{}, params.vr, got)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, got.Interface(), protocmp.Transform())
+			})
+		}
+	})
+	t.Run("Formats", func(t *testing.T) {
+		for _, params := range []struct {
+			name   string
+			format protobsonoptions.MoneyFormat
+			m      *money.Money
+			want   interface{}
+		}{
+			{
+				"Decimal128",
+				protobsonoptions.MoneyFormatDecimal128,
+				m,
+				bson.D{{Key: "currency", Value: "EUR"}, {Key: "amount", Value: bsontest.Decimal128("12.34")}},
+			},
+			{
+				"Decimal128Negative",
+				protobsonoptions.MoneyFormatDecimal128,
+				&money.Money{CurrencyCode: "USD", Units: -1, Nanos: -750000000},
+				bson.D{{Key: "currency", Value: "USD"}, {Key: "amount", Value: bsontest.Decimal128("-1.75")}},
+			},
+			{
+				"Decimal128Nanos",
+				protobsonoptions.MoneyFormatDecimal128,
+				&money.Money{CurrencyCode: "USD", Nanos: 1},
+				bson.D{{Key: "currency", Value: "USD"}, {Key: "amount", Value: bsontest.Decimal128("0.000000001")}},
+			},
+			{
+				"MinorUnits",
+				protobsonoptions.MoneyFormatMinorUnits,
+				m,
+				bson.D{{Key: "currency", Value: "EUR"}, {Key: "amount", Value: int64(1234)}},
+			},
+			{
+				"MinorUnitsZeroDigits",
+				protobsonoptions.MoneyFormatMinorUnits,
+				&money.Money{CurrencyCode: "JPY", Units: 500},
+				bson.D{{Key: "currency", Value: "JPY"}, {Key: "amount", Value: int64(500)}},
+			},
+			{
+				"MinorUnitsThreeDigits",
+				protobsonoptions.MoneyFormatMinorUnits,
+				&money.Money{CurrencyCode: "KWD", Units: 1, Nanos: 5000000},
+				bson.D{{Key: "currency", Value: "KWD"}, {Key: "amount", Value: int64(1005)}},
+			},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				c := NewMoneyCodec(protobsonoptions.MoneyCodec().SetFormat(params.format))
+				got := bsontest.EncodeValue(t, c, params.m)
+				assert.DeepEqual(t, bsontest.RawValue(t, params.want), got)
+				dec, err := bsontest.DecodeValue(t, c, got, TypeMoney)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.m, dec, protocmp.Transform())
+			})
+		}
+	})
+	t.Run("DecodeAmounts", func(t *testing.T) {
+		c := NewMoneyCodec()
+		for _, val := range []interface{}{
+			bson.D{{Key: "currency", Value: "EUR"}, {Key: "amount", Value: 12.34}},
+			bson.D{{Key: "currency", Value: "EUR"}, {Key: "amount", Value: "12.34"}},
+			bson.D{{Key: "currencyCode", Value: "EUR"}, {Key: "units", Value: int64(12)}, {Key: "nanos", Value: int32(340000000)}},
+		} {
+			dec, err := bsontest.DecodeValue(t, c, bsontest.RawValue(t, val), TypeMoney)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, m, dec, protocmp.Transform())
+		}
+		dec, err := bsontest.DecodeValue(t, c, bsontest.RawValue(t, bson.D{{Key: "currency", Value: "EUR"}, {Key: "amount", Value: int32(12)}}), TypeMoney)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, &money.Money{CurrencyCode: "EUR", Units: 12}, dec, protocmp.Transform())
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		c := NewMoneyCodec()
+		for _, m := range []*money.Money{
+			{CurrencyCode: "EUR", Units: 1, Nanos: -1},
+			{CurrencyCode: "EUR", Units: -1, Nanos: 1},
+			{CurrencyCode: "EUR", Nanos: 1000000000},
+		} {
+			err := c.EncodeValue(bsoncodec.EncodeContext{}, &bsonrwtest.ValueReaderWriter{}, reflect.ValueOf(m))
+			assert.ErrorContains(t, err, "invalid money")
+		}
+		c = NewMoneyCodec(protobsonoptions.MoneyCodec().SetFormat(protobsonoptions.MoneyFormatMinorUnits))
+		err := c.EncodeValue(bsoncodec.EncodeContext{}, &bsonrwtest.ValueReaderWriter{}, reflect.ValueOf(&money.Money{CurrencyCode: "EUR", Nanos: 1}))
+		assert.ErrorContains(t, err, "minor units")
+		_, err = bsontest.DecodeValue(t, NewMoneyCodec(), bsontest.RawValue(t, bson.D{{Key: "currency", Value: "EUR"}, {Key: "amount", Value: "1e30"}}), TypeMoney)
+		assert.ErrorContains(t, err, "out of range")
+	})
+}
@@ -0,0 +1,55 @@
+package googleapis
+
+import (
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/type/month"
+)
+
+// Month type.
+var TypeMonth = reflect.TypeOf(month.Month(0))
+
+// MonthCodec is the Codec used for month.Month values.
+type MonthCodec struct {
+	Format protobsonoptions.EnumFormat
+}
+
+// EncodeValue is the ValueEncoderFunc for month.Month.
+func (c *MonthCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
+	if !v.IsValid() || v.Type() != TypeMonth {
+		return bsoncodec.ValueEncoderError{
+			Name:     "MonthCodec.EncodeValue",
+			Types:    []reflect.Type{TypeMonth},
+			Received: v,
+		}
+	}
+	return writeEnum(vw, c.Format, int32(v.Interface().(month.Month)), month.Month_name)
+}
+
+// DecodeValue is the ValueDecoderFunc for month.Month.
+func (c *MonthCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) error {
+	if !v.CanSet() || v.Type() != TypeMonth {
+		return bsoncodec.ValueDecoderError{
+			Name:     "MonthCodec.DecodeValue",
+			Types:    []reflect.Type{TypeMonth},
+			Received: v,
+		}
+	}
+	n, err := readEnum(vr, "month.Month", month.Month_name, month.Month_value)
+	if err != nil {
+		return err
+	}
+	v.Set(reflect.ValueOf(month.Month(n)))
+	return nil
+}
+
+// NewMonthCodec returns a MonthCodec with options opts.
+func NewMonthCodec(opts ...*protobsonoptions.MonthCodecOptions) *MonthCodec {
+	mergedOpts := protobsonoptions.MergeMonthCodecOptions(opts...)
+	return &MonthCodec{
+		Format: *mergedOpts.Format,
+	}
+}
@@ -0,0 +1,237 @@
+package googleapis
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"go.vallahaye.net/protobson/protobsoncodec"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// OperationName is the full name of the google.longrunning.Operation message.
+const OperationName protoreflect.FullName = "google.longrunning.Operation"
+
+// OperationCodec is the Codec used for google.longrunning.Operation values.
+//
+// genproto only aliases the Operation type of cloud.google.com/go/longrunning, so the codec
+// works on the message descriptor rather than a Go type and has to be registered for the
+// Operation type in use:
+//
+//	rb.RegisterCodec(reflect.TypeOf((*longrunningpb.Operation)(nil)), googleapis.NewOperationCodec())
+//
+// Operations are encoded as {name: <string>, metadata: <any>, done: <bool>, error: <status>,
+// response: <any>} documents, where metadata and response are expanded like the details of a
+// *status.Status, and error is encoded by the registry. error and response are only present
+// when set. As operations are usually stored as collection documents, an "_id" key is ignored
+// when decoding.
+type OperationCodec struct {
+	Resolver *protoregistry.Types
+}
+
+// EncodeValue is the ValueEncoderFunc for google.longrunning.Operation.
+func (c *OperationCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
+	if !v.IsValid() || !isOperationType(v.Type()) {
+		return bsoncodec.ValueEncoderError{
+			Name:     "OperationCodec.EncodeValue",
+			Types:    []reflect.Type{protobsoncodec.TypeMessage},
+			Received: v,
+		}
+	}
+	if v.IsNil() {
+		return vw.WriteNull()
+	}
+	m := v.Interface().(proto.Message).ProtoReflect()
+	fields := m.Descriptor().Fields()
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+	if err := bsonutil.WriteElement(dw, "name", func(vw bsonrw.ValueWriter) error {
+		return vw.WriteString(m.Get(fields.ByName("name")).String())
+	}); err != nil {
+		return err
+	}
+	if err := bsonutil.WriteElement(dw, "metadata", func(vw bsonrw.ValueWriter) error {
+		return writeAny(ec, vw, anyField(m, fields.ByName("metadata")), c.Resolver)
+	}); err != nil {
+		return err
+	}
+	if err := bsonutil.WriteElement(dw, "done", func(vw bsonrw.ValueWriter) error {
+		return vw.WriteBoolean(m.Get(fields.ByName("done")).Bool())
+	}); err != nil {
+		return err
+	}
+	if fd := fields.ByName("error"); m.Has(fd) {
+		if err := bsonutil.WriteElement(dw, "error", func(vw bsonrw.ValueWriter) error {
+			return encodeStatus(ec, vw, m.Get(fd).Message().Interface().(*status.Status))
+		}); err != nil {
+			return err
+		}
+	}
+	if fd := fields.ByName("response"); m.Has(fd) {
+		if err := bsonutil.WriteElement(dw, "response", func(vw bsonrw.ValueWriter) error {
+			return writeAny(ec, vw, anyField(m, fd), c.Resolver)
+		}); err != nil {
+			return err
+		}
+	}
+	return dw.WriteDocumentEnd()
+}
+
+// DecodeValue is the ValueDecoderFunc for google.longrunning.Operation.
+func (c *OperationCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) error {
+	if !v.CanSet() || !isOperationType(v.Type()) {
+		return bsoncodec.ValueDecoderError{
+			Name:     "OperationCodec.DecodeValue",
+			Types:    []reflect.Type{protobsoncodec.TypeMessage},
+			Received: v,
+		}
+	}
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.EmbeddedDocument:
+	case bsontype.Null:
+		v.Set(reflect.Zero(v.Type()))
+		return vr.ReadNull()
+	case bsontype.Undefined:
+		v.Set(reflect.New(v.Type().Elem()))
+		return vr.ReadUndefined()
+	default:
+		return fmt.Errorf("cannot decode %v into a %s", bsonTyp, OperationName)
+	}
+	op := reflect.New(v.Type().Elem())
+	m := op.Interface().(proto.Message).ProtoReflect()
+	fields := m.Descriptor().Fields()
+	err := bsonutil.ReadDocument(vr, func(key string, vr bsonrw.ValueReader) error {
+		if key == "_id" {
+			return vr.Skip()
+		}
+		fd := fields.ByName(protoreflect.Name(key))
+		if fd == nil {
+			return fmt.Errorf("unexpected key %q in a %s document", key, OperationName)
+		}
+		if vr.Type() == bsontype.Null {
+			return vr.ReadNull()
+		}
+		switch key {
+		case "name":
+			s, err := vr.ReadString()
+			m.Set(fd, protoreflect.ValueOfString(s))
+			return err
+		case "done":
+			b, err := vr.ReadBoolean()
+			m.Set(fd, protoreflect.ValueOfBool(b))
+			return err
+		case "error":
+			var s *status.Status
+			if err := decodeStatus(dc, vr, &s); err != nil {
+				return err
+			}
+			m.Set(fd, protoreflect.ValueOfMessage(s.ProtoReflect()))
+			return nil
+		default:
+			a, err := readAny(dc, vr, c.Resolver)
+			if err != nil {
+				return fmt.Errorf("%s: %w", fd.FullName(), err)
+			}
+			m.Set(fd, protoreflect.ValueOfMessage(a.ProtoReflect()))
+			return nil
+		}
+	})
+	if err != nil {
+		return err
+	}
+	v.Set(op)
+	return nil
+}
+
+// NewOperationCodec returns an OperationCodec with options opts.
+func NewOperationCodec(opts ...*protobsonoptions.OperationCodecOptions) *OperationCodec {
+	mergedOpts := protobsonoptions.MergeOperationCodecOptions(opts...)
+	return &OperationCodec{
+		Resolver: mergedOpts.Resolver,
+	}
+}
+
+// RunningFilter returns a query filter matching the operation named name while it is not done.
+// Combined with ResponseUpdate or ErrorUpdate, it transitions the operation to done atomically.
+func (c *OperationCodec) RunningFilter(name string) bson.D {
+	return bson.D{{Key: "name", Value: name}, {Key: "done", Value: false}}
+}
+
+// ResponseUpdate returns an update document marking an operation as done with response, encoded
+// with reg. response is packed into an *anypb.Any unless it already is one.
+func (c *OperationCodec) ResponseUpdate(reg *bsoncodec.Registry, response proto.Message) (bson.D, error) {
+	a, ok := response.(*anypb.Any)
+	if !ok {
+		var err error
+		a, err = anypb.New(response)
+		if err != nil {
+			return nil, err
+		}
+	}
+	rv, err := bsonutil.EncodeRawValue(reg, func(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter) error {
+		return writeAny(ec, vw, a, c.Resolver)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bson.D{
+		{Key: "$set", Value: bson.D{{Key: "done", Value: true}, {Key: "response", Value: rv}}},
+		{Key: "$unset", Value: bson.D{{Key: "error", Value: ""}}},
+	}, nil
+}
+
+// ErrorUpdate returns an update document marking an operation as done with the error st, encoded
+// with reg.
+func (c *OperationCodec) ErrorUpdate(reg *bsoncodec.Registry, st *status.Status) (bson.D, error) {
+	rv, err := bsonutil.EncodeRawValue(reg, func(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter) error {
+		return encodeStatus(ec, vw, st)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bson.D{
+		{Key: "$set", Value: bson.D{{Key: "done", Value: true}, {Key: "error", Value: rv}}},
+		{Key: "$unset", Value: bson.D{{Key: "response", Value: ""}}},
+	}, nil
+}
+
+func isOperationType(t reflect.Type) bool {
+	if t.Kind() != reflect.Ptr || !t.Implements(protobsoncodec.TypeMessage) {
+		return false
+	}
+	return reflect.Zero(t).Interface().(proto.Message).ProtoReflect().Descriptor().FullName() == OperationName
+}
+
+func anyField(m protoreflect.Message, fd protoreflect.FieldDescriptor) *anypb.Any {
+	if !m.Has(fd) {
+		return nil
+	}
+	return m.Get(fd).Message().Interface().(*anypb.Any)
+}
+
+func encodeStatus(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, s *status.Status) error {
+	encoder, err := ec.LookupEncoder(TypeStatus)
+	if err != nil {
+		return err
+	}
+	return encoder.EncodeValue(ec, vw, reflect.ValueOf(s))
+}
+
+func decodeStatus(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, s **status.Status) error {
+	decoder, err := dc.LookupDecoder(TypeStatus)
+	if err != nil {
+		return err
+	}
+	return decoder.DecodeValue(dc, vr, reflect.ValueOf(s).Elem())
+}
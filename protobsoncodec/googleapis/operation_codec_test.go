@@ -0,0 +1,237 @@
+package googleapis
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.vallahaye.net/protobson/internal/testpb"
+	"go.vallahaye.net/protobson/protobsoncodec"
+	knowncodec "go.vallahaye.net/protobson/protobsoncodec/known"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"gotest.tools/v3/assert"
+)
+
+var typeOperation = reflect.TypeOf((*testpb.Operation)(nil))
+
+func newOperationRegistry() *bsoncodec.Registry {
+	mc := protobsoncodec.NewMessageCodec()
+	return bson.NewRegistryBuilder().
+		RegisterCodec(typeOperation, NewOperationCodec()).
+		RegisterCodec(TypeStatus, NewStatusCodec()).
+		RegisterCodec(knowncodec.TypeStringValue, knowncodec.NewStringValueCodec()).
+		RegisterHookEncoder(protobsoncodec.TypeMessage, mc).
+		RegisterHookDecoder(protobsoncodec.TypeMessage, mc).
+		Build()
+}
+
+func TestOperationCodec(t *testing.T) {
+	t.Run("EncodeToBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			op   *testpb.Operation
+			vw   *bsonrwtest.ValueReaderWriter
+			want bsonrwtest.Invoked
+		}{
+			{
+				nil,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.Null},
+				bsonrwtest.WriteNull,
+			},
+			{
+				&testpb.Operation{Name: "operations/1"},
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.EmbeddedDocument},
+				bsonrwtest.WriteDocumentEnd,
+			},
+		} {
+			t.Run(params.vw.Type().String(), func(t *testing.T) {
+				c := NewOperationCodec()
+				v := reflect.ValueOf(params.op)
+				err := c.EncodeValue(bsoncodec.EncodeContext{}, params.vw, v)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, params.vw.Invoked)
+			})
+		}
+	})
+	t.Run("DecodeFromBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			vr   *bsonrwtest.ValueReaderWriter
+			want *testpb.Operation
+		}{
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Null,
+				},
+				nil,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Undefined,
+				},
+				&testpb.Operation{},
+			},
+		} {
+			t.Run(params.vr.Type().String(), func(t *testing.T) {
+				c := NewOperationCodec()
+				got := reflect.New(typeOperation).Elem()
+				err := c.DecodeValue(bsoncodec.DecodeContext{}, params.vr, got)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, got.Interface(), protocmp.Transform())
+			})
+		}
+	})
+	t.Run("RoundTrip", func(t *testing.T) {
+		reg := newOperationRegistry()
+		for _, params := range []struct {
+			name string
+			op   *testpb.Operation
+			want bson.D
+		}{
+			{
+				"Running",
+				&testpb.Operation{
+					Name:     "operations/1",
+					Metadata: mustNewAny(t, wrapperspb.String("50%")),
+				},
+				bson.D{
+					{Key: "name", Value: "operations/1"},
+					{Key: "metadata", Value: bson.D{
+						{Key: "@type", Value: "type.googleapis.com/google.protobuf.StringValue"},
+						{Key: "value", Value: "50%"},
+					}},
+					{Key: "done", Value: false},
+				},
+			},
+			{
+				"Response",
+				&testpb.Operation{
+					Name:   "operations/2",
+					Done:   true,
+					Result: &testpb.Operation_Response{Response: mustNewAny(t, &errdetails.ResourceInfo{ResourceName: "orders/42"})},
+				},
+				bson.D{
+					{Key: "name", Value: "operations/2"},
+					{Key: "metadata", Value: nil},
+					{Key: "done", Value: true},
+					{Key: "response", Value: bson.D{
+						{Key: "@type", Value: "type.googleapis.com/google.rpc.ResourceInfo"},
+						{Key: "resourceType", Value: ""},
+						{Key: "resourceName", Value: "orders/42"},
+						{Key: "owner", Value: ""},
+						{Key: "description", Value: ""},
+					}},
+				},
+			},
+			{
+				"Error",
+				&testpb.Operation{
+					Name:   "operations/3",
+					Done:   true,
+					Result: &testpb.Operation_Error{Error: &status.Status{Code: 5, Message: "order not found"}},
+				},
+				bson.D{
+					{Key: "name", Value: "operations/3"},
+					{Key: "metadata", Value: nil},
+					{Key: "done", Value: true},
+					{Key: "error", Value: bson.D{
+						{Key: "code", Value: int32(5)},
+						{Key: "code_name", Value: "NOT_FOUND"},
+						{Key: "message", Value: "order not found"},
+						{Key: "details", Value: bson.A{}},
+					}},
+				},
+			},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				b, err := bson.MarshalWithRegistry(reg, bson.D{{Key: "v", Value: params.op}})
+				assert.NilError(t, err)
+				want, err := bson.Marshal(params.want)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, bson.Raw(want), bson.Raw(b).Lookup("v").Document())
+				var dec struct {
+					V *testpb.Operation `bson:"v"`
+				}
+				err = bson.UnmarshalWithRegistry(reg, b, &dec)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.op, dec.V, protocmp.Transform())
+			})
+		}
+	})
+	t.Run("StoredDocument", func(t *testing.T) {
+		reg := newOperationRegistry()
+		want := &testpb.Operation{
+			Name:     "operations/4",
+			Metadata: mustNewAny(t, wrapperspb.String("100%")),
+			Done:     true,
+			Result:   &testpb.Operation_Response{Response: mustNewAny(t, &errdetails.ResourceInfo{ResourceName: "orders/42"})},
+		}
+		// Documents read from a collection start with their _id.
+		doc, err := bson.Marshal(bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "name", Value: "operations/4"},
+			{Key: "metadata", Value: bson.D{
+				{Key: "@type", Value: "type.googleapis.com/google.protobuf.StringValue"},
+				{Key: "value", Value: "100%"},
+			}},
+			{Key: "done", Value: true},
+			{Key: "response", Value: bson.D{
+				{Key: "@type", Value: "type.googleapis.com/google.rpc.ResourceInfo"},
+				{Key: "resourceName", Value: "orders/42"},
+			}},
+		})
+		assert.NilError(t, err)
+		got := &testpb.Operation{}
+		err = bson.UnmarshalWithRegistry(reg, doc, got)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, want, got, protocmp.Transform())
+
+		errOp := &testpb.Operation{Name: "operations/5", Done: true, Result: &testpb.Operation_Error{Error: &status.Status{Code: 5, Details: []*anypb.Any{mustNewAny(t, wrapperspb.String("hint"))}}}}
+		b, err := bson.MarshalWithRegistry(reg, errOp)
+		assert.NilError(t, err)
+		got = &testpb.Operation{}
+		err = bson.UnmarshalWithRegistry(reg, b, got)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, errOp, got, protocmp.Transform())
+	})
+	t.Run("Updates", func(t *testing.T) {
+		reg := newOperationRegistry()
+		c := NewOperationCodec()
+		assert.DeepEqual(t, bson.D{{Key: "name", Value: "operations/1"}, {Key: "done", Value: false}}, c.RunningFilter("operations/1"))
+		update, err := c.ResponseUpdate(reg, wrapperspb.String("ok"))
+		assert.NilError(t, err)
+		response, err := bson.Marshal(bson.D{
+			{Key: "@type", Value: "type.googleapis.com/google.protobuf.StringValue"},
+			{Key: "value", Value: "ok"},
+		})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, bson.D{
+			{Key: "$set", Value: bson.D{{Key: "done", Value: true}, {Key: "response", Value: bson.RawValue{Type: bsontype.EmbeddedDocument, Value: response}}}},
+			{Key: "$unset", Value: bson.D{{Key: "error", Value: ""}}},
+		}, update)
+		update, err = c.ErrorUpdate(reg, &status.Status{Code: 5})
+		assert.NilError(t, err)
+		st, err := bson.Marshal(bson.D{
+			{Key: "code", Value: int32(5)},
+			{Key: "code_name", Value: "NOT_FOUND"},
+			{Key: "message", Value: ""},
+			{Key: "details", Value: bson.A{}},
+		})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, bson.D{
+			{Key: "$set", Value: bson.D{{Key: "done", Value: true}, {Key: "error", Value: bson.RawValue{Type: bsontype.EmbeddedDocument, Value: st}}}},
+			{Key: "$unset", Value: bson.D{{Key: "response", Value: ""}}},
+		}, update)
+	})
+	t.Run("InvalidType", func(t *testing.T) {
+		c := NewOperationCodec()
+		err := c.EncodeValue(bsoncodec.EncodeContext{}, &bsonrwtest.ValueReaderWriter{}, reflect.ValueOf(&status.Status{}))
+		assert.Assert(t, err != nil)
+	})
+}
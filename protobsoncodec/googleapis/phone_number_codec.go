@@ -0,0 +1,225 @@
+package googleapis
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/type/phone_number"
+)
+
+var (
+	e164Pattern      = regexp.MustCompile(`^\+[1-9]\d{1,14}$`)
+	shortCodePattern = regexp.MustCompile(`^\d+$`)
+	phoneSeparators  = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+)
+
+// PhoneNumber type.
+var TypePhoneNumber = reflect.TypeOf((*phone_number.PhoneNumber)(nil))
+
+// PhoneNumberCodec is the Codec used for *phone_number.PhoneNumber values.
+//
+// Phone numbers are encoded as {e164: <string>, extension: <string>} documents, or as
+// {regionCode: <string>, shortCode: <string>, extension: <string>} documents for short codes.
+// Visual separators are stripped from numbers and the extension element is omitted when empty.
+type PhoneNumberCodec struct {
+	SearchKeys bool
+}
+
+// EncodeValue is the ValueEncoderFunc for *phone_number.PhoneNumber.
+func (c *PhoneNumberCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
+	if !v.IsValid() || v.Type() != TypePhoneNumber {
+		return bsoncodec.ValueEncoderError{
+			Name:     "PhoneNumberCodec.EncodeValue",
+			Types:    []reflect.Type{TypePhoneNumber},
+			Received: v,
+		}
+	}
+	pn := v.Interface().(*phone_number.PhoneNumber)
+	if pn == nil {
+		return vw.WriteNull()
+	}
+	pn, err := normalizePhoneNumber(pn)
+	if err != nil {
+		return err
+	}
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+	var elements [][2]string
+	if sc := pn.GetShortCode(); sc != nil {
+		elements = append(elements, [2]string{"regionCode", sc.RegionCode}, [2]string{"shortCode", sc.Number})
+	} else {
+		elements = append(elements, [2]string{"e164", pn.GetE164Number()})
+	}
+	if pn.Extension != "" {
+		elements = append(elements, [2]string{"extension", pn.Extension})
+	}
+	if c.SearchKeys {
+		elements = append(elements, [2]string{"search", phoneNumberURI(pn)})
+	}
+	for _, e := range elements {
+		if err := bsonutil.WriteElement(dw, e[0], func(vw bsonrw.ValueWriter) error { return vw.WriteString(e[1]) }); err != nil {
+			return err
+		}
+	}
+	return dw.WriteDocumentEnd()
+}
+
+// DecodeValue is the ValueDecoderFunc for *phone_number.PhoneNumber.
+func (c *PhoneNumberCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) error {
+	if !v.CanSet() || v.Type() != TypePhoneNumber {
+		return bsoncodec.ValueDecoderError{
+			Name:     "PhoneNumberCodec.DecodeValue",
+			Types:    []reflect.Type{TypePhoneNumber},
+			Received: v,
+		}
+	}
+	var pn *phone_number.PhoneNumber
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.String:
+		s, err := vr.ReadString()
+		if err != nil {
+			return err
+		}
+		number, extension, _ := strings.Cut(s, ";ext=")
+		pn, err = normalizePhoneNumber(&phone_number.PhoneNumber{
+			Kind:      &phone_number.PhoneNumber_E164Number{E164Number: strings.TrimPrefix(number, "tel:")},
+			Extension: extension,
+		})
+		if err != nil {
+			return err
+		}
+	case bsontype.EmbeddedDocument:
+		var err error
+		pn, err = decodePhoneNumberDocument(vr)
+		if err != nil {
+			return err
+		}
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+		pn = nil
+	case bsontype.Undefined:
+		if err := vr.ReadUndefined(); err != nil {
+			return err
+		}
+		pn = &phone_number.PhoneNumber{}
+	default:
+		return fmt.Errorf("cannot decode %v into a *phone_number.PhoneNumber", bsonTyp)
+	}
+	v.Set(reflect.ValueOf(pn))
+	return nil
+}
+
+// NewPhoneNumberCodec returns a PhoneNumberCodec with options opts.
+func NewPhoneNumberCodec(opts ...*protobsonoptions.PhoneNumberCodecOptions) *PhoneNumberCodec {
+	mergedOpts := protobsonoptions.MergePhoneNumberCodecOptions(opts...)
+	return &PhoneNumberCodec{
+		SearchKeys: *mergedOpts.SearchKeys,
+	}
+}
+
+// decodePhoneNumberDocument decodes both the normalized documents and the documents written by the
+// MessageCodec.
+func decodePhoneNumberDocument(vr bsonrw.ValueReader) (*phone_number.PhoneNumber, error) {
+	pn := &phone_number.PhoneNumber{}
+	var sc *phone_number.PhoneNumber_ShortCode
+	shortCode := func() *phone_number.PhoneNumber_ShortCode {
+		if sc == nil {
+			sc = &phone_number.PhoneNumber_ShortCode{}
+		}
+		return sc
+	}
+	err := bsonutil.ReadDocument(vr, func(key string, vr bsonrw.ValueReader) error {
+		var err error
+		switch key {
+		case "e164", "e164Number", "e164_number":
+			var s string
+			s, err = vr.ReadString()
+			pn.Kind = &phone_number.PhoneNumber_E164Number{E164Number: s}
+		case "regionCode":
+			shortCode().RegionCode, err = vr.ReadString()
+		case "shortCode", "short_code":
+			if vr.Type() == bsontype.EmbeddedDocument {
+				err = bsonutil.ReadDocument(vr, func(key string, vr bsonrw.ValueReader) error {
+					var err error
+					switch key {
+					case "regionCode", "region_code":
+						shortCode().RegionCode, err = vr.ReadString()
+					case "number":
+						shortCode().Number, err = vr.ReadString()
+					default:
+						err = fmt.Errorf("unexpected key %q in a *phone_number.PhoneNumber_ShortCode document", key)
+					}
+					return err
+				})
+			} else {
+				shortCode().Number, err = vr.ReadString()
+			}
+		case "extension":
+			pn.Extension, err = vr.ReadString()
+		case "search":
+			err = vr.Skip()
+		default:
+			err = fmt.Errorf("unexpected key %q in a *phone_number.PhoneNumber document", key)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if sc != nil {
+		if pn.Kind != nil {
+			return nil, fmt.Errorf("phone number cannot be both an E.164 number and a short code")
+		}
+		pn.Kind = &phone_number.PhoneNumber_ShortCode_{ShortCode: sc}
+	}
+	return normalizePhoneNumber(pn)
+}
+
+// normalizePhoneNumber returns a copy of pn with visual separators stripped from its number,
+// an uppercase region code and a trimmed extension, failing if the number is invalid.
+func normalizePhoneNumber(pn *phone_number.PhoneNumber) (*phone_number.PhoneNumber, error) {
+	out := &phone_number.PhoneNumber{Extension: strings.TrimSpace(pn.Extension)}
+	switch kind := pn.Kind.(type) {
+	case *phone_number.PhoneNumber_E164Number:
+		number := phoneSeparators.Replace(kind.E164Number)
+		if !e164Pattern.MatchString(number) {
+			return nil, fmt.Errorf("invalid E.164 phone number %q", kind.E164Number)
+		}
+		out.Kind = &phone_number.PhoneNumber_E164Number{E164Number: number}
+	case *phone_number.PhoneNumber_ShortCode_:
+		sc := &phone_number.PhoneNumber_ShortCode{
+			RegionCode: strings.ToUpper(strings.TrimSpace(kind.ShortCode.GetRegionCode())),
+			Number:     phoneSeparators.Replace(kind.ShortCode.GetNumber()),
+		}
+		if sc.RegionCode == "" || !shortCodePattern.MatchString(sc.Number) {
+			return nil, fmt.Errorf("invalid short code %q in region %q", kind.ShortCode.GetNumber(), kind.ShortCode.GetRegionCode())
+		}
+		out.Kind = &phone_number.PhoneNumber_ShortCode_{ShortCode: sc}
+	default:
+		return nil, fmt.Errorf("phone number is neither an E.164 number nor a short code")
+	}
+	return out, nil
+}
+
+// phoneNumberURI returns the lowercase RFC 3966 URI of the normalized phone number pn.
+func phoneNumberURI(pn *phone_number.PhoneNumber) string {
+	uri := "tel:" + pn.GetE164Number()
+	if sc := pn.GetShortCode(); sc != nil {
+		uri = "tel:" + sc.Number + ";phone-context=" + sc.RegionCode
+	}
+	if pn.Extension != "" {
+		uri += ";ext=" + pn.Extension
+	}
+	return strings.ToLower(uri)
+}
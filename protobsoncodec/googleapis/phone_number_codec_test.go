@@ -0,0 +1,159 @@
+package googleapis
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsontest"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/type/phone_number"
+	"google.golang.org/protobuf/testing/protocmp"
+	"gotest.tools/v3/assert"
+)
+
+func TestPhoneNumberCodec(t *testing.T) {
+	pn := &phone_number.PhoneNumber{
+		Kind:      &phone_number.PhoneNumber_E164Number{E164Number: "+15552220123"},
+		Extension: "123",
+	}
+	sc := &phone_number.PhoneNumber{
+		Kind: &phone_number.PhoneNumber_ShortCode_{ShortCode: &phone_number.PhoneNumber_ShortCode{RegionCode: "US", Number: "611"}},
+	}
+	t.Run("EncodeToBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			pn   *phone_number.PhoneNumber
+			vw   *bsonrwtest.ValueReaderWriter
+			want bsonrwtest.Invoked
+		}{
+			{
+				nil,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.Null},
+				bsonrwtest.WriteNull,
+			},
+			{
+				pn,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.EmbeddedDocument},
+				bsonrwtest.WriteDocumentEnd,
+			},
+		} {
+			t.Run(params.vw.Type().String(), func(t *testing.T) {
+				c := NewPhoneNumberCodec()
+				v := reflect.ValueOf(params.pn)
+				err := c.EncodeValue(bsoncodec.EncodeContext{}, params.vw, v)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, params.vw.Invoked)
+			})
+		}
+	})
+	t.Run("DecodeFromBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			vr   *bsonrwtest.ValueReaderWriter
+			want *phone_number.PhoneNumber
+		}{
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.String,
+					Return:   "tel:+1-555-222-0123;ext=123",
+				},
+				pn,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Null,
+				},
+				nil,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Undefined,
+				},
+				&phone_number.PhoneNumber{},
+			},
+		} {
+			t.Run(params.vr.Type().String(), func(t *testing.T) {
+				c := NewPhoneNumberCodec()
+				got := reflect.New(reflect.TypeOf(params.want)).Elem()
+				err := c.DecodeValue(bsoncodec.DecodeContext{}, params.vr, got)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, got.Interface(), protocmp.Transform())
+			})
+		}
+	})
+	t.Run("RoundTrip", func(t *testing.T) {
+		for _, params := range []struct {
+			name       string
+			searchKeys bool
+			pn         *phone_number.PhoneNumber
+			want       bson.D
+		}{
+			{
+				"E164",
+				false,
+				pn,
+				bson.D{{Key: "e164", Value: "+15552220123"}, {Key: "extension", Value: "123"}},
+			},
+			{
+				"ShortCode",
+				false,
+				sc,
+				bson.D{{Key: "regionCode", Value: "US"}, {Key: "shortCode", Value: "611"}},
+			},
+			{
+				"E164SearchKeys",
+				true,
+				pn,
+				bson.D{{Key: "e164", Value: "+15552220123"}, {Key: "extension", Value: "123"}, {Key: "search", Value: "tel:+15552220123;ext=123"}},
+			},
+			{
+				"ShortCodeSearchKeys",
+				true,
+				sc,
+				bson.D{{Key: "regionCode", Value: "US"}, {Key: "shortCode", Value: "611"}, {Key: "search", Value: "tel:611;phone-context=us"}},
+			},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				c := NewPhoneNumberCodec(protobsonoptions.PhoneNumberCodec().SetSearchKeys(params.searchKeys))
+				got := bsontest.EncodeValue(t, c, params.pn)
+				assert.DeepEqual(t, bsontest.RawValue(t, params.want), got)
+				dec, err := bsontest.DecodeValue(t, c, got, TypePhoneNumber)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.pn, dec, protocmp.Transform())
+			})
+		}
+	})
+	t.Run("Normalize", func(t *testing.T) {
+		c := NewPhoneNumberCodec()
+		got := bsontest.EncodeValue(t, c, &phone_number.PhoneNumber{
+			Kind:      &phone_number.PhoneNumber_E164Number{E164Number: "+1 (555) 222-0123"},
+			Extension: " 123 ",
+		})
+		assert.DeepEqual(t, bsontest.RawValue(t, bson.D{{Key: "e164", Value: "+15552220123"}, {Key: "extension", Value: "123"}}), got)
+		dec, err := bsontest.DecodeValue(t, c, bsontest.RawValue(t, bson.D{
+			{Key: "shortCode", Value: bson.D{{Key: "regionCode", Value: "us"}, {Key: "number", Value: "611"}}},
+		}), TypePhoneNumber)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, sc, dec, protocmp.Transform())
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		c := NewPhoneNumberCodec()
+		for _, pn := range []*phone_number.PhoneNumber{
+			{},
+			{Kind: &phone_number.PhoneNumber_E164Number{E164Number: "5552220123"}},
+			{Kind: &phone_number.PhoneNumber_E164Number{E164Number: "+1555222012345678"}},
+			{Kind: &phone_number.PhoneNumber_ShortCode_{ShortCode: &phone_number.PhoneNumber_ShortCode{Number: "611"}}},
+		} {
+			_, err := bsontest.TryEncodeValue(c, pn)
+			assert.Assert(t, err != nil)
+		}
+		_, err := bsontest.DecodeValue(t, c, bsontest.RawValue(t, bson.D{
+			{Key: "e164", Value: "+15552220123"},
+			{Key: "regionCode", Value: "US"},
+			{Key: "shortCode", Value: "611"},
+		}), TypePhoneNumber)
+		assert.ErrorContains(t, err, "both")
+	})
+}
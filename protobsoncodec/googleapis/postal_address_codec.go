@@ -0,0 +1,252 @@
+package googleapis
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/type/postaladdress"
+)
+
+// PostalAddress type.
+var TypePostalAddress = reflect.TypeOf((*postaladdress.PostalAddress)(nil))
+
+// PostalAddressCodec is the Codec used for *postaladdress.PostalAddress values.
+//
+// Addresses are encoded as documents keyed by their uppercase regionCode, holding the address
+// lines in a lines array and the other components as strings, omitted when empty. Whitespace
+// is trimmed and collapsed in all components, and postal and sorting codes are uppercased.
+type PostalAddressCodec struct {
+	SearchKeys bool
+}
+
+// EncodeValue is the ValueEncoderFunc for *postaladdress.PostalAddress.
+func (c *PostalAddressCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
+	if !v.IsValid() || v.Type() != TypePostalAddress {
+		return bsoncodec.ValueEncoderError{
+			Name:     "PostalAddressCodec.EncodeValue",
+			Types:    []reflect.Type{TypePostalAddress},
+			Received: v,
+		}
+	}
+	pa := v.Interface().(*postaladdress.PostalAddress)
+	if pa == nil {
+		return vw.WriteNull()
+	}
+	pa, err := normalizePostalAddress(pa)
+	if err != nil {
+		return err
+	}
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+	if err := writePostalAddressString(dw, "regionCode", pa.RegionCode); err != nil {
+		return err
+	}
+	if pa.Revision != 0 {
+		if err := bsonutil.WriteElement(dw, "revision", func(vw bsonrw.ValueWriter) error { return vw.WriteInt32(pa.Revision) }); err != nil {
+			return err
+		}
+	}
+	for _, e := range postalAddressComponents(pa) {
+		if err := writePostalAddressString(dw, e.key, e.value); err != nil {
+			return err
+		}
+	}
+	if err := bsonutil.WriteElement(dw, "lines", func(vw bsonrw.ValueWriter) error { return bsonutil.WriteStrings(vw, pa.AddressLines) }); err != nil {
+		return err
+	}
+	if len(pa.Recipients) > 0 {
+		if err := bsonutil.WriteElement(dw, "recipients", func(vw bsonrw.ValueWriter) error { return bsonutil.WriteStrings(vw, pa.Recipients) }); err != nil {
+			return err
+		}
+	}
+	if err := writePostalAddressString(dw, "organization", pa.Organization); err != nil {
+		return err
+	}
+	if c.SearchKeys {
+		if err := bsonutil.WriteElement(dw, "search", func(vw bsonrw.ValueWriter) error { return writePostalAddressSearchKeys(vw, pa) }); err != nil {
+			return err
+		}
+	}
+	return dw.WriteDocumentEnd()
+}
+
+// DecodeValue is the ValueDecoderFunc for *postaladdress.PostalAddress.
+func (c *PostalAddressCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) error {
+	if !v.CanSet() || v.Type() != TypePostalAddress {
+		return bsoncodec.ValueDecoderError{
+			Name:     "PostalAddressCodec.DecodeValue",
+			Types:    []reflect.Type{TypePostalAddress},
+			Received: v,
+		}
+	}
+	var pa *postaladdress.PostalAddress
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.EmbeddedDocument:
+		var err error
+		pa, err = decodePostalAddressDocument(vr)
+		if err != nil {
+			return err
+		}
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+		pa = nil
+	case bsontype.Undefined:
+		if err := vr.ReadUndefined(); err != nil {
+			return err
+		}
+		pa = &postaladdress.PostalAddress{}
+	default:
+		return fmt.Errorf("cannot decode %v into a *postaladdress.PostalAddress", bsonTyp)
+	}
+	v.Set(reflect.ValueOf(pa))
+	return nil
+}
+
+// NewPostalAddressCodec returns a PostalAddressCodec with options opts.
+func NewPostalAddressCodec(opts ...*protobsonoptions.PostalAddressCodecOptions) *PostalAddressCodec {
+	mergedOpts := protobsonoptions.MergePostalAddressCodecOptions(opts...)
+	return &PostalAddressCodec{
+		SearchKeys: *mergedOpts.SearchKeys,
+	}
+}
+
+type postalAddressComponent struct {
+	key   string
+	value string
+}
+
+// postalAddressComponents returns the single-valued string components of pa other than the
+// region and organization, in encoding order.
+func postalAddressComponents(pa *postaladdress.PostalAddress) []postalAddressComponent {
+	return []postalAddressComponent{
+		{"languageCode", pa.LanguageCode},
+		{"postalCode", pa.PostalCode},
+		{"sortingCode", pa.SortingCode},
+		{"administrativeArea", pa.AdministrativeArea},
+		{"locality", pa.Locality},
+		{"sublocality", pa.Sublocality},
+	}
+}
+
+// decodePostalAddressDocument decodes both the normalized documents and the documents written by
+// the MessageCodec.
+func decodePostalAddressDocument(vr bsonrw.ValueReader) (*postaladdress.PostalAddress, error) {
+	pa := &postaladdress.PostalAddress{}
+	err := bsonutil.ReadDocument(vr, func(key string, vr bsonrw.ValueReader) error {
+		var err error
+		switch key {
+		case "regionCode", "region_code":
+			pa.RegionCode, err = vr.ReadString()
+		case "revision":
+			var i64 int64
+			i64, err = bsonutil.ReadInt64(vr)
+			pa.Revision = int32(i64)
+		case "languageCode", "language_code":
+			pa.LanguageCode, err = vr.ReadString()
+		case "postalCode", "postal_code":
+			pa.PostalCode, err = vr.ReadString()
+		case "sortingCode", "sorting_code":
+			pa.SortingCode, err = vr.ReadString()
+		case "administrativeArea", "administrative_area":
+			pa.AdministrativeArea, err = vr.ReadString()
+		case "locality":
+			pa.Locality, err = vr.ReadString()
+		case "sublocality":
+			pa.Sublocality, err = vr.ReadString()
+		case "lines", "addressLines", "address_lines":
+			pa.AddressLines, err = bsonutil.ReadStrings(vr)
+		case "recipients":
+			pa.Recipients, err = bsonutil.ReadStrings(vr)
+		case "organization":
+			pa.Organization, err = vr.ReadString()
+		case "search":
+			err = vr.Skip()
+		default:
+			err = fmt.Errorf("unexpected key %q in a *postaladdress.PostalAddress document", key)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return normalizePostalAddress(pa)
+}
+
+// normalizePostalAddress returns a normalized copy of pa, failing if it has no region code.
+func normalizePostalAddress(pa *postaladdress.PostalAddress) (*postaladdress.PostalAddress, error) {
+	out := &postaladdress.PostalAddress{
+		Revision:           pa.Revision,
+		RegionCode:         strings.ToUpper(normalizeSpace(pa.RegionCode)),
+		LanguageCode:       normalizeSpace(pa.LanguageCode),
+		PostalCode:         strings.ToUpper(normalizeSpace(pa.PostalCode)),
+		SortingCode:        strings.ToUpper(normalizeSpace(pa.SortingCode)),
+		AdministrativeArea: normalizeSpace(pa.AdministrativeArea),
+		Locality:           normalizeSpace(pa.Locality),
+		Sublocality:        normalizeSpace(pa.Sublocality),
+		Organization:       normalizeSpace(pa.Organization),
+	}
+	if out.RegionCode == "" {
+		return nil, fmt.Errorf("postal address has no region code")
+	}
+	for _, line := range pa.AddressLines {
+		if line = normalizeSpace(line); line != "" {
+			out.AddressLines = append(out.AddressLines, line)
+		}
+	}
+	for _, recipient := range pa.Recipients {
+		if recipient = normalizeSpace(recipient); recipient != "" {
+			out.Recipients = append(out.Recipients, recipient)
+		}
+	}
+	return out, nil
+}
+
+func writePostalAddressString(dw bsonrw.DocumentWriter, key, s string) error {
+	if s == "" {
+		return nil
+	}
+	return bsonutil.WriteElement(dw, key, func(vw bsonrw.ValueWriter) error { return vw.WriteString(s) })
+}
+
+// writePostalAddressSearchKeys writes a document holding lowercase copies of the searchable
+// components of pa.
+func writePostalAddressSearchKeys(vw bsonrw.ValueWriter, pa *postaladdress.PostalAddress) error {
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+	for _, e := range postalAddressComponents(pa) {
+		if e.key == "languageCode" {
+			continue
+		}
+		if err := writePostalAddressString(dw, e.key, strings.ToLower(e.value)); err != nil {
+			return err
+		}
+	}
+	lines := make([]string, len(pa.AddressLines))
+	for i, line := range pa.AddressLines {
+		lines[i] = strings.ToLower(line)
+	}
+	if err := bsonutil.WriteElement(dw, "lines", func(vw bsonrw.ValueWriter) error { return bsonutil.WriteStrings(vw, lines) }); err != nil {
+		return err
+	}
+	if err := writePostalAddressString(dw, "organization", strings.ToLower(pa.Organization)); err != nil {
+		return err
+	}
+	return dw.WriteDocumentEnd()
+}
+
+// normalizeSpace trims s and collapses its inner whitespace into single spaces.
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
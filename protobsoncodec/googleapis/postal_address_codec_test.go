@@ -0,0 +1,130 @@
+package googleapis
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsontest"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/type/postaladdress"
+	"google.golang.org/protobuf/testing/protocmp"
+	"gotest.tools/v3/assert"
+)
+
+func TestPostalAddressCodec(t *testing.T) {
+	pa := &postaladdress.PostalAddress{
+		RegionCode:   "CH",
+		LanguageCode: "de-CH",
+		PostalCode:   "8002",
+		Locality:     "Zürich",
+		AddressLines: []string{"Brandschenkestrasse 110"},
+		Recipients:   []string{"Jane Doe"},
+		Organization: "Google",
+	}
+	want := bson.D{
+		{Key: "regionCode", Value: "CH"},
+		{Key: "languageCode", Value: "de-CH"},
+		{Key: "postalCode", Value: "8002"},
+		{Key: "locality", Value: "Zürich"},
+		{Key: "lines", Value: bson.A{"Brandschenkestrasse 110"}},
+		{Key: "recipients", Value: bson.A{"Jane Doe"}},
+		{Key: "organization", Value: "Google"},
+	}
+	t.Run("EncodeToBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			pa   *postaladdress.PostalAddress
+			vw   *bsonrwtest.ValueReaderWriter
+			want bsonrwtest.Invoked
+		}{
+			{
+				nil,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.Null},
+				bsonrwtest.WriteNull,
+			},
+			{
+				pa,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.EmbeddedDocument},
+				bsonrwtest.WriteDocumentEnd,
+			},
+		} {
+			t.Run(params.vw.Type().String(), func(t *testing.T) {
+				c := NewPostalAddressCodec()
+				v := reflect.ValueOf(params.pa)
+				err := c.EncodeValue(bsoncodec.EncodeContext{}, params.vw, v)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, params.vw.Invoked)
+			})
+		}
+	})
+	t.Run("DecodeFromBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			vr   *bsonrwtest.ValueReaderWriter
+			want *postaladdress.PostalAddress
+		}{
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Null,
+				},
+				nil,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Undefined,
+				},
+				&postaladdress.PostalAddress{},
+			},
+		} {
+			t.Run(params.vr.Type().String(), func(t *testing.T) {
+				c := NewPostalAddressCodec()
+				got := reflect.New(reflect.TypeOf(params.want)).Elem()
+				err := c.DecodeValue(bsoncodec.DecodeContext{}, params.vr, got)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, got.Interface(), protocmp.Transform())
+			})
+		}
+	})
+	t.Run("RoundTrip", func(t *testing.T) {
+		c := NewPostalAddressCodec()
+		got := bsontest.EncodeValue(t, c, pa)
+		assert.DeepEqual(t, bsontest.RawValue(t, want), got)
+		dec, err := bsontest.DecodeValue(t, c, got, TypePostalAddress)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, pa, dec, protocmp.Transform())
+	})
+	t.Run("SearchKeys", func(t *testing.T) {
+		c := NewPostalAddressCodec(protobsonoptions.PostalAddressCodec().SetSearchKeys(true))
+		got := bsontest.EncodeValue(t, c, pa)
+		assert.DeepEqual(t, bsontest.RawValue(t, append(want, bson.E{Key: "search", Value: bson.D{
+			{Key: "postalCode", Value: "8002"},
+			{Key: "locality", Value: "zürich"},
+			{Key: "lines", Value: bson.A{"brandschenkestrasse 110"}},
+			{Key: "organization", Value: "google"},
+		}})), got)
+		dec, err := bsontest.DecodeValue(t, c, got, TypePostalAddress)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, pa, dec, protocmp.Transform())
+	})
+	t.Run("Normalize", func(t *testing.T) {
+		c := NewPostalAddressCodec()
+		dec, err := bsontest.DecodeValue(t, c, bsontest.RawValue(t, bson.D{
+			{Key: "region_code", Value: " ch"},
+			{Key: "language_code", Value: "de-CH"},
+			{Key: "postal_code", Value: "8002 "},
+			{Key: "locality", Value: "Zürich"},
+			{Key: "address_lines", Value: bson.A{"  Brandschenkestrasse   110", ""}},
+			{Key: "recipients", Value: bson.A{"Jane Doe"}},
+			{Key: "organization", Value: "Google"},
+		}), TypePostalAddress)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, pa, dec, protocmp.Transform())
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		c := NewPostalAddressCodec()
+		_, err := bsontest.TryEncodeValue(c, &postaladdress.PostalAddress{Locality: "Zürich"})
+		assert.ErrorContains(t, err, "no region code")
+	})
+}
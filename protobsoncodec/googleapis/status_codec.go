@@ -0,0 +1,165 @@
+package googleapis
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Status type.
+var TypeStatus = reflect.TypeOf((*status.Status)(nil))
+
+// StatusCodec is the Codec used for *status.Status values.
+//
+// Statuses are encoded as {code: <int32>, code_name: <string>, message: <string>, details: [...]}
+// documents. code_name holds the name of the google.rpc.Code and is omitted for unknown codes.
+// Each detail is a document holding its type URL under "@type", expanded like protojson does
+// for messages known to the resolver and the registry. Details of unknown types are held as
+// binary under "value".
+type StatusCodec struct {
+	Resolver *protoregistry.Types
+}
+
+// EncodeValue is the ValueEncoderFunc for *status.Status.
+func (c *StatusCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
+	if !v.IsValid() || v.Type() != TypeStatus {
+		return bsoncodec.ValueEncoderError{
+			Name:     "StatusCodec.EncodeValue",
+			Types:    []reflect.Type{TypeStatus},
+			Received: v,
+		}
+	}
+	s := v.Interface().(*status.Status)
+	if s == nil {
+		return vw.WriteNull()
+	}
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+	if err := bsonutil.WriteElement(dw, "code", func(vw bsonrw.ValueWriter) error { return vw.WriteInt32(s.Code) }); err != nil {
+		return err
+	}
+	if name, ok := code.Code_name[s.Code]; ok {
+		if err := bsonutil.WriteElement(dw, "code_name", func(vw bsonrw.ValueWriter) error { return vw.WriteString(name) }); err != nil {
+			return err
+		}
+	}
+	if err := bsonutil.WriteElement(dw, "message", func(vw bsonrw.ValueWriter) error { return vw.WriteString(s.Message) }); err != nil {
+		return err
+	}
+	if err := bsonutil.WriteElement(dw, "details", func(vw bsonrw.ValueWriter) error {
+		aw, err := vw.WriteArray()
+		if err != nil {
+			return err
+		}
+		for _, detail := range s.Details {
+			evw, err := aw.WriteArrayElement()
+			if err != nil {
+				return err
+			}
+			if err := writeAny(ec, evw, detail, c.Resolver); err != nil {
+				return err
+			}
+		}
+		return aw.WriteArrayEnd()
+	}); err != nil {
+		return err
+	}
+	return dw.WriteDocumentEnd()
+}
+
+// DecodeValue is the ValueDecoderFunc for *status.Status.
+func (c *StatusCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) error {
+	if !v.CanSet() || v.Type() != TypeStatus {
+		return bsoncodec.ValueDecoderError{
+			Name:     "StatusCodec.DecodeValue",
+			Types:    []reflect.Type{TypeStatus},
+			Received: v,
+		}
+	}
+	var s *status.Status
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.EmbeddedDocument:
+		var err error
+		s, err = c.decodeDocument(dc, vr)
+		if err != nil {
+			return err
+		}
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+		s = nil
+	case bsontype.Undefined:
+		if err := vr.ReadUndefined(); err != nil {
+			return err
+		}
+		s = &status.Status{}
+	default:
+		return fmt.Errorf("cannot decode %v into a *status.Status", bsonTyp)
+	}
+	v.Set(reflect.ValueOf(s))
+	return nil
+}
+
+// NewStatusCodec returns a StatusCodec with options opts.
+func NewStatusCodec(opts ...*protobsonoptions.StatusCodecOptions) *StatusCodec {
+	mergedOpts := protobsonoptions.MergeStatusCodecOptions(opts...)
+	return &StatusCodec{
+		Resolver: mergedOpts.Resolver,
+	}
+}
+
+// decodeDocument decodes a status document. The code is derived from code_name when missing,
+// and must match it otherwise.
+func (c *StatusCodec) decodeDocument(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader) (*status.Status, error) {
+	s := &status.Status{}
+	var hasCode bool
+	var codeName string
+	err := bsonutil.ReadDocument(vr, func(key string, vr bsonrw.ValueReader) error {
+		var err error
+		switch key {
+		case "code":
+			var i64 int64
+			i64, err = bsonutil.ReadInt64(vr)
+			s.Code, hasCode = int32(i64), true
+		case "code_name", "codeName":
+			codeName, err = vr.ReadString()
+		case "message":
+			s.Message, err = vr.ReadString()
+		case "details":
+			err = bsonutil.ReadArray(vr, func(_ int, vr bsonrw.ValueReader) error {
+				detail, err := readAny(dc, vr, c.Resolver)
+				s.Details = append(s.Details, detail)
+				return err
+			})
+		default:
+			err = fmt.Errorf("unexpected key %q in a *status.Status document", key)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if codeName != "" {
+		n, ok := code.Code_value[codeName]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("unknown code name %q", codeName)
+		case !hasCode:
+			s.Code = n
+		case n != s.Code:
+			return nil, fmt.Errorf("code %d does not match code name %q", s.Code, codeName)
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,191 @@
+package googleapis
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.vallahaye.net/protobson/protobsoncodec"
+	knowncodec "go.vallahaye.net/protobson/protobsoncodec/known"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"gotest.tools/v3/assert"
+)
+
+// newMessageRegistry returns a registry able to encode the messages embedded in Any values.
+func newMessageRegistry(typ reflect.Type, c bsoncodec.ValueCodec) *bsoncodec.Registry {
+	mc := protobsoncodec.NewMessageCodec()
+	return bson.NewRegistryBuilder().
+		RegisterCodec(typ, c).
+		RegisterCodec(knowncodec.TypeStringValue, knowncodec.NewStringValueCodec()).
+		RegisterHookEncoder(protobsoncodec.TypeMessage, mc).
+		RegisterHookDecoder(protobsoncodec.TypeMessage, mc).
+		Build()
+}
+
+func mustNewAny(t *testing.T, m proto.Message) *anypb.Any {
+	t.Helper()
+	a, err := anypb.New(m)
+	assert.NilError(t, err)
+	return a
+}
+
+func TestStatusCodec(t *testing.T) {
+	s := &status.Status{
+		Code:    5,
+		Message: "order not found",
+		Details: []*anypb.Any{
+			mustNewAny(t, &errdetails.ErrorInfo{Reason: "ORDER_NOT_FOUND", Domain: "acme.com", Metadata: map[string]string{"order": "42"}}),
+			mustNewAny(t, wrapperspb.String("hint")),
+			{TypeUrl: "type.googleapis.com/acme.v1.Unknown", Value: []byte{0x08, 0x01}},
+		},
+	}
+	t.Run("EncodeToBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			s    *status.Status
+			vw   *bsonrwtest.ValueReaderWriter
+			want bsonrwtest.Invoked
+		}{
+			{
+				nil,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.Null},
+				bsonrwtest.WriteNull,
+			},
+			{
+				&status.Status{Code: 5},
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.EmbeddedDocument},
+				bsonrwtest.WriteDocumentEnd,
+			},
+		} {
+			t.Run(params.vw.Type().String(), func(t *testing.T) {
+				c := NewStatusCodec()
+				v := reflect.ValueOf(params.s)
+				err := c.EncodeValue(bsoncodec.EncodeContext{}, params.vw, v)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, params.vw.Invoked)
+			})
+		}
+	})
+	t.Run("DecodeFromBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			vr   *bsonrwtest.ValueReaderWriter
+			want *status.Status
+		}{
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Null,
+				},
+				nil,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Undefined,
+				},
+				&status.Status{},
+			},
+		} {
+			t.Run(params.vr.Type().String(), func(t *testing.T) {
+				c := NewStatusCodec()
+				got := reflect.New(reflect.TypeOf(params.want)).Elem()
+				err := c.DecodeValue(bsoncodec.DecodeContext{}, params.vr, got)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, got.Interface(), protocmp.Transform())
+			})
+		}
+	})
+	t.Run("RoundTrip", func(t *testing.T) {
+		reg := newMessageRegistry(TypeStatus, NewStatusCodec())
+		b, err := bson.MarshalWithRegistry(reg, bson.D{{Key: "v", Value: s}})
+		assert.NilError(t, err)
+		got := bson.Raw(b).Lookup("v")
+		want, err := bson.Marshal(bson.D{
+			{Key: "code", Value: int32(5)},
+			{Key: "code_name", Value: "NOT_FOUND"},
+			{Key: "message", Value: "order not found"},
+			{Key: "details", Value: bson.A{
+				bson.D{
+					{Key: "@type", Value: "type.googleapis.com/google.rpc.ErrorInfo"},
+					{Key: "reason", Value: "ORDER_NOT_FOUND"},
+					{Key: "domain", Value: "acme.com"},
+					{Key: "metadata", Value: bson.D{{Key: "order", Value: "42"}}},
+				},
+				bson.D{
+					{Key: "@type", Value: "type.googleapis.com/google.protobuf.StringValue"},
+					{Key: "value", Value: "hint"},
+				},
+				bson.D{
+					{Key: "@type", Value: "type.googleapis.com/acme.v1.Unknown"},
+					{Key: "value", Value: primitive.Binary{Data: []byte{0x08, 0x01}}},
+				},
+			}},
+		})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, bson.Raw(want), got.Document())
+		var dec struct {
+			V *status.Status `bson:"v"`
+		}
+		err = bson.UnmarshalWithRegistry(reg, b, &dec)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, s, dec.V, protocmp.Transform())
+	})
+	t.Run("CodeName", func(t *testing.T) {
+		reg := newMessageRegistry(TypeStatus, NewStatusCodec())
+		b, err := bson.Marshal(bson.D{{Key: "v", Value: bson.D{{Key: "code_name", Value: "NOT_FOUND"}}}})
+		assert.NilError(t, err)
+		var dec struct {
+			V *status.Status `bson:"v"`
+		}
+		err = bson.UnmarshalWithRegistry(reg, b, &dec)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, &status.Status{Code: 5}, dec.V, protocmp.Transform())
+		for _, params := range []struct {
+			doc     bson.D
+			wantErr string
+		}{
+			{bson.D{{Key: "code", Value: int32(3)}, {Key: "code_name", Value: "NOT_FOUND"}}, `code 3 does not match code name "NOT_FOUND"`},
+			{bson.D{{Key: "code_name", Value: "NOT_FOUND"}, {Key: "code", Value: int32(0)}}, `code 0 does not match code name "NOT_FOUND"`},
+			{bson.D{{Key: "code", Value: int32(5)}, {Key: "code_name", Value: "MISSING"}}, `unknown code name "MISSING"`},
+		} {
+			b, err := bson.Marshal(bson.D{{Key: "v", Value: params.doc}})
+			assert.NilError(t, err)
+			err = bson.UnmarshalWithRegistry(reg, b, &dec)
+			assert.ErrorContains(t, err, params.wantErr)
+		}
+	})
+	t.Run("TopLevel", func(t *testing.T) {
+		reg := newMessageRegistry(TypeStatus, NewStatusCodec())
+		b, err := bson.MarshalWithRegistry(reg, s)
+		assert.NilError(t, err)
+		assert.Equal(t, "NOT_FOUND", bson.Raw(b).Lookup("code_name").StringValue())
+		got := &status.Status{}
+		err = bson.UnmarshalWithRegistry(reg, b, got)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, s, got, protocmp.Transform())
+	})
+	t.Run("InlinedValueField", func(t *testing.T) {
+		// Without a StringValue codec, StringValue details are inlined documents whose only
+		// field is "value".
+		mc := protobsoncodec.NewMessageCodec()
+		reg := bson.NewRegistryBuilder().
+			RegisterCodec(TypeStatus, NewStatusCodec()).
+			RegisterHookEncoder(protobsoncodec.TypeMessage, mc).
+			RegisterHookDecoder(protobsoncodec.TypeMessage, mc).
+			Build()
+		want := &status.Status{Details: []*anypb.Any{mustNewAny(t, wrapperspb.String("hint"))}}
+		b, err := bson.MarshalWithRegistry(reg, want)
+		assert.NilError(t, err)
+		assert.Equal(t, "hint", bson.Raw(b).Lookup("details", "0", "value").StringValue())
+		got := &status.Status{}
+		err = bson.UnmarshalWithRegistry(reg, b, got)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, want, got, protocmp.Transform())
+	})
+}
@@ -0,0 +1,197 @@
+package googleapis
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/type/timeofday"
+)
+
+const nanosPerDay = int64(24 * time.Hour)
+
+// TimeOfDay type.
+var TypeTimeOfDay = reflect.TypeOf((*timeofday.TimeOfDay)(nil))
+
+// TimeOfDayCodec is the Codec used for *timeofday.TimeOfDay values.
+type TimeOfDayCodec struct {
+	Format protobsonoptions.TimeOfDayFormat
+}
+
+// EncodeValue is the ValueEncoderFunc for *timeofday.TimeOfDay.
+func (c *TimeOfDayCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
+	if !v.IsValid() || v.Type() != TypeTimeOfDay {
+		return bsoncodec.ValueEncoderError{
+			Name:     "TimeOfDayCodec.EncodeValue",
+			Types:    []reflect.Type{TypeTimeOfDay},
+			Received: v,
+		}
+	}
+	tod := v.Interface().(*timeofday.TimeOfDay)
+	if tod == nil {
+		return vw.WriteNull()
+	}
+	if err := validateTimeOfDay(tod); err != nil {
+		return err
+	}
+	switch c.Format {
+	case protobsonoptions.TimeOfDayFormatNanos:
+		return vw.WriteInt64(timeOfDayToNanos(tod))
+	case protobsonoptions.TimeOfDayFormatString:
+		return vw.WriteString(fmt.Sprintf("%02d:%02d:%02d.%09d", tod.Hours, tod.Minutes, tod.Seconds, tod.Nanos))
+	default:
+		return vw.WriteInt32(int32(timeOfDayToNanos(tod) / int64(time.Millisecond)))
+	}
+}
+
+// DecodeValue is the ValueDecoderFunc for *timeofday.TimeOfDay.
+func (c *TimeOfDayCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) error {
+	if !v.CanSet() || v.Type() != TypeTimeOfDay {
+		return bsoncodec.ValueDecoderError{
+			Name:     "TimeOfDayCodec.DecodeValue",
+			Types:    []reflect.Type{TypeTimeOfDay},
+			Received: v,
+		}
+	}
+	var tod *timeofday.TimeOfDay
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.Int32, bsontype.Int64:
+		n, err := bsonutil.ReadInt64(vr)
+		if err != nil {
+			return err
+		}
+		unit := int64(time.Millisecond)
+		if c.Format == protobsonoptions.TimeOfDayFormatNanos {
+			unit = 1
+		}
+		// Checked before converting to nanoseconds, which could overflow.
+		if n < 0 || n > nanosPerDay/unit {
+			return fmt.Errorf("%d is out of range for a *timeofday.TimeOfDay", n)
+		}
+		n *= unit
+		tod = nanosToTimeOfDay(n)
+	case bsontype.String:
+		s, err := vr.ReadString()
+		if err != nil {
+			return err
+		}
+		tod, err = parseTimeOfDay(s)
+		if err != nil {
+			return err
+		}
+	case bsontype.EmbeddedDocument:
+		tod = &timeofday.TimeOfDay{}
+		err := bsonutil.ReadDocument(vr, func(key string, vr bsonrw.ValueReader) error {
+			i64, err := bsonutil.ReadInt64(vr)
+			if err != nil {
+				return err
+			}
+			switch key {
+			case "hours":
+				tod.Hours = int32(i64)
+			case "minutes":
+				tod.Minutes = int32(i64)
+			case "seconds":
+				tod.Seconds = int32(i64)
+			case "nanos":
+				tod.Nanos = int32(i64)
+			default:
+				return fmt.Errorf("unexpected key %q in a *timeofday.TimeOfDay document", key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if err := validateTimeOfDay(tod); err != nil {
+			return err
+		}
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+		tod = nil
+	case bsontype.Undefined:
+		if err := vr.ReadUndefined(); err != nil {
+			return err
+		}
+		tod = &timeofday.TimeOfDay{}
+	default:
+		return fmt.Errorf("cannot decode %v into a *timeofday.TimeOfDay", bsonTyp)
+	}
+	v.Set(reflect.ValueOf(tod))
+	return nil
+}
+
+// NewTimeOfDayCodec returns a TimeOfDayCodec with options opts.
+func NewTimeOfDayCodec(opts ...*protobsonoptions.TimeOfDayCodecOptions) *TimeOfDayCodec {
+	mergedOpts := protobsonoptions.MergeTimeOfDayCodecOptions(opts...)
+	return &TimeOfDayCodec{
+		Format: *mergedOpts.Format,
+	}
+}
+
+// validateTimeOfDay reports whether tod is a valid time of day, allowing "24:00:00" for the
+// end of the day but rejecting leap seconds.
+func validateTimeOfDay(tod *timeofday.TimeOfDay) error {
+	valid := tod.Hours >= 0 && tod.Hours <= 23 && tod.Minutes >= 0 && tod.Minutes <= 59 &&
+		tod.Seconds >= 0 && tod.Seconds <= 59 && tod.Nanos >= 0 && tod.Nanos <= 999999999
+	if tod.Hours == 24 {
+		valid = tod.Minutes == 0 && tod.Seconds == 0 && tod.Nanos == 0
+	}
+	if !valid {
+		return fmt.Errorf("invalid time of day %02d:%02d:%02d.%09d", tod.Hours, tod.Minutes, tod.Seconds, tod.Nanos)
+	}
+	return nil
+}
+
+func timeOfDayToNanos(tod *timeofday.TimeOfDay) int64 {
+	return int64(tod.Hours)*int64(time.Hour) + int64(tod.Minutes)*int64(time.Minute) +
+		int64(tod.Seconds)*int64(time.Second) + int64(tod.Nanos)
+}
+
+func nanosToTimeOfDay(n int64) *timeofday.TimeOfDay {
+	d := time.Duration(n)
+	return &timeofday.TimeOfDay{
+		Hours:   int32(d / time.Hour),
+		Minutes: int32(d % time.Hour / time.Minute),
+		Seconds: int32(d % time.Minute / time.Second),
+		Nanos:   int32(d % time.Second),
+	}
+}
+
+// parseTimeOfDay parses s in the "HH:MM[:SS[.fffffffff]]" format.
+func parseTimeOfDay(s string) (*timeofday.TimeOfDay, error) {
+	invalid := fmt.Errorf("cannot parse %q as a time of day", s)
+	s, frac, hasFrac := strings.Cut(s, ".")
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 || (hasFrac && (len(parts) != 3 || len(frac) == 0 || len(frac) > 9)) {
+		return nil, invalid
+	}
+	var tod timeofday.TimeOfDay
+	for i, dst := range []*int32{&tod.Hours, &tod.Minutes, &tod.Seconds}[:len(parts)] {
+		n, err := strconv.ParseUint(parts[i], 10, 8)
+		if err != nil || len(parts[i]) != 2 {
+			return nil, invalid
+		}
+		*dst = int32(n)
+	}
+	if hasFrac {
+		n, err := strconv.ParseUint(frac+strings.Repeat("0", 9-len(frac)), 10, 32)
+		if err != nil {
+			return nil, invalid
+		}
+		tod.Nanos = int32(n)
+	}
+	if err := validateTimeOfDay(&tod); err != nil {
+		return nil, err
+	}
+	return &tod, nil
+}
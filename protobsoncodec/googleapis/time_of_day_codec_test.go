@@ -0,0 +1,171 @@
+package googleapis
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsontest"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/type/timeofday"
+	"google.golang.org/protobuf/testing/protocmp"
+	"gotest.tools/v3/assert"
+)
+
+func TestTimeOfDayCodec(t *testing.T) {
+	tod := &timeofday.TimeOfDay{Hours: 11, Minutes: 43, Seconds: 26}
+	t.Run("EncodeToBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			tod  *timeofday.TimeOfDay
+			vw   *bsonrwtest.ValueReaderWriter
+			want bsonrwtest.Invoked
+		}{
+			{
+				nil,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.Null},
+				bsonrwtest.WriteNull,
+			},
+			{
+				tod,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.Int32},
+				bsonrwtest.WriteInt32,
+			},
+		} {
+			t.Run(params.vw.Type().String(), func(t *testing.T) {
+				c := NewTimeOfDayCodec()
+				v := reflect.ValueOf(params.tod)
+				err := c.EncodeValue(bsoncodec.EncodeContext{}, params.vw, v)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, params.vw.Invoked)
+			})
+		}
+	})
+	t.Run("DecodeFromBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			vr   *bsonrwtest.ValueReaderWriter
+			want *timeofday.TimeOfDay
+		}{
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Int32,
+					Return:   int32(42206000),
+				},
+				tod,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Int64,
+					Return:   int64(42206000),
+				},
+				tod,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.String,
+					Return:   "11:43:26",
+				},
+				tod,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Null,
+				},
+				nil,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Undefined,
+				},
+				&timeofday.TimeOfDay{},
+			},
+		} {
+			t.Run(params.vr.Type().String(), func(t *testing.T) {
+				c := NewTimeOfDayCodec()
+				got := reflect.New(reflect.TypeOf(params.want)).Elem()
+				err := c.DecodeValue(bsoncodec.DecodeContext{}, params.vr, got)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, got.Interface(), protocmp.Transform())
+			})
+		}
+	})
+	t.Run("Formats", func(t *testing.T) {
+		for _, params := range []struct {
+			name   string
+			format protobsonoptions.TimeOfDayFormat
+			tod    *timeofday.TimeOfDay
+			want   interface{}
+		}{
+			{
+				"Millis",
+				protobsonoptions.TimeOfDayFormatMillis,
+				&timeofday.TimeOfDay{Hours: 11, Minutes: 43, Seconds: 26, Nanos: 123000000},
+				int32(42206123),
+			},
+			{
+				"MillisEndOfDay",
+				protobsonoptions.TimeOfDayFormatMillis,
+				&timeofday.TimeOfDay{Hours: 24},
+				int32(86400000),
+			},
+			{
+				"Nanos",
+				protobsonoptions.TimeOfDayFormatNanos,
+				&timeofday.TimeOfDay{Hours: 11, Minutes: 43, Seconds: 26, Nanos: 123456789},
+				int64(42206123456789),
+			},
+			{
+				"String",
+				protobsonoptions.TimeOfDayFormatString,
+				&timeofday.TimeOfDay{Hours: 11, Minutes: 43, Seconds: 26, Nanos: 123456789},
+				"11:43:26.123456789",
+			},
+			{
+				"StringEndOfDay",
+				protobsonoptions.TimeOfDayFormatString,
+				&timeofday.TimeOfDay{Hours: 24},
+				"24:00:00.000000000",
+			},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				c := NewTimeOfDayCodec(protobsonoptions.TimeOfDayCodec().SetFormat(params.format))
+				got := bsontest.EncodeValue(t, c, params.tod)
+				assert.DeepEqual(t, bsontest.RawValue(t, params.want), got)
+				dec, err := bsontest.DecodeValue(t, c, got, TypeTimeOfDay)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.tod, dec, protocmp.Transform())
+			})
+		}
+	})
+	t.Run("DecodeDocument", func(t *testing.T) {
+		c := NewTimeOfDayCodec()
+		got, err := bsontest.DecodeValue(t, c, bsontest.RawValue(t, bson.D{
+			{Key: "hours", Value: int32(11)},
+			{Key: "minutes", Value: int32(43)},
+			{Key: "seconds", Value: int32(26)},
+			{Key: "nanos", Value: int32(0)},
+		}), TypeTimeOfDay)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, tod, got, protocmp.Transform())
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		c := NewTimeOfDayCodec()
+		for _, tod := range []*timeofday.TimeOfDay{
+			{Hours: 24, Minutes: 1},
+			{Hours: 25},
+			{Minutes: 60},
+			{Seconds: 60},
+			{Nanos: -1},
+		} {
+			err := c.EncodeValue(bsoncodec.EncodeContext{}, &bsonrwtest.ValueReaderWriter{}, reflect.ValueOf(tod))
+			assert.ErrorContains(t, err, "invalid time of day")
+		}
+		for _, val := range []interface{}{"24:00:01", "1:00", "11:43:26.", int32(86400001), int32(-1), int64(18446744073710), int64(math.MaxInt64)} {
+			_, err := bsontest.DecodeValue(t, c, bsontest.RawValue(t, val), TypeTimeOfDay)
+			assert.Assert(t, err != nil)
+		}
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -15,7 +16,9 @@ import (
 var TypeBoolValue = reflect.TypeOf((*wrapperspb.BoolValue)(nil))
 
 // BoolValueCodec is the Codec used for *wrapperspb.BoolValue values.
-type BoolValueCodec struct{}
+type BoolValueCodec struct {
+	NilFormat protobsonoptions.NilFormat
+}
 
 // EncodeValue is the ValueEncoderFunc for *wrapperspb.BoolValue.
 func (c *BoolValueCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
@@ -77,7 +80,15 @@ func (c *BoolValueCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.Value
 	return nil
 }
 
-// NewBoolValueCodec returns a BoolValueCodec.
-func NewBoolValueCodec() *BoolValueCodec {
-	return &BoolValueCodec{}
+// NewBoolValueCodec returns a BoolValueCodec with options opts.
+func NewBoolValueCodec(opts ...*protobsonoptions.WrapperCodecOptions) *BoolValueCodec {
+	mergedOpts := protobsonoptions.MergeWrapperCodecOptions(opts...)
+	return &BoolValueCodec{
+		NilFormat: *mergedOpts.NilFormat,
+	}
+}
+
+// OmitNil reports whether the MessageCodec omits unset fields of this type.
+func (c *BoolValueCodec) OmitNil() bool {
+	return c.NilFormat == protobsonoptions.NilFormatOmit
 }
@@ -0,0 +1,59 @@
+package known
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"gotest.tools/v3/assert"
+)
+
+// encodeValue encodes val with codec c and returns the resulting BSON value.
+func encodeValue(t *testing.T, c bsoncodec.ValueCodec, val interface{}) bson.RawValue {
+	t.Helper()
+	reg := bson.NewRegistryBuilder().RegisterCodec(reflect.TypeOf(val), c).Build()
+	b, err := bson.MarshalWithRegistry(reg, bson.D{{Key: "v", Value: val}})
+	assert.NilError(t, err)
+	return bson.Raw(b).Lookup("v")
+}
+
+// tryEncodeValue encodes val with codec c and returns the resulting BSON value or the encoding
+// error.
+func tryEncodeValue(c bsoncodec.ValueCodec, val interface{}) (bson.RawValue, error) {
+	reg := bson.NewRegistryBuilder().RegisterCodec(reflect.TypeOf(val), c).Build()
+	b, err := bson.MarshalWithRegistry(reg, bson.D{{Key: "v", Value: val}})
+	if err != nil {
+		return bson.RawValue{}, err
+	}
+	return bson.Raw(b).Lookup("v"), nil
+}
+
+// decodeValue decodes the BSON value rv into a new value of type typ with codec c.
+func decodeValue(t *testing.T, c bsoncodec.ValueCodec, rv bson.RawValue, typ reflect.Type) (interface{}, error) {
+	t.Helper()
+	b, err := bson.Marshal(bson.D{{Key: "v", Value: rv}})
+	assert.NilError(t, err)
+	reg := bson.NewRegistryBuilder().RegisterCodec(typ, c).Build()
+	got := reflect.New(reflect.StructOf([]reflect.StructField{{Name: "V", Type: typ, Tag: `bson:"v"`}}))
+	err = bson.UnmarshalWithRegistry(reg, b, got.Interface())
+	return got.Elem().Field(0).Interface(), err
+}
+
+// rawValue returns val marshaled as a BSON value with the default registry.
+func rawValue(t *testing.T, val interface{}) bson.RawValue {
+	t.Helper()
+	b, err := bson.Marshal(bson.D{{Key: "v", Value: val}})
+	assert.NilError(t, err)
+	return bson.Raw(b).Lookup("v")
+}
+
+// decimal128 parses s as a Decimal128, panicking on error.
+func decimal128(s string) primitive.Decimal128 {
+	d, err := primitive.ParseDecimal128(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
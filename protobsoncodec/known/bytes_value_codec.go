@@ -7,6 +7,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -14,7 +16,14 @@ import (
 var TypeBytesValue = reflect.TypeOf((*wrapperspb.BytesValue)(nil))
 
 // BytesValueCodec is the Codec used for *wrapperspb.BytesValue values.
-type BytesValueCodec struct{}
+//
+// Values are encoded as binary of the configured subtype. Binary values of any subtype and
+// strings are decoded, the latter being base64 decoded when DecodeBase64Strings is true.
+type BytesValueCodec struct {
+	Subtype             byte
+	DecodeBase64Strings bool
+	NilFormat           protobsonoptions.NilFormat
+}
 
 // EncodeValue is the ValueEncoderFunc for *wrapperspb.BytesValue.
 func (c *BytesValueCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
@@ -29,7 +38,7 @@ func (c *BytesValueCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.Valu
 	if val == nil {
 		return vw.WriteNull()
 	}
-	return vw.WriteBinary(val.Value)
+	return vw.WriteBinaryWithSubtype(val.Value, c.Subtype)
 }
 
 // DecodeValue is the ValueDecoderFunc for *wrapperspb.BytesValue.
@@ -43,18 +52,12 @@ func (c *BytesValueCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.Valu
 	}
 	var val *wrapperspb.BytesValue
 	switch bsonTyp := vr.Type(); bsonTyp {
-	case bsontype.Binary:
-		v, _, err := vr.ReadBinary()
+	case bsontype.Binary, bsontype.String:
+		v, err := bsonutil.ReadBytes(vr, c.DecodeBase64Strings)
 		if err != nil {
 			return err
 		}
 		val = wrapperspb.Bytes(v)
-	case bsontype.String:
-		v, err := vr.ReadString()
-		if err != nil {
-			return err
-		}
-		val = wrapperspb.Bytes([]byte(v))
 	case bsontype.Null:
 		if err := vr.ReadNull(); err != nil {
 			return err
@@ -72,7 +75,17 @@ func (c *BytesValueCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.Valu
 	return nil
 }
 
-// NewBytesValueCodec returns a BytesValueCodec.
-func NewBytesValueCodec() *BytesValueCodec {
-	return &BytesValueCodec{}
+// NewBytesValueCodec returns a BytesValueCodec with options opts.
+func NewBytesValueCodec(opts ...*protobsonoptions.BytesValueCodecOptions) *BytesValueCodec {
+	mergedOpts := protobsonoptions.MergeBytesValueCodecOptions(opts...)
+	return &BytesValueCodec{
+		Subtype:             *mergedOpts.Subtype,
+		DecodeBase64Strings: *mergedOpts.DecodeBase64Strings,
+		NilFormat:           *mergedOpts.NilFormat,
+	}
+}
+
+// OmitNil reports whether the MessageCodec omits unset fields of this type.
+func (c *BytesValueCodec) OmitNil() bool {
+	return c.NilFormat == protobsonoptions.NilFormatOmit
 }
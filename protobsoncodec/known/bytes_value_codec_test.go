@@ -7,7 +7,9 @@ import (
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 	"gotest.tools/v3/assert"
@@ -28,7 +30,7 @@ func TestBytesValueCodec(t *testing.T) {
 			{
 				wrapperspb.Bytes([]byte("Hello, World!")),
 				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.Binary},
-				bsonrwtest.WriteBinary,
+				bsonrwtest.WriteBinaryWithSubtype,
 			},
 		} {
 			c := NewBytesValueCodec()
@@ -53,6 +55,16 @@ func TestBytesValueCodec(t *testing.T) {
 				},
 				wrapperspb.Bytes([]byte("Hello, World!")),
 			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Binary,
+					Return: bsoncore.Value{
+						Type: bsontype.Binary,
+						Data: bsoncore.AppendBinary(nil, bsontype.BinaryMD5, []byte("Hello, World!")),
+					},
+				},
+				wrapperspb.Bytes([]byte("Hello, World!")),
+			},
 			{
 				&bsonrwtest.ValueReaderWriter{
 					BSONType: bsontype.String,
@@ -82,4 +94,23 @@ func TestBytesValueCodec(t *testing.T) {
 			})
 		}
 	})
+	t.Run("Subtype", func(t *testing.T) {
+		c := NewBytesValueCodec(protobsonoptions.BytesValueCodec().SetSubtype(bsontype.BinaryUUID))
+		val := wrapperspb.Bytes([]byte{0x4f, 0x0a, 0x1e, 0x5c, 0x2b, 0x8d, 0x4c, 0x3e, 0x9a, 0x61, 0x0d, 0x7b, 0x52, 0xe4, 0x11, 0x09})
+		got := encodeValue(t, c, val)
+		assert.DeepEqual(t, rawValue(t, primitive.Binary{Subtype: bsontype.BinaryUUID, Data: val.Value}), got)
+		dec, err := decodeValue(t, c, got, TypeBytesValue)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, val, dec, protocmp.Transform())
+	})
+	t.Run("Base64", func(t *testing.T) {
+		c := NewBytesValueCodec(protobsonoptions.BytesValueCodec().SetDecodeBase64Strings(true))
+		for _, s := range []string{"+/8=", "+/8", "-_8=", "-_8"} {
+			dec, err := decodeValue(t, c, rawValue(t, s), TypeBytesValue)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, wrapperspb.Bytes([]byte{0xfb, 0xff}), dec, protocmp.Transform())
+		}
+		_, err := decodeValue(t, c, rawValue(t, "Hello, World!"), TypeBytesValue)
+		assert.Assert(t, err != nil)
+	})
 }
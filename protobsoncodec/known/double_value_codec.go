@@ -3,11 +3,12 @@ package known
 import (
 	"fmt"
 	"reflect"
-	"strconv"
 
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -15,7 +16,12 @@ import (
 var TypeDoubleValue = reflect.TypeOf((*wrapperspb.DoubleValue)(nil))
 
 // DoubleValueCodec is the Codec used for *wrapperspb.DoubleValue values.
-type DoubleValueCodec struct{}
+//
+// Double, Int32, Int64, Decimal128 and numeric String values are decoded.
+type DoubleValueCodec struct {
+	NonFiniteFormat protobsonoptions.NonFiniteFormat
+	NilFormat       protobsonoptions.NilFormat
+}
 
 // EncodeValue is the ValueEncoderFunc for *wrapperspb.DoubleValue.
 func (c *DoubleValueCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
@@ -30,7 +36,7 @@ func (c *DoubleValueCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.Val
 	if val == nil {
 		return vw.WriteNull()
 	}
-	return vw.WriteDouble(val.Value)
+	return bsonutil.WriteFloat64(vw, val.Value, c.NonFiniteFormat)
 }
 
 // DecodeValue is the ValueDecoderFunc for *wrapperspb.DoubleValue.
@@ -44,22 +50,12 @@ func (c *DoubleValueCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.Val
 	}
 	var val *wrapperspb.DoubleValue
 	switch bsonTyp := vr.Type(); bsonTyp {
-	case bsontype.Double:
-		v, err := vr.ReadDouble()
-		if err != nil {
-			return err
-		}
-		val = wrapperspb.Double(v)
-	case bsontype.String:
-		s, err := vr.ReadString()
-		if err != nil {
-			return err
-		}
-		v, err := strconv.ParseFloat(s, 64)
+	case bsontype.Double, bsontype.Int32, bsontype.Int64, bsontype.Decimal128, bsontype.String:
+		f, err := bsonutil.ReadAnyFloat64(vr)
 		if err != nil {
 			return err
 		}
-		val = wrapperspb.Double(v)
+		val = wrapperspb.Double(f)
 	case bsontype.Null:
 		if err := vr.ReadNull(); err != nil {
 			return err
@@ -77,7 +73,16 @@ func (c *DoubleValueCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.Val
 	return nil
 }
 
-// NewDoubleValueCodec returns a DoubleValueCodec.
-func NewDoubleValueCodec() *DoubleValueCodec {
-	return &DoubleValueCodec{}
+// NewDoubleValueCodec returns a DoubleValueCodec with options opts.
+func NewDoubleValueCodec(opts ...*protobsonoptions.DoubleValueCodecOptions) *DoubleValueCodec {
+	mergedOpts := protobsonoptions.MergeDoubleValueCodecOptions(opts...)
+	return &DoubleValueCodec{
+		NonFiniteFormat: *mergedOpts.NonFiniteFormat,
+		NilFormat:       *mergedOpts.NilFormat,
+	}
+}
+
+// OmitNil reports whether the MessageCodec omits unset fields of this type.
+func (c *DoubleValueCodec) OmitNil() bool {
+	return c.NilFormat == protobsonoptions.NilFormatOmit
 }
@@ -1,12 +1,14 @@
 package known
 
 import (
+	"math"
 	"reflect"
 	"testing"
 
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 	"gotest.tools/v3/assert"
@@ -78,4 +80,54 @@ func TestDoubleValueCodec(t *testing.T) {
 			})
 		}
 	})
+	t.Run("NonFiniteFormat", func(t *testing.T) {
+		for _, params := range []struct {
+			name    string
+			format  protobsonoptions.NonFiniteFormat
+			want    interface{}
+			wantErr string
+		}{
+			{"Double", protobsonoptions.NonFiniteFormatDouble, math.Inf(-1), ""},
+			{"String", protobsonoptions.NonFiniteFormatString, "-Infinity", ""},
+			{"Reject", protobsonoptions.NonFiniteFormatReject, nil, "-Inf cannot be encoded"},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				c := NewDoubleValueCodec(protobsonoptions.DoubleValueCodec().SetNonFiniteFormat(params.format))
+				val := wrapperspb.Double(math.Inf(-1))
+				got, err := tryEncodeValue(c, val)
+				if params.wantErr != "" {
+					assert.ErrorContains(t, err, params.wantErr)
+					return
+				}
+				assert.NilError(t, err)
+				assert.DeepEqual(t, rawValue(t, params.want), got)
+				dec, err := decodeValue(t, c, got, TypeDoubleValue)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, val, dec, protocmp.Transform())
+			})
+		}
+	})
+	t.Run("Decode", func(t *testing.T) {
+		c := NewDoubleValueCodec()
+		for _, params := range []struct {
+			val  interface{}
+			want float64
+		}{
+			{decimal128("0.1"), 0.1},
+			{decimal128("-1E+300"), -1e300},
+			{"2.5", 2.5},
+			{int64(3), 3},
+		} {
+			dec, err := decodeValue(t, c, rawValue(t, params.val), TypeDoubleValue)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, wrapperspb.Double(params.want), dec, protocmp.Transform())
+		}
+		dec, err := decodeValue(t, c, rawValue(t, "NaN"), TypeDoubleValue)
+		assert.NilError(t, err)
+		assert.Assert(t, math.IsNaN(dec.(*wrapperspb.DoubleValue).Value))
+		for _, val := range []interface{}{decimal128("1E+400"), "1e400", "one"} {
+			_, err := decodeValue(t, c, rawValue(t, val), TypeDoubleValue)
+			assert.Assert(t, err != nil)
+		}
+	})
 }
@@ -2,20 +2,33 @@ package known
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/protobuf/types/known/durationpb"
 )
 
+const (
+	nanosPerSecond = int64(time.Second)
+	maxNanosSecond = math.MaxInt64 / nanosPerSecond
+)
+
 // Duration type.
 var TypeDuration = reflect.TypeOf((*durationpb.Duration)(nil))
 
 // DurationCodec is the Codec used for *durationpb.Duration values.
-type DurationCodec struct{}
+type DurationCodec struct {
+	Format protobsonoptions.DurationFormat
+}
 
 // EncodeValue is the ValueEncoderFunc for *durationpb.Duration.
 func (c *DurationCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
@@ -30,7 +43,51 @@ func (c *DurationCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueW
 	if dur == nil {
 		return vw.WriteNull()
 	}
-	return vw.WriteInt64(int64(dur.AsDuration()))
+	if err := dur.CheckValid(); err != nil {
+		return err
+	}
+	switch c.Format {
+	case protobsonoptions.DurationFormatMillis:
+		return vw.WriteInt64(dur.Seconds*int64(time.Second/time.Millisecond) + int64(dur.Nanos)/nanosPerMilli)
+	case protobsonoptions.DurationFormatSeconds:
+		return vw.WriteDouble(float64(dur.Seconds) + float64(dur.Nanos)/float64(nanosPerSecond))
+	case protobsonoptions.DurationFormatDocument:
+		dw, err := vw.WriteDocument()
+		if err != nil {
+			return err
+		}
+		evw, err := dw.WriteDocumentElement("seconds")
+		if err != nil {
+			return err
+		}
+		if err := evw.WriteInt64(dur.Seconds); err != nil {
+			return err
+		}
+		evw, err = dw.WriteDocumentElement("nanos")
+		if err != nil {
+			return err
+		}
+		if err := evw.WriteInt32(dur.Nanos); err != nil {
+			return err
+		}
+		return dw.WriteDocumentEnd()
+	case protobsonoptions.DurationFormatString:
+		return vw.WriteString(formatDuration(dur))
+	case protobsonoptions.DurationFormatDecimal128:
+		nsec := new(big.Int).Mul(big.NewInt(dur.Seconds), big.NewInt(nanosPerSecond))
+		d, err := bsonutil.DecimalFromBigInt(nsec.Add(nsec, big.NewInt(int64(dur.Nanos))), 9)
+		if err != nil {
+			return err
+		}
+		return vw.WriteDecimal128(d)
+	default:
+		if dur.Seconds > maxNanosSecond || dur.Seconds < -maxNanosSecond ||
+			(dur.Seconds == maxNanosSecond && int64(dur.Nanos) > math.MaxInt64%nanosPerSecond) ||
+			(dur.Seconds == -maxNanosSecond && int64(dur.Nanos) < math.MinInt64%nanosPerSecond) {
+			return fmt.Errorf("%s cannot be encoded as 64-bit integer nanoseconds", formatDuration(dur))
+		}
+		return vw.WriteInt64(dur.Seconds*nanosPerSecond + int64(dur.Nanos))
+	}
 }
 
 // DecodeValue is the ValueDecoderFunc for *durationpb.Duration.
@@ -44,22 +101,52 @@ func (c *DurationCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueR
 	}
 	var dur *durationpb.Duration
 	switch bsonTyp := vr.Type(); bsonTyp {
-	case bsontype.Int64:
-		nsec, err := vr.ReadInt64()
+	case bsontype.Int64, bsontype.Int32:
+		n, err := bsonutil.ReadInt64(vr)
+		if err != nil {
+			return err
+		}
+		unit := c.unit()
+		perSec := nanosPerSecond / unit
+		dur = &durationpb.Duration{Seconds: n / perSec, Nanos: int32(n % perSec * unit)}
+	case bsontype.Double:
+		f, err := vr.ReadDouble()
+		if err != nil {
+			return err
+		}
+		dur, err = floatToDuration(f * float64(c.unit()) / float64(nanosPerSecond))
 		if err != nil {
 			return err
 		}
-		dur = durationpb.New(time.Duration(nsec))
+	case bsontype.Decimal128:
+		d, err := vr.ReadDecimal128()
+		if err != nil {
+			return err
+		}
+		nsec, err := bsonutil.DecimalToBigInt(d, 9)
+		if err != nil {
+			return err
+		}
+		sec, nanos := new(big.Int).QuoRem(nsec, big.NewInt(nanosPerSecond), new(big.Int))
+		if !sec.IsInt64() {
+			return fmt.Errorf("%v seconds is out of range for a *durationpb.Duration", d)
+		}
+		dur = &durationpb.Duration{Seconds: sec.Int64(), Nanos: int32(nanos.Int64())}
 	case bsontype.String:
 		s, err := vr.ReadString()
 		if err != nil {
 			return err
 		}
-		d, err := time.ParseDuration(s)
+		dur, err = parseDuration(s)
+		if err != nil {
+			return err
+		}
+	case bsontype.EmbeddedDocument:
+		var err error
+		dur, err = decodeDurationDocument(vr)
 		if err != nil {
 			return err
 		}
-		dur = durationpb.New(d)
 	case bsontype.Null:
 		if err := vr.ReadNull(); err != nil {
 			return err
@@ -73,11 +160,125 @@ func (c *DurationCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueR
 	default:
 		return fmt.Errorf("cannot decode %v into a *durationpb.Duration", bsonTyp)
 	}
+	if dur != nil {
+		if err := dur.CheckValid(); err != nil {
+			return err
+		}
+	}
 	v.Set(reflect.ValueOf(dur))
 	return nil
 }
 
-// NewDurationCodec returns a DurationCodec.
-func NewDurationCodec() *DurationCodec {
-	return &DurationCodec{}
+// unit returns the number of nanoseconds in the unit numbers are decoded in.
+func (c *DurationCodec) unit() int64 {
+	switch c.Format {
+	case protobsonoptions.DurationFormatMillis:
+		return nanosPerMilli
+	case protobsonoptions.DurationFormatSeconds, protobsonoptions.DurationFormatDecimal128:
+		return nanosPerSecond
+	default:
+		return 1
+	}
+}
+
+// NewDurationCodec returns a DurationCodec with options opts.
+func NewDurationCodec(opts ...*protobsonoptions.DurationCodecOptions) *DurationCodec {
+	mergedOpts := protobsonoptions.MergeDurationCodecOptions(opts...)
+	return &DurationCodec{
+		Format: *mergedOpts.Format,
+	}
+}
+
+// floatToDuration converts f seconds into a *durationpb.Duration, rounding to the nearest nanosecond.
+func floatToDuration(f float64) (*durationpb.Duration, error) {
+	// The largest valid duration is 10,000 years, far below the range of an int64.
+	if math.IsNaN(f) || math.Abs(f) > 1e12 {
+		return nil, fmt.Errorf("%v seconds is out of range for a *durationpb.Duration", f)
+	}
+	sec, frac := math.Modf(f)
+	nanos := math.Round(frac * float64(nanosPerSecond))
+	if math.Abs(nanos) == float64(nanosPerSecond) {
+		sec, nanos = sec+math.Copysign(1, nanos), 0
+	}
+	return &durationpb.Duration{Seconds: int64(sec), Nanos: int32(nanos)}, nil
+}
+
+// formatDuration formats dur the same way as protojson, e.g. "1.500s".
+func formatDuration(dur *durationpb.Duration) string {
+	sec, nanos := dur.Seconds, dur.Nanos
+	sign := ""
+	if sec < 0 || nanos < 0 {
+		sign, sec, nanos = "-", -sec, -nanos
+	}
+	s := fmt.Sprintf("%s%d.%09d", sign, sec, nanos)
+	s = strings.TrimSuffix(s, "000")
+	s = strings.TrimSuffix(s, "000")
+	s = strings.TrimSuffix(s, ".000")
+	return s + "s"
+}
+
+// parseDuration parses s in the protojson format, falling back to the time.ParseDuration
+// format for backward compatibility.
+func parseDuration(s string) (*durationpb.Duration, error) {
+	if dur, ok := parseProtoJSONDuration(s); ok {
+		return dur, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return nil, err
+	}
+	return durationpb.New(d), nil
+}
+
+func parseProtoJSONDuration(s string) (*durationpb.Duration, bool) {
+	s, ok := strings.CutSuffix(s, "s")
+	if !ok {
+		return nil, false
+	}
+	s, neg := strings.CutPrefix(s, "-")
+	intPart, fracPart, hasFrac := strings.Cut(s, ".")
+	if intPart == "" || strings.ContainsAny(intPart, "+-") || len(fracPart) > 9 || (hasFrac && fracPart == "") {
+		return nil, false
+	}
+	sec, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	var nanos int64
+	if hasFrac {
+		if strings.ContainsAny(fracPart, "+-") {
+			return nil, false
+		}
+		nanos, err = strconv.ParseInt(fracPart+strings.Repeat("0", 9-len(fracPart)), 10, 32)
+		if err != nil {
+			return nil, false
+		}
+	}
+	if neg {
+		sec, nanos = -sec, -nanos
+	}
+	return &durationpb.Duration{Seconds: sec, Nanos: int32(nanos)}, true
+}
+
+func decodeDurationDocument(vr bsonrw.ValueReader) (*durationpb.Duration, error) {
+	var seconds, nanos int64
+	err := bsonutil.ReadDocument(vr, func(key string, vr bsonrw.ValueReader) error {
+		var err error
+		switch key {
+		case "seconds":
+			seconds, err = bsonutil.ReadInt64(vr)
+		case "nanos":
+			nanos, err = bsonutil.ReadInt64(vr)
+		default:
+			err = fmt.Errorf("unexpected key %q in a *durationpb.Duration document", key)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if nanos < math.MinInt32 || nanos > math.MaxInt32 {
+		return nil, fmt.Errorf("nanos %d out of range", nanos)
+	}
+	return &durationpb.Duration{Seconds: seconds, Nanos: int32(nanos)}, nil
 }
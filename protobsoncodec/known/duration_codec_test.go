@@ -5,9 +5,11 @@ import (
 	"testing"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"gotest.tools/v3/assert"
@@ -82,4 +84,113 @@ func TestDurationCodec(t *testing.T) {
 			})
 		}
 	})
+	t.Run("Formats", func(t *testing.T) {
+		dur := &durationpb.Duration{Seconds: 1, Nanos: 500000000}
+		for _, params := range []struct {
+			name string
+			opts *protobsonoptions.DurationCodecOptions
+			want interface{}
+		}{
+			{
+				"Nanos",
+				protobsonoptions.DurationCodec(),
+				int64(1500000000),
+			},
+			{
+				"Millis",
+				protobsonoptions.DurationCodec().SetFormat(protobsonoptions.DurationFormatMillis),
+				int64(1500),
+			},
+			{
+				"Seconds",
+				protobsonoptions.DurationCodec().SetFormat(protobsonoptions.DurationFormatSeconds),
+				1.5,
+			},
+			{
+				"Document",
+				protobsonoptions.DurationCodec().SetFormat(protobsonoptions.DurationFormatDocument),
+				bson.D{{Key: "seconds", Value: int64(1)}, {Key: "nanos", Value: int32(500000000)}},
+			},
+			{
+				"String",
+				protobsonoptions.DurationCodec().SetFormat(protobsonoptions.DurationFormatString),
+				"1.500s",
+			},
+			{
+				"Decimal128",
+				protobsonoptions.DurationCodec().SetFormat(protobsonoptions.DurationFormatDecimal128),
+				decimal128("1.5"),
+			},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				c := NewDurationCodec(params.opts)
+				got := encodeValue(t, c, dur)
+				assert.DeepEqual(t, rawValue(t, params.want), got)
+				dec, err := decodeValue(t, c, got, TypeDuration)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, dur, dec, protocmp.Transform())
+			})
+		}
+	})
+	t.Run("DecodeNumbers", func(t *testing.T) {
+		for _, params := range []struct {
+			name string
+			opts *protobsonoptions.DurationCodecOptions
+			val  interface{}
+			want *durationpb.Duration
+		}{
+			{
+				"Int32Nanos",
+				protobsonoptions.DurationCodec(),
+				int32(-1500),
+				&durationpb.Duration{Nanos: -1500},
+			},
+			{
+				"Int32Millis",
+				protobsonoptions.DurationCodec().SetFormat(protobsonoptions.DurationFormatMillis),
+				int32(-1500),
+				&durationpb.Duration{Seconds: -1, Nanos: -500000000},
+			},
+			{
+				"DoubleMillis",
+				protobsonoptions.DurationCodec().SetFormat(protobsonoptions.DurationFormatMillis),
+				1500.5,
+				&durationpb.Duration{Seconds: 1, Nanos: 500500000},
+			},
+			{
+				"DoubleSeconds",
+				protobsonoptions.DurationCodec().SetFormat(protobsonoptions.DurationFormatSeconds),
+				-0.25,
+				&durationpb.Duration{Nanos: -250000000},
+			},
+			{
+				"LongString",
+				protobsonoptions.DurationCodec(),
+				"315576000000s",
+				&durationpb.Duration{Seconds: 315576000000},
+			},
+			{
+				"GoString",
+				protobsonoptions.DurationCodec(),
+				"1h30m",
+				durationpb.New(90 * time.Minute),
+			},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				c := NewDurationCodec(params.opts)
+				got, err := decodeValue(t, c, rawValue(t, params.val), TypeDuration)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, got, protocmp.Transform())
+			})
+		}
+	})
+	t.Run("OutOfRange", func(t *testing.T) {
+		c := NewDurationCodec()
+		err := c.EncodeValue(bsoncodec.EncodeContext{}, &bsonrwtest.ValueReaderWriter{}, reflect.ValueOf(&durationpb.Duration{Seconds: 315576000001}))
+		assert.ErrorContains(t, err, "exceeds +10000 years")
+		err = c.EncodeValue(bsoncodec.EncodeContext{}, &bsonrwtest.ValueReaderWriter{}, reflect.ValueOf(&durationpb.Duration{Seconds: 315576000000}))
+		assert.ErrorContains(t, err, "cannot be encoded as 64-bit integer nanoseconds")
+		_, err = decodeValue(t, c, rawValue(t, "315576000001s"), TypeDuration)
+		assert.ErrorContains(t, err, "exceeds +10000 years")
+	})
 }
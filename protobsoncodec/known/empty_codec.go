@@ -0,0 +1,77 @@
+package known
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Empty type.
+var TypeEmpty = reflect.TypeOf((*emptypb.Empty)(nil))
+
+// EmptyCodec is the Codec used for *emptypb.Empty values.
+//
+// Empty values are encoded as empty documents, like protojson does. Any document decodes
+// into an *emptypb.Empty, its elements being ignored.
+type EmptyCodec struct{}
+
+// EncodeValue is the ValueEncoderFunc for *emptypb.Empty.
+func (c *EmptyCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
+	if !v.IsValid() || v.Type() != TypeEmpty {
+		return bsoncodec.ValueEncoderError{
+			Name:     "EmptyCodec.EncodeValue",
+			Types:    []reflect.Type{TypeEmpty},
+			Received: v,
+		}
+	}
+	if v.IsNil() {
+		return vw.WriteNull()
+	}
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+	return dw.WriteDocumentEnd()
+}
+
+// DecodeValue is the ValueDecoderFunc for *emptypb.Empty.
+func (c *EmptyCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) error {
+	if !v.CanSet() || v.Type() != TypeEmpty {
+		return bsoncodec.ValueDecoderError{
+			Name:     "EmptyCodec.DecodeValue",
+			Types:    []reflect.Type{TypeEmpty},
+			Received: v,
+		}
+	}
+	var val *emptypb.Empty
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.EmbeddedDocument:
+		if err := vr.Skip(); err != nil {
+			return err
+		}
+		val = &emptypb.Empty{}
+	case bsontype.Null:
+		if err := vr.ReadNull(); err != nil {
+			return err
+		}
+		val = nil
+	case bsontype.Undefined:
+		if err := vr.ReadUndefined(); err != nil {
+			return err
+		}
+		val = &emptypb.Empty{}
+	default:
+		return fmt.Errorf("cannot decode %v into a *emptypb.Empty", bsonTyp)
+	}
+	v.Set(reflect.ValueOf(val))
+	return nil
+}
+
+// NewEmptyCodec returns an EmptyCodec.
+func NewEmptyCodec() *EmptyCodec {
+	return &EmptyCodec{}
+}
@@ -0,0 +1,84 @@
+package known
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"gotest.tools/v3/assert"
+)
+
+func TestEmptyCodec(t *testing.T) {
+	t.Run("EncodeToBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			val  *emptypb.Empty
+			vw   *bsonrwtest.ValueReaderWriter
+			want bsonrwtest.Invoked
+		}{
+			{
+				nil,
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.Null},
+				bsonrwtest.WriteNull,
+			},
+			{
+				&emptypb.Empty{},
+				&bsonrwtest.ValueReaderWriter{BSONType: bsontype.EmbeddedDocument},
+				bsonrwtest.WriteDocumentEnd,
+			},
+		} {
+			c := NewEmptyCodec()
+			v := reflect.ValueOf(params.val)
+			err := c.EncodeValue(bsoncodec.EncodeContext{}, params.vw, v)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, params.want, params.vw.Invoked)
+		}
+	})
+	t.Run("DecodeFromBsontype", func(t *testing.T) {
+		for _, params := range []struct {
+			vr   *bsonrwtest.ValueReaderWriter
+			want *emptypb.Empty
+		}{
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.EmbeddedDocument,
+				},
+				&emptypb.Empty{},
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Null,
+				},
+				nil,
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Undefined,
+				},
+				&emptypb.Empty{},
+			},
+		} {
+			t.Run(params.vr.Type().String(), func(t *testing.T) {
+				c := NewEmptyCodec()
+				got := reflect.New(reflect.TypeOf(params.want)).Elem()
+				err := c.DecodeValue(bsoncodec.DecodeContext{}, params.vr, got)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, got.Interface(), protocmp.Transform())
+			})
+		}
+	})
+	t.Run("RoundTrip", func(t *testing.T) {
+		c := NewEmptyCodec()
+		got := encodeValue(t, c, &emptypb.Empty{})
+		assert.DeepEqual(t, rawValue(t, bson.D{}), got)
+		dec, err := decodeValue(t, c, rawValue(t, bson.D{{Key: "ignored", Value: int32(1)}}), TypeEmpty)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, &emptypb.Empty{}, dec, protocmp.Transform())
+		_, err = decodeValue(t, c, rawValue(t, "{}"), TypeEmpty)
+		assert.Assert(t, err != nil)
+	})
+}
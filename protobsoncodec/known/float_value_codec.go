@@ -3,11 +3,12 @@ package known
 import (
 	"fmt"
 	"reflect"
-	"strconv"
 
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -15,7 +16,14 @@ import (
 var TypeFloatValue = reflect.TypeOf((*wrapperspb.FloatValue)(nil))
 
 // FloatValueCodec is the Codec used for *wrapperspb.FloatValue values.
-type FloatValueCodec struct{}
+//
+// Values are encoded as doubles. Double, Int32, Int64, Decimal128 and numeric String values
+// are decoded, failing if they overflow a float32.
+type FloatValueCodec struct {
+	NonFiniteFormat protobsonoptions.NonFiniteFormat
+	RoundFloat32    bool
+	NilFormat       protobsonoptions.NilFormat
+}
 
 // EncodeValue is the ValueEncoderFunc for *wrapperspb.FloatValue.
 func (c *FloatValueCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
@@ -30,7 +38,7 @@ func (c *FloatValueCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.Valu
 	if val == nil {
 		return vw.WriteNull()
 	}
-	return vw.WriteDouble(float64(val.Value))
+	return bsonutil.WriteFloat32(vw, val.Value, c.NonFiniteFormat, c.RoundFloat32)
 }
 
 // DecodeValue is the ValueDecoderFunc for *wrapperspb.FloatValue.
@@ -44,22 +52,12 @@ func (c *FloatValueCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.Valu
 	}
 	var val *wrapperspb.FloatValue
 	switch bsonTyp := vr.Type(); bsonTyp {
-	case bsontype.Double:
-		v, err := vr.ReadDouble()
-		if err != nil {
-			return err
-		}
-		val = wrapperspb.Float(float32(v))
-	case bsontype.String:
-		s, err := vr.ReadString()
-		if err != nil {
-			return err
-		}
-		v, err := strconv.ParseFloat(s, 32)
+	case bsontype.Double, bsontype.Int32, bsontype.Int64, bsontype.Decimal128, bsontype.String:
+		f, err := bsonutil.ReadAnyFloat32(vr)
 		if err != nil {
 			return err
 		}
-		val = wrapperspb.Float(float32(v))
+		val = wrapperspb.Float(f)
 	case bsontype.Null:
 		if err := vr.ReadNull(); err != nil {
 			return err
@@ -77,7 +75,17 @@ func (c *FloatValueCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.Valu
 	return nil
 }
 
-// NewFloatValueCodec returns a FloatValueCodec.
-func NewFloatValueCodec() *FloatValueCodec {
-	return &FloatValueCodec{}
+// NewFloatValueCodec returns a FloatValueCodec with options opts.
+func NewFloatValueCodec(opts ...*protobsonoptions.FloatValueCodecOptions) *FloatValueCodec {
+	mergedOpts := protobsonoptions.MergeFloatValueCodecOptions(opts...)
+	return &FloatValueCodec{
+		NonFiniteFormat: *mergedOpts.NonFiniteFormat,
+		RoundFloat32:    *mergedOpts.RoundFloat32,
+		NilFormat:       *mergedOpts.NilFormat,
+	}
+}
+
+// OmitNil reports whether the MessageCodec omits unset fields of this type.
+func (c *FloatValueCodec) OmitNil() bool {
+	return c.NilFormat == protobsonoptions.NilFormatOmit
 }
@@ -1,12 +1,14 @@
 package known
 
 import (
+	"math"
 	"reflect"
 	"testing"
 
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 	"gotest.tools/v3/assert"
@@ -78,4 +80,54 @@ func TestFloatValueCodec(t *testing.T) {
 			})
 		}
 	})
+	t.Run("RoundFloat32", func(t *testing.T) {
+		val := wrapperspb.Float(0.1)
+		for _, params := range []struct {
+			round bool
+			want  float64
+		}{
+			{false, 0.10000000149011612},
+			{true, 0.1},
+		} {
+			c := NewFloatValueCodec(protobsonoptions.FloatValueCodec().SetRoundFloat32(params.round))
+			got := encodeValue(t, c, val)
+			assert.DeepEqual(t, rawValue(t, params.want), got)
+			dec, err := decodeValue(t, c, got, TypeFloatValue)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, val, dec, protocmp.Transform())
+		}
+	})
+	t.Run("NonFiniteFormat", func(t *testing.T) {
+		c := NewFloatValueCodec(protobsonoptions.FloatValueCodec().SetNonFiniteFormat(protobsonoptions.NonFiniteFormatString))
+		for _, params := range []struct {
+			val  float32
+			want string
+		}{
+			{float32(math.Inf(1)), "Infinity"},
+			{float32(math.Inf(-1)), "-Infinity"},
+		} {
+			got := encodeValue(t, c, wrapperspb.Float(params.val))
+			assert.DeepEqual(t, rawValue(t, params.want), got)
+			dec, err := decodeValue(t, c, got, TypeFloatValue)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, wrapperspb.Float(params.val), dec, protocmp.Transform())
+		}
+		got := encodeValue(t, c, wrapperspb.Float(float32(math.NaN())))
+		assert.DeepEqual(t, rawValue(t, "NaN"), got)
+		c = NewFloatValueCodec(protobsonoptions.FloatValueCodec().SetNonFiniteFormat(protobsonoptions.NonFiniteFormatReject))
+		_, err := tryEncodeValue(c, wrapperspb.Float(float32(math.NaN())))
+		assert.ErrorContains(t, err, "NaN cannot be encoded")
+	})
+	t.Run("Decode", func(t *testing.T) {
+		c := NewFloatValueCodec()
+		for _, val := range []interface{}{decimal128("1.5"), "1.5", "1.5e0", int32(1)} {
+			dec, err := decodeValue(t, c, rawValue(t, val), TypeFloatValue)
+			assert.NilError(t, err)
+			assert.Assert(t, dec.(*wrapperspb.FloatValue).Value >= 1)
+		}
+		for _, val := range []interface{}{1e39, "1e39", decimal128("-1E+39"), "one"} {
+			_, err := decodeValue(t, c, rawValue(t, val), TypeFloatValue)
+			assert.Assert(t, err != nil)
+		}
+	})
 }
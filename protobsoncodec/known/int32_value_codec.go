@@ -8,6 +8,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -15,7 +16,9 @@ import (
 var TypeInt32Value = reflect.TypeOf((*wrapperspb.Int32Value)(nil))
 
 // Int32ValueCodec is the Codec used for *wrapperspb.Int32Value values.
-type Int32ValueCodec struct{}
+type Int32ValueCodec struct {
+	NilFormat protobsonoptions.NilFormat
+}
 
 // EncodeValue is the ValueEncoderFunc for *wrapperspb.Int32Value.
 func (c *Int32ValueCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
@@ -77,7 +80,15 @@ func (c *Int32ValueCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.Valu
 	return nil
 }
 
-// NewInt32ValueCodec returns a Int32ValueCodec.
-func NewInt32ValueCodec() *Int32ValueCodec {
-	return &Int32ValueCodec{}
+// NewInt32ValueCodec returns a Int32ValueCodec with options opts.
+func NewInt32ValueCodec(opts ...*protobsonoptions.WrapperCodecOptions) *Int32ValueCodec {
+	mergedOpts := protobsonoptions.MergeWrapperCodecOptions(opts...)
+	return &Int32ValueCodec{
+		NilFormat: *mergedOpts.NilFormat,
+	}
+}
+
+// OmitNil reports whether the MessageCodec omits unset fields of this type.
+func (c *Int32ValueCodec) OmitNil() bool {
+	return c.NilFormat == protobsonoptions.NilFormatOmit
 }
@@ -7,6 +7,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 	"gotest.tools/v3/assert"
@@ -78,4 +79,9 @@ func TestInt32ValueCodec(t *testing.T) {
 			})
 		}
 	})
+	t.Run("NilFormat", func(t *testing.T) {
+		assert.Assert(t, !NewInt32ValueCodec().OmitNil())
+		c := NewInt32ValueCodec(protobsonoptions.WrapperCodec().SetNilFormat(protobsonoptions.NilFormatOmit))
+		assert.Assert(t, c.OmitNil())
+	})
 }
@@ -3,11 +3,12 @@ package known
 import (
 	"fmt"
 	"reflect"
-	"strconv"
 
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -15,7 +16,10 @@ import (
 var TypeInt64Value = reflect.TypeOf((*wrapperspb.Int64Value)(nil))
 
 // Int64ValueCodec is the Codec used for *wrapperspb.Int64Value values.
-type Int64ValueCodec struct{}
+type Int64ValueCodec struct {
+	Format    protobsonoptions.Int64Format
+	NilFormat protobsonoptions.NilFormat
+}
 
 // EncodeValue is the ValueEncoderFunc for *wrapperspb.Int64Value.
 func (c *Int64ValueCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
@@ -30,7 +34,7 @@ func (c *Int64ValueCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.Valu
 	if val == nil {
 		return vw.WriteNull()
 	}
-	return vw.WriteInt64(val.Value)
+	return bsonutil.WriteInt64(vw, val.Value, c.Format)
 }
 
 // DecodeValue is the ValueDecoderFunc for *wrapperspb.Int64Value.
@@ -44,28 +48,12 @@ func (c *Int64ValueCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.Valu
 	}
 	var val *wrapperspb.Int64Value
 	switch bsonTyp := vr.Type(); bsonTyp {
-	case bsontype.Int64:
-		v, err := vr.ReadInt64()
-		if err != nil {
-			return err
-		}
-		val = wrapperspb.Int64(v)
-	case bsontype.Int32:
-		v, err := vr.ReadInt32()
-		if err != nil {
-			return err
-		}
-		val = wrapperspb.Int64(int64(v))
-	case bsontype.String:
-		s, err := vr.ReadString()
+	case bsontype.Int64, bsontype.Int32, bsontype.Double, bsontype.Decimal128, bsontype.String:
+		i, err := bsonutil.ReadAnyInt64(vr)
 		if err != nil {
 			return err
 		}
-		v, err := strconv.ParseInt(s, 10, 64)
-		if err != nil {
-			return err
-		}
-		val = wrapperspb.Int64(v)
+		val = wrapperspb.Int64(i)
 	case bsontype.Null:
 		if err := vr.ReadNull(); err != nil {
 			return err
@@ -83,7 +71,16 @@ func (c *Int64ValueCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.Valu
 	return nil
 }
 
-// NewInt64ValueCodec returns a Int64ValueCodec.
-func NewInt64ValueCodec() *Int64ValueCodec {
-	return &Int64ValueCodec{}
+// NewInt64ValueCodec returns a Int64ValueCodec with options opts.
+func NewInt64ValueCodec(opts ...*protobsonoptions.Int64ValueCodecOptions) *Int64ValueCodec {
+	mergedOpts := protobsonoptions.MergeInt64ValueCodecOptions(opts...)
+	return &Int64ValueCodec{
+		Format:    *mergedOpts.Format,
+		NilFormat: *mergedOpts.NilFormat,
+	}
+}
+
+// OmitNil reports whether the MessageCodec omits unset fields of this type.
+func (c *Int64ValueCodec) OmitNil() bool {
+	return c.NilFormat == protobsonoptions.NilFormatOmit
 }
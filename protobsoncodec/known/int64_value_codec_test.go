@@ -1,12 +1,14 @@
 package known
 
 import (
+	"math"
 	"reflect"
 	"testing"
 
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 	"gotest.tools/v3/assert"
@@ -56,6 +58,20 @@ func TestInt64ValueCodec(t *testing.T) {
 				},
 				wrapperspb.Int64(42),
 			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Double,
+					Return:   float64(42),
+				},
+				wrapperspb.Int64(42),
+			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Decimal128,
+					Return:   decimal128("42"),
+				},
+				wrapperspb.Int64(42),
+			},
 			{
 				&bsonrwtest.ValueReaderWriter{
 					BSONType: bsontype.String,
@@ -85,4 +101,44 @@ func TestInt64ValueCodec(t *testing.T) {
 			})
 		}
 	})
+	t.Run("Formats", func(t *testing.T) {
+		val := wrapperspb.Int64(math.MinInt64)
+		for _, params := range []struct {
+			name string
+			opts *protobsonoptions.Int64ValueCodecOptions
+			want interface{}
+		}{
+			{
+				"Int64",
+				protobsonoptions.Int64ValueCodec().SetFormat(protobsonoptions.Int64FormatInt64),
+				int64(math.MinInt64),
+			},
+			{
+				"Decimal128",
+				protobsonoptions.Int64ValueCodec().SetFormat(protobsonoptions.Int64FormatDecimal128),
+				decimal128("-9223372036854775808"),
+			},
+			{
+				"String",
+				protobsonoptions.Int64ValueCodec().SetFormat(protobsonoptions.Int64FormatString),
+				"-9223372036854775808",
+			},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				c := NewInt64ValueCodec(params.opts)
+				got := encodeValue(t, c, val)
+				assert.DeepEqual(t, rawValue(t, params.want), got)
+				dec, err := decodeValue(t, c, got, TypeInt64Value)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, val, dec, protocmp.Transform())
+			})
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		c := NewInt64ValueCodec()
+		for _, val := range []interface{}{1.5, decimal128("9223372036854775808"), decimal128("1.5"), "1e3"} {
+			_, err := decodeValue(t, c, rawValue(t, val), TypeInt64Value)
+			assert.Assert(t, err != nil)
+		}
+	})
 }
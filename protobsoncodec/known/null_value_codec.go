@@ -0,0 +1,68 @@
+package known
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// NullValue type.
+var TypeNullValue = reflect.TypeOf(structpb.NullValue_NULL_VALUE)
+
+// NullValueCodec is the Codec used for structpb.NullValue values.
+//
+// NullValue values are encoded as BSON null, like protojson does. The integer 0 is also
+// accepted when decoding, as it is how NullValue values were encoded as plain enums.
+type NullValueCodec struct{}
+
+// EncodeValue is the ValueEncoderFunc for structpb.NullValue.
+func (c *NullValueCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
+	if !v.IsValid() || v.Type() != TypeNullValue {
+		return bsoncodec.ValueEncoderError{
+			Name:     "NullValueCodec.EncodeValue",
+			Types:    []reflect.Type{TypeNullValue},
+			Received: v,
+		}
+	}
+	return vw.WriteNull()
+}
+
+// DecodeValue is the ValueDecoderFunc for structpb.NullValue.
+func (c *NullValueCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) error {
+	if !v.CanSet() || v.Type() != TypeNullValue {
+		return bsoncodec.ValueDecoderError{
+			Name:     "NullValueCodec.DecodeValue",
+			Types:    []reflect.Type{TypeNullValue},
+			Received: v,
+		}
+	}
+	var err error
+	switch bsonTyp := vr.Type(); bsonTyp {
+	case bsontype.Null:
+		err = vr.ReadNull()
+	case bsontype.Undefined:
+		err = vr.ReadUndefined()
+	case bsontype.Int32:
+		var i32 int32
+		i32, err = vr.ReadInt32()
+		if err == nil && i32 != 0 {
+			err = fmt.Errorf("cannot decode %d into a structpb.NullValue", i32)
+		}
+	default:
+		return fmt.Errorf("cannot decode %v into a structpb.NullValue", bsonTyp)
+	}
+	if err != nil {
+		return err
+	}
+	v.Set(reflect.ValueOf(structpb.NullValue_NULL_VALUE))
+	return nil
+}
+
+// NewNullValueCodec returns a NullValueCodec.
+func NewNullValueCodec() *NullValueCodec {
+	return &NullValueCodec{}
+}
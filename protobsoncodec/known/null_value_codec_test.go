@@ -0,0 +1,44 @@
+package known
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"google.golang.org/protobuf/types/known/structpb"
+	"gotest.tools/v3/assert"
+)
+
+func TestNullValueCodec(t *testing.T) {
+	t.Run("EncodeToBsontype", func(t *testing.T) {
+		c := NewNullValueCodec()
+		vw := &bsonrwtest.ValueReaderWriter{BSONType: bsontype.Null}
+		err := c.EncodeValue(bsoncodec.EncodeContext{}, vw, reflect.ValueOf(structpb.NullValue_NULL_VALUE))
+		assert.NilError(t, err)
+		assert.DeepEqual(t, bsonrwtest.WriteNull, vw.Invoked)
+	})
+	t.Run("DecodeFromBsontype", func(t *testing.T) {
+		for _, vr := range []*bsonrwtest.ValueReaderWriter{
+			{BSONType: bsontype.Null},
+			{BSONType: bsontype.Undefined},
+			{BSONType: bsontype.Int32, Return: int32(0)},
+		} {
+			t.Run(vr.Type().String(), func(t *testing.T) {
+				c := NewNullValueCodec()
+				got := reflect.New(TypeNullValue).Elem()
+				err := c.DecodeValue(bsoncodec.DecodeContext{}, vr, got)
+				assert.NilError(t, err)
+				assert.Equal(t, structpb.NullValue_NULL_VALUE, got.Interface())
+			})
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		c := NewNullValueCodec()
+		for _, val := range []interface{}{int32(1), "NULL_VALUE"} {
+			_, err := decodeValue(t, c, rawValue(t, val), TypeNullValue)
+			assert.Assert(t, err != nil)
+		}
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -14,7 +15,9 @@ import (
 var TypeStringValue = reflect.TypeOf((*wrapperspb.StringValue)(nil))
 
 // StringValueCodec is the Codec used for *wrapperspb.StringValue values.
-type StringValueCodec struct{}
+type StringValueCodec struct {
+	NilFormat protobsonoptions.NilFormat
+}
 
 // EncodeValue is the ValueEncoderFunc for *wrapperspb.StringValue.
 func (c *StringValueCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
@@ -72,7 +75,15 @@ func (c *StringValueCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.Val
 	return nil
 }
 
-// NewStringValueCodec returns a StringValueCodec.
-func NewStringValueCodec() *StringValueCodec {
-	return &StringValueCodec{}
+// NewStringValueCodec returns a StringValueCodec with options opts.
+func NewStringValueCodec(opts ...*protobsonoptions.WrapperCodecOptions) *StringValueCodec {
+	mergedOpts := protobsonoptions.MergeWrapperCodecOptions(opts...)
+	return &StringValueCodec{
+		NilFormat: *mergedOpts.NilFormat,
+	}
+}
+
+// OmitNil reports whether the MessageCodec omits unset fields of this type.
+func (c *StringValueCodec) OmitNil() bool {
+	return c.NilFormat == protobsonoptions.NilFormatOmit
 }
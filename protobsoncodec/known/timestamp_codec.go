@@ -2,20 +2,40 @@ package known
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const nanosPerMilli = int64(time.Millisecond)
+
+// Range of the valid timestamps, from 0001-01-01 to 9999-12-31, in milliseconds since the Unix
+// epoch.
+const (
+	minTimestampMillis = -62135596800000
+	maxTimestampMillis = 253402300799999
+)
+
 // Timestamp type.
 var TypeTimestamp = reflect.TypeOf((*timestamppb.Timestamp)(nil))
 
 // TimestampCodec is the Codec used for *timestamppb.Timestamp values.
-type TimestampCodec struct{}
+//
+// Timestamps are encoded in Format, once checked to be valid, and decoded from any format,
+// whatever Format is. 64-bit integers count milliseconds since the Unix epoch, like BSON dates,
+// when they fall in the range of valid timestamps, and nanoseconds otherwise, which is how
+// TimestampFormatNanos encodes timestamps outside of a few days around the Unix epoch.
+type TimestampCodec struct {
+	Format          protobsonoptions.TimestampFormat
+	ErrorOnTruncate bool
+}
 
 // EncodeValue is the ValueEncoderFunc for *timestamppb.Timestamp.
 func (c *TimestampCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
@@ -30,7 +50,74 @@ func (c *TimestampCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.Value
 	if ts == nil {
 		return vw.WriteNull()
 	}
-	return vw.WriteDateTime(ts.AsTime().UnixMilli())
+	if err := ts.CheckValid(); err != nil {
+		return err
+	}
+	switch c.Format {
+	case protobsonoptions.TimestampFormatDocument:
+		dw, err := vw.WriteDocument()
+		if err != nil {
+			return err
+		}
+		evw, err := dw.WriteDocumentElement("seconds")
+		if err != nil {
+			return err
+		}
+		if err := evw.WriteInt64(ts.Seconds); err != nil {
+			return err
+		}
+		evw, err = dw.WriteDocumentElement("nanos")
+		if err != nil {
+			return err
+		}
+		if err := evw.WriteInt32(ts.Nanos); err != nil {
+			return err
+		}
+		return dw.WriteDocumentEnd()
+	case protobsonoptions.TimestampFormatString:
+		return vw.WriteString(ts.AsTime().Format(time.RFC3339Nano))
+	case protobsonoptions.TimestampFormatNanos:
+		if ts.Seconds > math.MaxInt64/int64(time.Second) || ts.Seconds < math.MinInt64/int64(time.Second) ||
+			(ts.Seconds == math.MaxInt64/int64(time.Second) && int64(ts.Nanos) > math.MaxInt64%int64(time.Second)) {
+			return fmt.Errorf("%v cannot be encoded as 64-bit integer nanoseconds", ts.AsTime())
+		}
+		return vw.WriteInt64(ts.Seconds*int64(time.Second) + int64(ts.Nanos))
+	case protobsonoptions.TimestampFormatBSONTimestamp:
+		if ts.Seconds < 0 || ts.Seconds > math.MaxUint32 {
+			return fmt.Errorf("%v cannot be encoded as a BSON timestamp", ts.AsTime())
+		}
+		if c.ErrorOnTruncate && ts.Nanos != 0 {
+			return fmt.Errorf("%v cannot be encoded as a BSON timestamp without losing precision", ts.AsTime())
+		}
+		return vw.WriteTimestamp(uint32(ts.Seconds), 1)
+	case protobsonoptions.TimestampFormatDateTimeNanos:
+		t := ts.AsTime()
+		dw, err := vw.WriteDocument()
+		if err != nil {
+			return err
+		}
+		evw, err := dw.WriteDocumentElement("date")
+		if err != nil {
+			return err
+		}
+		if err := evw.WriteDateTime(t.UnixMilli()); err != nil {
+			return err
+		}
+		evw, err = dw.WriteDocumentElement("nanos")
+		if err != nil {
+			return err
+		}
+		if err := evw.WriteInt32(int32(int64(t.Nanosecond()) % nanosPerMilli)); err != nil {
+			return err
+		}
+		return dw.WriteDocumentEnd()
+	default:
+		t := ts.AsTime()
+		if c.ErrorOnTruncate && int64(t.Nanosecond())%nanosPerMilli != 0 {
+			return fmt.Errorf("%v cannot be encoded as a BSON date without losing precision", t)
+		}
+		return vw.WriteDateTime(t.UnixMilli())
+	}
 }
 
 // DecodeValue is the ValueDecoderFunc for *timestamppb.Timestamp.
@@ -51,11 +138,15 @@ func (c *TimestampCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.Value
 		}
 		ts = timestamppb.New(time.UnixMilli(msec))
 	case bsontype.Int64:
-		msec, err := vr.ReadInt64()
+		i64, err := vr.ReadInt64()
 		if err != nil {
 			return err
 		}
-		ts = timestamppb.New(time.UnixMilli(msec))
+		if i64 >= minTimestampMillis && i64 <= maxTimestampMillis {
+			ts = timestamppb.New(time.UnixMilli(i64))
+		} else {
+			ts = timestamppb.New(time.Unix(0, i64))
+		}
 	case bsontype.String:
 		s, err := vr.ReadString()
 		if err != nil {
@@ -66,6 +157,18 @@ func (c *TimestampCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.Value
 			return err
 		}
 		ts = timestamppb.New(t)
+	case bsontype.Timestamp:
+		t, _, err := vr.ReadTimestamp()
+		if err != nil {
+			return err
+		}
+		ts = timestamppb.New(bsonutil.TimestampToTime(t))
+	case bsontype.EmbeddedDocument:
+		var err error
+		ts, err = decodeTimestampDocument(vr)
+		if err != nil {
+			return err
+		}
 	case bsontype.Null:
 		if err := vr.ReadNull(); err != nil {
 			return err
@@ -83,7 +186,55 @@ func (c *TimestampCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.Value
 	return nil
 }
 
-// NewTimestampCodec returns a TimestampCodec.
-func NewTimestampCodec() *TimestampCodec {
-	return &TimestampCodec{}
+// NewTimestampCodec returns a TimestampCodec with options opts.
+func NewTimestampCodec(opts ...*protobsonoptions.TimestampCodecOptions) *TimestampCodec {
+	mergedOpts := protobsonoptions.MergeTimestampCodecOptions(opts...)
+	return &TimestampCodec{
+		Format:          *mergedOpts.Format,
+		ErrorOnTruncate: *mergedOpts.ErrorOnTruncate,
+	}
+}
+
+// decodeTimestampDocument decodes both the {seconds, nanos} and {date, nanos} document forms.
+func decodeTimestampDocument(vr bsonrw.ValueReader) (*timestamppb.Timestamp, error) {
+	var (
+		seconds, nanos int64
+		date           *time.Time
+	)
+	err := bsonutil.ReadDocument(vr, func(key string, vr bsonrw.ValueReader) error {
+		var err error
+		switch key {
+		case "seconds":
+			seconds, err = bsonutil.ReadInt64(vr)
+		case "nanos":
+			nanos, err = bsonutil.ReadInt64(vr)
+		case "date":
+			var msec int64
+			msec, err = vr.ReadDateTime()
+			t := time.UnixMilli(msec)
+			date = &t
+		default:
+			err = fmt.Errorf("unexpected key %q in a *timestamppb.Timestamp document", key)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ts *timestamppb.Timestamp
+	if date != nil {
+		if nanos < 0 || nanos >= nanosPerMilli {
+			return nil, fmt.Errorf("sub-millisecond nanos %d out of range", nanos)
+		}
+		ts = timestamppb.New(date.Add(time.Duration(nanos)))
+	} else {
+		if nanos < math.MinInt32 || nanos > math.MaxInt32 {
+			return nil, fmt.Errorf("nanos %d out of range", nanos)
+		}
+		ts = &timestamppb.Timestamp{Seconds: seconds, Nanos: int32(nanos)}
+	}
+	if err := ts.CheckValid(); err != nil {
+		return nil, err
+	}
+	return ts, nil
 }
@@ -1,13 +1,18 @@
 package known
 
 import (
+	"math"
 	"reflect"
 	"testing"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"gotest.tools/v3/assert"
@@ -67,6 +72,16 @@ func TestTimestampCodec(t *testing.T) {
 				},
 				ts,
 			},
+			{
+				&bsonrwtest.ValueReaderWriter{
+					BSONType: bsontype.Timestamp,
+					Return: bsoncore.Value{
+						Type: bsontype.Timestamp,
+						Data: bsoncore.AppendTimestamp(nil, 1653911006, 0),
+					},
+				},
+				ts,
+			},
 			{
 				&bsonrwtest.ValueReaderWriter{
 					BSONType: bsontype.Null,
@@ -89,4 +104,134 @@ func TestTimestampCodec(t *testing.T) {
 			})
 		}
 	})
+	t.Run("Formats", func(t *testing.T) {
+		ts := timestamppb.New(time.Date(2022, 5, 30, 11, 43, 26, 123456789, time.UTC))
+		for _, params := range []struct {
+			name string
+			opts *protobsonoptions.TimestampCodecOptions
+			want interface{}
+		}{
+			{
+				"DateTime",
+				protobsonoptions.TimestampCodec(),
+				primitive.DateTime(1653911006123),
+			},
+			{
+				"Document",
+				protobsonoptions.TimestampCodec().SetFormat(protobsonoptions.TimestampFormatDocument),
+				bson.D{{Key: "seconds", Value: int64(1653911006)}, {Key: "nanos", Value: int32(123456789)}},
+			},
+			{
+				"String",
+				protobsonoptions.TimestampCodec().SetFormat(protobsonoptions.TimestampFormatString),
+				"2022-05-30T11:43:26.123456789Z",
+			},
+			{
+				"Nanos",
+				protobsonoptions.TimestampCodec().SetFormat(protobsonoptions.TimestampFormatNanos),
+				int64(1653911006123456789),
+			},
+			{
+				"DateTimeNanos",
+				protobsonoptions.TimestampCodec().SetFormat(protobsonoptions.TimestampFormatDateTimeNanos),
+				bson.D{{Key: "date", Value: primitive.DateTime(1653911006123)}, {Key: "nanos", Value: int32(456789)}},
+			},
+			{
+				"BSONTimestamp",
+				protobsonoptions.TimestampCodec().SetFormat(protobsonoptions.TimestampFormatBSONTimestamp),
+				primitive.Timestamp{T: 1653911006, I: 1},
+			},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				c := NewTimestampCodec(params.opts)
+				got := encodeValue(t, c, ts)
+				assert.DeepEqual(t, rawValue(t, params.want), got)
+				dec, err := decodeValue(t, c, got, TypeTimestamp)
+				assert.NilError(t, err)
+				switch params.name {
+				case "DateTime":
+					assert.DeepEqual(t, timestamppb.New(time.UnixMilli(1653911006123)), dec, protocmp.Transform())
+				case "BSONTimestamp":
+					assert.DeepEqual(t, timestamppb.New(time.Unix(1653911006, 0)), dec, protocmp.Transform())
+				default:
+					assert.DeepEqual(t, ts, dec, protocmp.Transform())
+				}
+			})
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for _, f := range []protobsonoptions.TimestampFormat{
+			protobsonoptions.TimestampFormatDateTime,
+			protobsonoptions.TimestampFormatDocument,
+			protobsonoptions.TimestampFormatString,
+			protobsonoptions.TimestampFormatNanos,
+			protobsonoptions.TimestampFormatDateTimeNanos,
+			protobsonoptions.TimestampFormatBSONTimestamp,
+		} {
+			c := NewTimestampCodec(protobsonoptions.TimestampCodec().SetFormat(f))
+			for _, ts := range []*timestamppb.Timestamp{{Seconds: 1653911006, Nanos: -1}, {Seconds: 1653911006, Nanos: 1e9}, {Seconds: 253402300800}} {
+				_, err := tryEncodeValue(c, ts)
+				assert.Assert(t, err != nil, "format %d, timestamp %v", f, ts)
+			}
+		}
+	})
+	t.Run("Int64Unit", func(t *testing.T) {
+		for _, params := range []struct {
+			i64  int64
+			want *timestamppb.Timestamp
+		}{
+			{1653911006123, timestamppb.New(time.UnixMilli(1653911006123))},
+			{-62135596800000, timestamppb.New(time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC))},
+			{253402300799999, timestamppb.New(time.Date(9999, 12, 31, 23, 59, 59, 999000000, time.UTC))},
+			{1653911006123456789, timestamppb.New(time.Unix(0, 1653911006123456789))},
+			{-62135596800001, timestamppb.New(time.Unix(0, -62135596800001))},
+			{math.MaxInt64, timestamppb.New(time.Unix(0, math.MaxInt64))},
+		} {
+			for _, format := range []protobsonoptions.TimestampFormat{
+				protobsonoptions.TimestampFormatDateTime,
+				protobsonoptions.TimestampFormatDocument,
+				protobsonoptions.TimestampFormatString,
+				protobsonoptions.TimestampFormatNanos,
+				protobsonoptions.TimestampFormatBSONTimestamp,
+				protobsonoptions.TimestampFormatDateTimeNanos,
+			} {
+				c := NewTimestampCodec(protobsonoptions.TimestampCodec().SetFormat(format))
+				dec, err := decodeValue(t, c, rawValue(t, params.i64), TypeTimestamp)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.want, dec, protocmp.Transform())
+			}
+		}
+	})
+	t.Run("ErrorOnTruncate", func(t *testing.T) {
+		c := NewTimestampCodec(protobsonoptions.TimestampCodec().SetErrorOnTruncate(true))
+		v := reflect.ValueOf(timestamppb.New(time.Date(2022, 5, 30, 11, 43, 26, 123456789, time.UTC)))
+		err := c.EncodeValue(bsoncodec.EncodeContext{}, &bsonrwtest.ValueReaderWriter{}, v)
+		assert.ErrorContains(t, err, "without losing precision")
+		v = reflect.ValueOf(timestamppb.New(time.Date(2022, 5, 30, 11, 43, 26, 123000000, time.UTC)))
+		err = c.EncodeValue(bsoncodec.EncodeContext{}, &bsonrwtest.ValueReaderWriter{}, v)
+		assert.NilError(t, err)
+	})
+	t.Run("BSONTimestampOrdinal", func(t *testing.T) {
+		c := NewTimestampCodec()
+		dec, err := decodeValue(t, c, rawValue(t, primitive.Timestamp{T: 1653911006, I: 4000000000}), TypeTimestamp)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, timestamppb.New(time.Unix(1653911006, 0)), dec, protocmp.Transform())
+		c = NewTimestampCodec(protobsonoptions.TimestampCodec().SetFormat(protobsonoptions.TimestampFormatBSONTimestamp).SetErrorOnTruncate(true))
+		_, err = tryEncodeValue(c, timestamppb.New(time.Unix(1653911006, 1)))
+		assert.ErrorContains(t, err, "without losing precision")
+		got := encodeValue(t, c, timestamppb.New(time.Unix(1653911006, 0)))
+		assert.DeepEqual(t, rawValue(t, primitive.Timestamp{T: 1653911006, I: 1}), got)
+	})
+	t.Run("BSONTimestampOutOfRange", func(t *testing.T) {
+		c := NewTimestampCodec(protobsonoptions.TimestampCodec().SetFormat(protobsonoptions.TimestampFormatBSONTimestamp))
+		v := reflect.ValueOf(timestamppb.New(time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC)))
+		err := c.EncodeValue(bsoncodec.EncodeContext{}, &bsonrwtest.ValueReaderWriter{}, v)
+		assert.ErrorContains(t, err, "cannot be encoded as a BSON timestamp")
+	})
+	t.Run("NanosOverflow", func(t *testing.T) {
+		c := NewTimestampCodec(protobsonoptions.TimestampCodec().SetFormat(protobsonoptions.TimestampFormatNanos))
+		v := reflect.ValueOf(timestamppb.New(time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC)))
+		err := c.EncodeValue(bsoncodec.EncodeContext{}, &bsonrwtest.ValueReaderWriter{}, v)
+		assert.ErrorContains(t, err, "cannot be encoded")
+	})
 }
@@ -8,6 +8,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -15,7 +16,9 @@ import (
 var TypeUInt32Value = reflect.TypeOf((*wrapperspb.UInt32Value)(nil))
 
 // UInt32ValueCodec is the Codec used for *wrapperspb.UInt32Value values.
-type UInt32ValueCodec struct{}
+type UInt32ValueCodec struct {
+	NilFormat protobsonoptions.NilFormat
+}
 
 // EncodeValue is the ValueEncoderFunc for *wrapperspb.UInt32Value.
 func (vc *UInt32ValueCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
@@ -77,7 +80,15 @@ func (vc *UInt32ValueCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.Va
 	return nil
 }
 
-// NewUInt32ValueCodec returns a UInt32ValueCodec.
-func NewUInt32ValueCodec() *UInt32ValueCodec {
-	return &UInt32ValueCodec{}
+// NewUInt32ValueCodec returns a UInt32ValueCodec with options opts.
+func NewUInt32ValueCodec(opts ...*protobsonoptions.WrapperCodecOptions) *UInt32ValueCodec {
+	mergedOpts := protobsonoptions.MergeWrapperCodecOptions(opts...)
+	return &UInt32ValueCodec{
+		NilFormat: *mergedOpts.NilFormat,
+	}
+}
+
+// OmitNil reports whether the MessageCodec omits unset fields of this type.
+func (c *UInt32ValueCodec) OmitNil() bool {
+	return c.NilFormat == protobsonoptions.NilFormatOmit
 }
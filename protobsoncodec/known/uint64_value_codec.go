@@ -3,11 +3,12 @@ package known
 import (
 	"fmt"
 	"reflect"
-	"strconv"
 
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsonutil"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -15,7 +16,10 @@ import (
 var TypeUInt64Value = reflect.TypeOf((*wrapperspb.UInt64Value)(nil))
 
 // UInt64ValueCodec is the Codec used for *wrapperspb.UInt64Value values.
-type UInt64ValueCodec struct{}
+type UInt64ValueCodec struct {
+	Format    protobsonoptions.UInt64Format
+	NilFormat protobsonoptions.NilFormat
+}
 
 // EncodeValue is the ValueEncoderFunc for *wrapperspb.UInt64Value.
 func (c *UInt64ValueCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
@@ -30,7 +34,7 @@ func (c *UInt64ValueCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.Val
 	if val == nil {
 		return vw.WriteNull()
 	}
-	return vw.WriteInt64(int64(val.Value))
+	return bsonutil.WriteUInt64(vw, val.Value, c.Format)
 }
 
 // DecodeValue is the ValueDecoderFunc for *wrapperspb.UInt64Value.
@@ -44,28 +48,12 @@ func (c *UInt64ValueCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.Val
 	}
 	var val *wrapperspb.UInt64Value
 	switch bsonTyp := vr.Type(); bsonTyp {
-	case bsontype.Int64:
-		v, err := vr.ReadInt64()
-		if err != nil {
-			return err
-		}
-		val = wrapperspb.UInt64(uint64(v))
-	case bsontype.Int32:
-		v, err := vr.ReadInt32()
-		if err != nil {
-			return err
-		}
-		val = wrapperspb.UInt64(uint64(v))
-	case bsontype.String:
-		s, err := vr.ReadString()
+	case bsontype.Int64, bsontype.Int32, bsontype.Double, bsontype.Decimal128, bsontype.String:
+		u, err := bsonutil.ReadUInt64(vr)
 		if err != nil {
 			return err
 		}
-		v, err := strconv.ParseUint(s, 10, 64)
-		if err != nil {
-			return err
-		}
-		val = wrapperspb.UInt64(v)
+		val = wrapperspb.UInt64(u)
 	case bsontype.Null:
 		if err := vr.ReadNull(); err != nil {
 			return err
@@ -83,7 +71,16 @@ func (c *UInt64ValueCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.Val
 	return nil
 }
 
-// NewUInt64ValueCodec returns a UInt64ValueCodec.
-func NewUInt64ValueCodec() *UInt64ValueCodec {
-	return &UInt64ValueCodec{}
+// NewUInt64ValueCodec returns a UInt64ValueCodec with options opts.
+func NewUInt64ValueCodec(opts ...*protobsonoptions.UInt64ValueCodecOptions) *UInt64ValueCodec {
+	mergedOpts := protobsonoptions.MergeUInt64ValueCodecOptions(opts...)
+	return &UInt64ValueCodec{
+		Format:    *mergedOpts.Format,
+		NilFormat: *mergedOpts.NilFormat,
+	}
+}
+
+// OmitNil reports whether the MessageCodec omits unset fields of this type.
+func (c *UInt64ValueCodec) OmitNil() bool {
+	return c.NilFormat == protobsonoptions.NilFormatOmit
 }
@@ -1,12 +1,14 @@
 package known
 
 import (
+	"math"
 	"reflect"
 	"testing"
 
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/protobsonoptions"
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 	"gotest.tools/v3/assert"
@@ -85,4 +87,43 @@ func TestUInt64ValueCodec(t *testing.T) {
 			})
 		}
 	})
+	t.Run("Formats", func(t *testing.T) {
+		val := wrapperspb.UInt64(math.MaxUint64)
+		for _, params := range []struct {
+			name string
+			opts *protobsonoptions.UInt64ValueCodecOptions
+			want interface{}
+		}{
+			{
+				"Decimal128",
+				protobsonoptions.UInt64ValueCodec().SetFormat(protobsonoptions.UInt64FormatDecimal128),
+				decimal128("18446744073709551615"),
+			},
+			{
+				"String",
+				protobsonoptions.UInt64ValueCodec().SetFormat(protobsonoptions.UInt64FormatString),
+				"18446744073709551615",
+			},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				c := NewUInt64ValueCodec(params.opts)
+				got := encodeValue(t, c, val)
+				assert.DeepEqual(t, rawValue(t, params.want), got)
+				dec, err := decodeValue(t, c, got, TypeUInt64Value)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, val, dec, protocmp.Transform())
+			})
+		}
+		c := NewUInt64ValueCodec(protobsonoptions.UInt64ValueCodec().SetFormat(protobsonoptions.UInt64FormatString))
+		assert.DeepEqual(t, rawValue(t, "00000000000000000042"), encodeValue(t, c, wrapperspb.UInt64(42)))
+	})
+	t.Run("Overflow", func(t *testing.T) {
+		c := NewUInt64ValueCodec()
+		err := c.EncodeValue(bsoncodec.EncodeContext{}, &bsonrwtest.ValueReaderWriter{}, reflect.ValueOf(wrapperspb.UInt64(math.MaxInt64+1)))
+		assert.ErrorContains(t, err, "overflows int64")
+		for _, val := range []interface{}{int64(-1), -1.0, 1.5, decimal128("18446744073709551616")} {
+			_, err := decodeValue(t, c, rawValue(t, val), TypeUInt64Value)
+			assert.Assert(t, err != nil)
+		}
+	})
 }
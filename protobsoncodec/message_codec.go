@@ -1,21 +1,132 @@
 package protobsoncodec
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.vallahaye.net/protobson/internal/bsonutil"
 	"go.vallahaye.net/protobson/protobsonoptions"
+	"go.vallahaye.net/protobson/protobsonpb"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
 )
 
 // Message type.
 var TypeMessage = reflect.TypeOf((*proto.Message)(nil)).Elem()
 
-// MessageCodec is the Codec used for proto.Message values.
-type MessageCodec struct {
-	*bsoncodec.StructCodec
+var typeDynamicMessage = reflect.TypeOf((*dynamicpb.Message)(nil))
+
+const bytesValueName protoreflect.FullName = "google.protobuf.BytesValue"
+
+var stringFormats = map[protobsonpb.FieldOptions_StringFormat]protobsonoptions.StringFormat{
+	protobsonpb.FieldOptions_STRING:    protobsonoptions.StringFormatString,
+	protobsonpb.FieldOptions_OBJECT_ID: protobsonoptions.StringFormatObjectID,
+	protobsonpb.FieldOptions_UUID:      protobsonoptions.StringFormatUUID,
+}
+
+var scalarTypes = map[protoreflect.Kind]reflect.Type{
+	protoreflect.BoolKind:     reflect.TypeOf(false),
+	protoreflect.Int32Kind:    reflect.TypeOf(int32(0)),
+	protoreflect.Sint32Kind:   reflect.TypeOf(int32(0)),
+	protoreflect.Sfixed32Kind: reflect.TypeOf(int32(0)),
+	protoreflect.Int64Kind:    reflect.TypeOf(int64(0)),
+	protoreflect.Sint64Kind:   reflect.TypeOf(int64(0)),
+	protoreflect.Sfixed64Kind: reflect.TypeOf(int64(0)),
+	protoreflect.Uint32Kind:   reflect.TypeOf(uint32(0)),
+	protoreflect.Fixed32Kind:  reflect.TypeOf(uint32(0)),
+	protoreflect.Uint64Kind:   reflect.TypeOf(uint64(0)),
+	protoreflect.Fixed64Kind:  reflect.TypeOf(uint64(0)),
+	protoreflect.FloatKind:    reflect.TypeOf(float32(0)),
+	protoreflect.DoubleKind:   reflect.TypeOf(float64(0)),
+	protoreflect.StringKind:   reflect.TypeOf(""),
+	protoreflect.BytesKind:    reflect.TypeOf([]byte(nil)),
+}
+
+// NilOmitter is implemented by the Codecs of message types whose unset fields are omitted by
+// the MessageCodec rather than encoded as null.
+type NilOmitter interface {
+	OmitNil() bool
+}
+
+// valueCodec is implemented by the Codecs of Codecs.
+type valueCodec interface {
+	bsoncodec.ValueEncoder
+	bsoncodec.ValueDecoder
+}
+
+// structTags holds the struct tags of the Go fields of generated messages.
+type structTags struct {
+	Name      string
+	OmitEmpty bool
+	Skip      bool
+}
+
+type messageKey struct {
+	typ  reflect.Type
+	desc protoreflect.MessageDescriptor
+}
+
+type messageDescription struct {
+	fields []*fieldDescription
+	byName map[string]*fieldDescription
+	oneofs map[string]*oneofDescription
+}
+
+// oneofDescription describes a oneof, which is encoded like the interface field of generated
+// messages: as a document holding its set field, or null if none is set.
+type oneofDescription struct {
+	name      string
+	omitEmpty bool
+	od        protoreflect.OneofDescriptor
+	fields    []*fieldDescription
+	byName    map[string]*fieldDescription
+}
+
+type fieldDescription struct {
+	name      string
+	omitEmpty bool
+	fd        protoreflect.FieldDescriptor
+	enumType  protoreflect.EnumType
+	subtype   *byte // from the (protobson.field).binary_subtype option
+
+	stringFormat protobsonoptions.StringFormat
+	msgType      reflect.Type // Go type of singular message fields
+	codec        valueCodec   // from Codecs
+
+	generated protoreflect.MessageType // generated type of the messages of dynamic message fields
+	oneof     *oneofDescription
+	goIndex   []int // index of the Go struct field of generated messages
+}
+
+// nullPaths collects the dotted paths of the keys explicitly set to null in a document decoded
+// by DecodeWithNulls. A nil *nullPaths collects nothing.
+type nullPaths struct {
+	prefix string
+	paths  *[]string
+}
+
+func (n *nullPaths) add(key string) {
+	if n != nil {
+		*n.paths = append(*n.paths, n.prefix+key)
+	}
+}
+
+// child returns the nullPaths of the message field of key key.
+func (n *nullPaths) child(key string) *nullPaths {
+	if n == nil {
+		return nil
+	}
+	return &nullPaths{n.prefix + key + ".", n.paths}
 }
 
 // EncodeValue is the ValueEncoderFunc for proto.Message.
@@ -27,11 +138,66 @@ func (c *MessageCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWr
 			Received: v,
 		}
 	}
-	return c.StructCodec.EncodeValue(ec, vw, v.Elem())
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return vw.WriteNull()
+		}
+		// Some drivers look up the Codec of interface fields by their static type: dispatch to
+		// the Codec registered for their dynamic type, if any.
+		if encoder := c.typeEncoder(ec, v.Elem().Type()); encoder != nil {
+			return encoder.EncodeValue(ec, vw, v.Elem())
+		}
+		return c.EncodeValue(ec, vw, v.Elem())
+	case reflect.Ptr:
+		if v.IsNil() {
+			return vw.WriteNull()
+		}
+	case reflect.Struct:
+		if !v.CanAddr() {
+			ptr := reflect.New(v.Type())
+			ptr.Elem().Set(v)
+			v = ptr.Elem()
+		}
+		v = v.Addr()
+		// Codecs are registered for pointer types, while the driver looks up the struct type of
+		// top-level values: dispatch to the Codec registered for the pointer type, if any.
+		if encoder := c.typeEncoder(ec, v.Type()); encoder != nil {
+			return encoder.EncodeValue(ec, vw, v)
+		}
+	}
+	m := v.Interface().(proto.Message)
+	if mt := generatedType(m.ProtoReflect().Descriptor(), v.Type() == typeDynamicMessage); mt != nil && ec.Registry != nil {
+		gm := mt.New().Interface()
+		if err := convertMessage(gm, m); err != nil {
+			return err
+		}
+		encoder, err := ec.LookupEncoder(reflect.TypeOf(gm))
+		if err != nil {
+			return err
+		}
+		return encoder.EncodeValue(ec, vw, reflect.ValueOf(gm))
+	}
+	return c.encodeMessage(ec, vw, m.ProtoReflect())
 }
 
 // DecodeValue is the ValueDecoderFunc for proto.Message.
 func (c *MessageCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) error {
+	return c.decodeValue(dc, vr, v, nil)
+}
+
+// DecodeWithNulls is like DecodeValue but also returns the dotted paths of the fields explicitly
+// set to null in the decoded document, including those of its singular message fields decoded
+// by a MessageCodec. The paths can be used to build $unset updates.
+func (c *MessageCodec) DecodeWithNulls(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) ([]string, error) {
+	paths := []string{}
+	if err := c.decodeValue(dc, vr, v, &nullPaths{paths: &paths}); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+func (c *MessageCodec) decodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value, nulls *nullPaths) error {
 	if !v.CanSet() || (!v.Type().Implements(TypeMessage) && !reflect.PtrTo(v.Type()).Implements(TypeMessage)) {
 		return bsoncodec.ValueDecoderError{
 			Name:     "MessageCodec.DecodeValue",
@@ -39,69 +205,832 @@ func (c *MessageCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueRe
 			Received: v,
 		}
 	}
-	return c.StructCodec.DecodeValue(dc, vr, v.Elem())
+	switch v.Kind() {
+	case reflect.Ptr:
+		if vr.Type() == bsontype.Null {
+			v.Set(reflect.Zero(v.Type()))
+			return vr.ReadNull()
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+	case reflect.Struct:
+		v = v.Addr()
+		if decoder := c.typeDecoder(dc, v.Type()); decoder != nil {
+			return decodeMessageInto(dc, vr, decoder, v.Interface().(proto.Message))
+		}
+	}
+	m := v.Interface().(proto.Message)
+	if dm, ok := m.(*dynamicpb.Message); ok && dm.Descriptor() == nil {
+		return errors.New("cannot decode into a *dynamicpb.Message without descriptor")
+	}
+	if c.decodeZeroStruct() {
+		proto.Reset(m)
+	}
+	if mt := generatedType(m.ProtoReflect().Descriptor(), v.Type() == typeDynamicMessage); mt != nil && dc.Registry != nil {
+		gv := reflect.New(reflect.TypeOf(mt.Zero().Interface())).Elem()
+		gv.Set(reflect.ValueOf(mt.New().Interface()))
+		decoder, err := dc.LookupDecoder(gv.Type())
+		if err != nil {
+			return err
+		}
+		if err := decoder.DecodeValue(dc, vr, gv); err != nil {
+			return err
+		}
+		if gv.IsNil() {
+			return nil
+		}
+		return convertMessage(m, gv.Interface().(proto.Message))
+	}
+	return c.decodeMessage(dc, vr, m.ProtoReflect(), nulls)
+}
+
+func (c *MessageCodec) encodeMessage(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, m protoreflect.Message) error {
+	desc, err := c.describe(m)
+	if err != nil {
+		return err
+	}
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+	for _, f := range desc.fields {
+		if o := f.oneof; o != nil {
+			// Oneofs are written in place of their first field, like in generated messages.
+			if o.fields[0] == f {
+				if err := c.encodeOneof(ec, dw, o, m); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		has := m.Has(f.fd)
+		if !has && (f.omitEmpty || c.omitNil(ec, f)) {
+			continue
+		}
+		if has && f.omitEmpty && c.encodeOmitDefaultStruct() && f.msgType != nil && proto.Size(m.Get(f.fd).Message().Interface()) == 0 {
+			continue
+		}
+		evw, err := dw.WriteDocumentElement(f.name)
+		if err != nil {
+			return err
+		}
+		if !has && (f.fd.HasPresence() || isNilField(m, f)) {
+			err = evw.WriteNull()
+		} else {
+			err = c.encodeField(ec, evw, f, m.Get(f.fd))
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.fd.FullName(), err)
+		}
+	}
+	return dw.WriteDocumentEnd()
+}
+
+func (c *MessageCodec) encodeOneof(ec bsoncodec.EncodeContext, dw bsonrw.DocumentWriter, o *oneofDescription, m protoreflect.Message) error {
+	fd := m.WhichOneof(o.od)
+	if fd == nil && o.omitEmpty {
+		return nil
+	}
+	vw, err := dw.WriteDocumentElement(o.name)
+	if err != nil {
+		return err
+	}
+	if fd == nil {
+		return vw.WriteNull()
+	}
+	odw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+	for _, f := range o.fields {
+		if f.fd.Number() != fd.Number() {
+			continue
+		}
+		evw, err := odw.WriteDocumentElement(f.name)
+		if err != nil {
+			return err
+		}
+		if err := c.encodeField(ec, evw, f, m.Get(fd)); err != nil {
+			return fmt.Errorf("%s: %w", fd.FullName(), err)
+		}
+	}
+	return odw.WriteDocumentEnd()
+}
+
+func (c *MessageCodec) encodeField(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, f *fieldDescription, v protoreflect.Value) error {
+	switch {
+	case f.fd.IsList():
+		list := v.List()
+		aw, err := vw.WriteArray()
+		if err != nil {
+			return err
+		}
+		for i := 0; i < list.Len(); i++ {
+			evw, err := aw.WriteArrayElement()
+			if err != nil {
+				return err
+			}
+			if err := c.encodeSingular(ec, evw, f, f.fd, list.Get(i)); err != nil {
+				return err
+			}
+		}
+		return aw.WriteArrayEnd()
+	case f.fd.IsMap():
+		mp := v.Map()
+		keys := make([]protoreflect.MapKey, 0, mp.Len())
+		mp.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+			keys = append(keys, k)
+			return true
+		})
+		sort.Slice(keys, func(i, j int) bool {
+			return lessMapKey(keys[i], keys[j])
+		})
+		dw, err := vw.WriteDocument()
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			evw, err := dw.WriteDocumentElement(k.String())
+			if err != nil {
+				return err
+			}
+			if err := c.encodeSingular(ec, evw, f, f.fd.MapValue(), mp.Get(k)); err != nil {
+				return err
+			}
+		}
+		return dw.WriteDocumentEnd()
+	default:
+		return c.encodeSingular(ec, vw, f, f.fd, v)
+	}
+}
+
+// encodeSingular encodes a single value of fd, which is either the descriptor of f or of its
+// map values.
+func (c *MessageCodec) encodeSingular(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, f *fieldDescription, fd protoreflect.FieldDescriptor, v protoreflect.Value) error {
+	if f.codec != nil {
+		rv, err := toGoValue(f, fd, v)
+		if err != nil {
+			return err
+		}
+		return f.codec.EncodeValue(ec, vw, rv)
+	}
+	switch fd.Kind() {
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return bsonutil.WriteInt64(vw, v.Int(), c.Int64Format)
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return bsonutil.WriteUInt64(vw, v.Uint(), c.UInt64Format)
+	case protoreflect.FloatKind:
+		return bsonutil.WriteFloat32(vw, float32(v.Float()), c.NonFiniteFormat, c.RoundFloat32)
+	case protoreflect.DoubleKind:
+		return bsonutil.WriteFloat64(vw, v.Float(), c.NonFiniteFormat)
+	case protoreflect.StringKind:
+		if f.stringFormat != protobsonoptions.StringFormatString {
+			return bsonutil.WriteString(vw, v.String(), f.stringFormat)
+		}
+	case protoreflect.BytesKind:
+		return vw.WriteBinaryWithSubtype(v.Bytes(), c.binarySubtype(f))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if f.subtype != nil && fd.Message().FullName() == bytesValueName {
+			m := v.Message()
+			return vw.WriteBinaryWithSubtype(m.Get(m.Descriptor().Fields().ByNumber(1)).Bytes(), *f.subtype)
+		}
+	}
+	rv, err := toGoValue(f, fd, v)
+	if err != nil {
+		return err
+	}
+	encoder, err := ec.LookupEncoder(rv.Type())
+	if err != nil {
+		return err
+	}
+	return encoder.EncodeValue(ec, vw, rv)
+}
+
+func (c *MessageCodec) decodeMessage(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, m protoreflect.Message, nulls *nullPaths) error {
+	desc, err := c.describe(m)
+	if err != nil {
+		return err
+	}
+	return bsonutil.ReadDocument(vr, func(key string, vr bsonrw.ValueReader) error {
+		if o, ok := desc.oneofs[key]; ok {
+			return c.decodeOneof(dc, vr, o, m, nulls)
+		}
+		f, ok := desc.byName[key]
+		if !ok {
+			return vr.Skip()
+		}
+		if err := c.decodeField(dc, vr, f, m, nulls); err != nil {
+			return fmt.Errorf("%s: %w", f.fd.FullName(), err)
+		}
+		return nil
+	})
+}
+
+func (c *MessageCodec) decodeOneof(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, o *oneofDescription, m protoreflect.Message, nulls *nullPaths) error {
+	if vr.Type() == bsontype.Null {
+		if fd := m.WhichOneof(o.od); fd != nil {
+			m.Clear(fd)
+		}
+		nulls.add(o.name)
+		return vr.ReadNull()
+	}
+	return bsonutil.ReadDocument(vr, func(key string, vr bsonrw.ValueReader) error {
+		f, ok := o.byName[key]
+		if !ok {
+			return vr.Skip()
+		}
+		if err := c.decodeField(dc, vr, f, m, nulls.child(o.name)); err != nil {
+			return fmt.Errorf("%s: %w", f.fd.FullName(), err)
+		}
+		return nil
+	})
+}
+
+func (c *MessageCodec) decodeField(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, f *fieldDescription, m protoreflect.Message, nulls *nullPaths) error {
+	// Null is the value of NullValue fields, which is decoded by their Codec and not reported.
+	if vr.Type() == bsontype.Null && !isNullValueField(f.fd) {
+		m.Clear(f.fd)
+		nulls.add(f.name)
+		return vr.ReadNull()
+	}
+	switch {
+	case f.fd.IsList():
+		m.Clear(f.fd)
+		list := m.Mutable(f.fd).List()
+		setNonNilField(m, f)
+		ar, err := vr.ReadArray()
+		if err != nil {
+			return err
+		}
+		for {
+			evr, err := ar.ReadValue()
+			if errors.Is(err, bsonrw.ErrEOA) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			v, err := c.decodeSingular(dc, evr, f, f.fd, list.NewElement, nil)
+			if err != nil {
+				return err
+			}
+			if !v.IsValid() {
+				return errors.New("cannot decode null into a repeated field element")
+			}
+			list.Append(v)
+		}
+	case f.fd.IsMap():
+		m.Clear(f.fd)
+		mp := m.Mutable(f.fd).Map()
+		setNonNilField(m, f)
+		return bsonutil.ReadDocument(vr, func(key string, vr bsonrw.ValueReader) error {
+			k, err := parseMapKey(f.fd.MapKey(), key)
+			if err != nil {
+				return err
+			}
+			v, err := c.decodeSingular(dc, vr, f, f.fd.MapValue(), mp.NewValue, nil)
+			if err != nil {
+				return err
+			}
+			if !v.IsValid() {
+				return errors.New("cannot decode null into a map field value")
+			}
+			mp.Set(k, v)
+			return nil
+		})
+	default:
+		v, err := c.decodeSingular(dc, vr, f, f.fd, func() protoreflect.Value {
+			return m.NewField(f.fd)
+		}, nulls.child(f.name))
+		if err != nil {
+			return err
+		}
+		if v.IsValid() {
+			m.Set(f.fd, v)
+		} else {
+			m.Clear(f.fd)
+		}
+		return nil
+	}
+}
+
+// decodeSingular decodes a single value of fd, which is either the descriptor of f or of its
+// map values. The returned value is invalid if a message Codec decoded a nil message. The null
+// keys of messages decoded by a MessageCodec are added to nulls.
+func (c *MessageCodec) decodeSingular(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, f *fieldDescription, fd protoreflect.FieldDescriptor, newValue func() protoreflect.Value, nulls *nullPaths) (protoreflect.Value, error) {
+	if f.codec != nil {
+		rv := newGoValue(f, fd, newValue)
+		if err := f.codec.DecodeValue(dc, vr, rv); err != nil {
+			return protoreflect.Value{}, err
+		}
+		return fromGoValue(f, fd, rv, newValue)
+	}
+	if fd.Kind() == protoreflect.StringKind && f.stringFormat != protobsonoptions.StringFormatString {
+		if vr.Type() == bsontype.Null {
+			return protoreflect.ValueOfString(""), vr.ReadNull()
+		}
+		s, err := bsonutil.ReadString(vr, f.stringFormat)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfString(s), nil
+	}
+	switch fd.Kind() {
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		i, err := bsonutil.ReadAnyInt64(vr)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfInt64(i), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		u, err := bsonutil.ReadUInt64(vr)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfUint64(u), nil
+	case protoreflect.FloatKind:
+		f, err := bsonutil.ReadAnyFloat32(vr)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfFloat32(f), nil
+	case protoreflect.DoubleKind:
+		f, err := bsonutil.ReadAnyFloat64(vr)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfFloat64(f), nil
+	case protoreflect.BytesKind:
+		b, err := bsonutil.ReadBytes(vr, c.DecodeBase64Strings)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfBytes(b), nil
+	}
+	rv := newGoValue(f, fd, newValue)
+	decoder, err := dc.LookupDecoder(rv.Type())
+	if err != nil {
+		return protoreflect.Value{}, err
+	}
+	if mc, ok := decoder.(*MessageCodec); ok && nulls != nil {
+		err = mc.decodeValue(dc, vr, rv, nulls)
+	} else {
+		err = decoder.DecodeValue(dc, vr, rv)
+	}
+	if err != nil {
+		return protoreflect.Value{}, err
+	}
+	return fromGoValue(f, fd, rv, newValue)
+}
+
+// toGoValue returns the Go value of v, a single value of fd, as passed to Codecs.
+func toGoValue(f *fieldDescription, fd protoreflect.FieldDescriptor, v protoreflect.Value) (reflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if f.generated != nil {
+			gm := f.generated.New().Interface()
+			if err := convertMessage(gm, v.Message().Interface()); err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(gm), nil
+		}
+		return reflect.ValueOf(v.Message().Interface()), nil
+	case protoreflect.EnumKind:
+		if f.enumType != nil {
+			return reflect.ValueOf(f.enumType.New(v.Enum())), nil
+		}
+		return reflect.ValueOf(v.Enum()), nil
+	default:
+		return reflect.ValueOf(v.Interface()), nil
+	}
+}
+
+// newGoValue returns a settable Go value for a single value of fd to be decoded into by Codecs.
+func newGoValue(f *fieldDescription, fd protoreflect.FieldDescriptor, newValue func() protoreflect.Value) reflect.Value {
+	var rv reflect.Value
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		var msg proto.Message
+		if f.generated != nil {
+			msg = f.generated.New().Interface()
+		} else {
+			msg = newValue().Message().Interface()
+		}
+		rv = reflect.New(reflect.TypeOf(msg)).Elem()
+		rv.Set(reflect.ValueOf(msg))
+	case protoreflect.EnumKind:
+		if f.enumType != nil {
+			rv = reflect.New(reflect.TypeOf(f.enumType.New(0))).Elem()
+		} else {
+			rv = reflect.New(reflect.TypeOf(protoreflect.EnumNumber(0))).Elem()
+		}
+	default:
+		rv = reflect.New(scalarTypes[fd.Kind()]).Elem()
+	}
+	return rv
 }
 
-// NewMessageCodec returns a MessageCodec with options opts.
-func NewMessageCodec(opts ...*protobsonoptions.MessageCodecOptions) *MessageCodec {
-	mergedOpts := protobsonoptions.MergeMessageCodecOptions(opts...)
-	parser := JSONPBFallbackStructTagParser
-	if mergedOpts.UseProtoNames != nil && *mergedOpts.UseProtoNames {
-		parser = ProtoNamesFallbackStructTagParser
+// fromGoValue returns the value of fd held by rv, a Go value returned by newGoValue. The
+// returned value is invalid if rv holds a nil message.
+func fromGoValue(f *fieldDescription, fd protoreflect.FieldDescriptor, rv reflect.Value, newValue func() protoreflect.Value) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if rv.IsNil() {
+			return protoreflect.Value{}, nil
+		}
+		if f.generated != nil {
+			v := newValue()
+			if err := convertMessage(v.Message().Interface(), rv.Interface().(proto.Message)); err != nil {
+				return protoreflect.Value{}, err
+			}
+			return v, nil
+		}
+		return protoreflect.ValueOfMessage(rv.Interface().(proto.Message).ProtoReflect()), nil
+	case protoreflect.EnumKind:
+		if f.enumType != nil {
+			return protoreflect.ValueOfEnum(rv.Interface().(protoreflect.Enum).Number()), nil
+		}
+		return protoreflect.ValueOfEnum(rv.Interface().(protoreflect.EnumNumber)), nil
+	default:
+		return protoreflect.ValueOf(rv.Interface()), nil
 	}
-	structCodec, _ := bsoncodec.NewStructCodec(parser, mergedOpts.StructCodecOptions)
-	return &MessageCodec{structCodec}
 }
 
-// JSONPBFallbackStructTagParser is the StructTagParser used by the MessageCodec by default.
-// It has the same behavior as bsoncodec.DefaultStructTagParser but will also fallback to
-// parsing the protobuf tag on a field where the bson tag isn't available. In this case, the
-// key will be taken from the json property, or from the name property if there is none.
-//
-// An example:
-//
-//	type T struct {
-//	  Name   string `protobuf:"bytes,1,opt,name=name,proto3"` // Key is "name"
-//	  FooBar string `protobuf:"bytes,2,opt,name=foo_bar,json=fooBar,proto3"` // Key is "fooBar"
-//	  BarFoo string `protobuf:"bytes,3,opt,name=bar_foo,json=barFoo,proto3" bson:"barfoo"` // Key is "barfoo"
-//	}
-var JSONPBFallbackStructTagParser bsoncodec.StructTagParserFunc = func(sf reflect.StructField) (bsoncodec.StructTags, error) {
-	if _, ok := sf.Tag.Lookup("bson"); ok {
-		return bsoncodec.DefaultStructTagParser(sf)
+// generatedType returns the generated type of the messages of descriptor md if they are
+// dynamic and such a type is registered, or nil.
+func generatedType(md protoreflect.MessageDescriptor, dynamic bool) protoreflect.MessageType {
+	if !dynamic {
+		return nil
 	}
-	tag, ok := sf.Tag.Lookup("protobuf")
-	if !ok {
-		return bsoncodec.DefaultStructTagParser(sf)
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(md.FullName())
+	if err != nil {
+		return nil
 	}
-	return parseTags(tag, false)
+	if _, ok := mt.New().Interface().(*dynamicpb.Message); ok {
+		return nil
+	}
+	return mt
+}
+
+// convertMessage merges src into dst, messages of the same full name but of different Go
+// types, such as a dynamic message and its generated counterpart.
+func convertMessage(dst, src proto.Message) error {
+	b, err := proto.MarshalOptions{AllowPartial: true}.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return proto.UnmarshalOptions{AllowPartial: true, Merge: true}.Unmarshal(b, dst)
 }
 
-// ProtoNamesFallbackStructTagParser has the same behavior as JSONPBFallbackStructTagParser
-// except it forces the use of the name property as the key when parsing protobuf tags.
-var ProtoNamesFallbackStructTagParser bsoncodec.StructTagParserFunc = func(sf reflect.StructField) (bsoncodec.StructTags, error) {
-	if _, ok := sf.Tag.Lookup("bson"); ok {
-		return bsoncodec.DefaultStructTagParser(sf)
+// describe returns the cached description of the fields of m.
+func (c *MessageCodec) describe(m protoreflect.Message) (*messageDescription, error) {
+	key := messageKey{reflect.TypeOf(m.Interface()), m.Descriptor()}
+	if desc, ok := c.cache.Load(key); ok {
+		return desc.(*messageDescription), nil
+	}
+	md := m.Descriptor()
+	tags := make(map[protoreflect.Name]structTags)
+	goIndexes := make(map[protoreflect.Name][]int)
+	oneofTags := make(map[protoreflect.Name]structTags)
+	if typ := key.typ; typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Struct {
+		for i := 0; i < typ.Elem().NumField(); i++ {
+			sf := typ.Elem().Field(i)
+			if sf.PkgPath != "" {
+				continue
+			}
+			tag, ok := sf.Tag.Lookup("protobuf")
+			oneofTag, isOneof := sf.Tag.Lookup("protobuf_oneof")
+			if !ok && !isOneof {
+				continue
+			}
+			st, err := c.parseStructTags(sf)
+			if err != nil {
+				return nil, err
+			}
+			if isOneof {
+				oneofTags[protoreflect.Name(oneofTag)] = st
+			} else {
+				name := protoreflect.Name(structTagProps(tag)["name"])
+				tags[name] = st
+				goIndexes[name] = sf.Index
+			}
+		}
+	}
+	desc := &messageDescription{
+		byName: make(map[string]*fieldDescription),
+		oneofs: make(map[string]*oneofDescription),
+	}
+	oneofs := make(map[protoreflect.Name]*oneofDescription)
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		f := &fieldDescription{
+			name:    fd.JSONName(),
+			fd:      fd,
+			goIndex: goIndexes[fd.Name()],
+		}
+		if c.useProtoNames {
+			f.name = string(fd.Name())
+		}
+		if st, ok := tags[fd.Name()]; ok {
+			if st.Skip {
+				continue
+			}
+			f.name = st.Name
+			f.omitEmpty = st.OmitEmpty
+		}
+		enumDesc := fd.Enum()
+		if fd.IsMap() {
+			enumDesc = fd.MapValue().Enum()
+		}
+		if enumDesc != nil {
+			if et, err := protoregistry.GlobalTypes.FindEnumByName(enumDesc.FullName()); err == nil {
+				f.enumType = et
+			}
+		}
+		if opts := fieldOptions(fd); opts.BinarySubtype != nil {
+			if opts.GetBinarySubtype() > math.MaxUint8 {
+				return nil, fmt.Errorf("%s: invalid binary subtype %d", fd.FullName(), opts.GetBinarySubtype())
+			}
+			subtype := byte(opts.GetBinarySubtype())
+			f.subtype = &subtype
+		}
+		valueDesc := fd
+		if fd.IsMap() {
+			valueDesc = fd.MapValue()
+		}
+		if md := valueDesc.Message(); md != nil {
+			f.generated = generatedType(md, key.typ == typeDynamicMessage)
+		}
+		if fd.Message() != nil && !fd.IsList() && !fd.IsMap() {
+			if f.generated != nil {
+				f.msgType = reflect.TypeOf(f.generated.Zero().Interface())
+			} else {
+				f.msgType = reflect.TypeOf(m.NewField(fd).Message().Interface())
+			}
+		}
+		if opts := fieldOptions(fd); opts.StringFormat != nil {
+			f.stringFormat = stringFormats[opts.GetStringFormat()]
+		}
+		if sf, ok := c.StringFormats[fd.FullName()]; ok {
+			f.stringFormat = sf
+		}
+		if f.stringFormat != protobsonoptions.StringFormatString && fd.Kind() != protoreflect.StringKind && !(fd.IsMap() && fd.MapValue().Kind() == protoreflect.StringKind) {
+			return nil, fmt.Errorf("%s: string format set on a %v field", fd.FullName(), fd.Kind())
+		}
+		f.codec = c.Codecs[fd.FullName()]
+		if md := valueDesc.Message(); f.codec == nil && md != nil {
+			f.codec = c.Codecs[md.FullName()]
+		}
+		if isOneofField(fd) {
+			od := fd.ContainingOneof()
+			o := oneofs[od.Name()]
+			if o == nil {
+				o = &oneofDescription{
+					name:   oneofKey(od.Name()),
+					od:     od,
+					byName: make(map[string]*fieldDescription),
+				}
+				if st, ok := oneofTags[od.Name()]; ok {
+					if st.Skip {
+						continue
+					}
+					o.name = st.Name
+					o.omitEmpty = st.OmitEmpty
+				}
+				oneofs[od.Name()] = o
+				desc.oneofs[o.name] = o
+			}
+			f.oneof = o
+			o.fields = append(o.fields, f)
+			o.byName[f.name] = f
+		} else {
+			desc.byName[f.name] = f
+		}
+		desc.fields = append(desc.fields, f)
 	}
-	tag, ok := sf.Tag.Lookup("protobuf")
-	if !ok {
-		return bsoncodec.DefaultStructTagParser(sf)
+	// Also accept the alternative names of the fields when decoding, like protojson does.
+	for _, f := range desc.fields {
+		byName := desc.byName
+		if f.oneof != nil {
+			byName = f.oneof.byName
+		}
+		for _, name := range []string{f.fd.JSONName(), string(f.fd.Name())} {
+			if _, ok := byName[name]; !ok {
+				byName[name] = f
+			}
+		}
 	}
-	return parseTags(tag, true)
+	actual, _ := c.cache.LoadOrStore(key, desc)
+	return actual.(*messageDescription), nil
 }
 
-func parseTags(tag string, useProtoNames bool) (bsoncodec.StructTags, error) {
+// typeEncoder returns the Encoder registered for the message pointer type t, or nil if t is
+// encoded by a MessageCodec.
+func (c *MessageCodec) typeEncoder(ec bsoncodec.EncodeContext, t reflect.Type) bsoncodec.ValueEncoder {
+	if ec.Registry == nil {
+		return nil
+	}
+	encoder, err := ec.LookupEncoder(t)
+	if err != nil {
+		return nil
+	}
+	if _, ok := encoder.(*MessageCodec); ok {
+		return nil
+	}
+	return encoder
+}
+
+// typeDecoder returns the Decoder registered for the message pointer type t, or nil if t is
+// decoded by a MessageCodec.
+func (c *MessageCodec) typeDecoder(dc bsoncodec.DecodeContext, t reflect.Type) bsoncodec.ValueDecoder {
+	if dc.Registry == nil {
+		return nil
+	}
+	decoder, err := dc.LookupDecoder(t)
+	if err != nil {
+		return nil
+	}
+	if _, ok := decoder.(*MessageCodec); ok {
+		return nil
+	}
+	return decoder
+}
+
+// decodeMessageInto decodes into m with decoder, a Decoder of the pointer type of m which may
+// allocate a new message.
+func decodeMessageInto(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, decoder bsoncodec.ValueDecoder, m proto.Message) error {
+	// Top-level readers report no type, which Codecs switching on it do not expect.
+	if vr.Type() == 0 {
+		b, err := bsonutil.CopyDocumentToBytes(vr)
+		if err != nil {
+			return err
+		}
+		if vr, err = bsonutil.NewValueReader(bsontype.EmbeddedDocument, b); err != nil {
+			return err
+		}
+	}
+	ptr := reflect.New(reflect.TypeOf(m)).Elem()
+	ptr.Set(reflect.ValueOf(m))
+	if err := decoder.DecodeValue(dc, vr, ptr); err != nil {
+		return err
+	}
+	if ptr.IsNil() {
+		proto.Reset(m)
+	} else if decoded := ptr.Interface().(proto.Message); decoded != m {
+		proto.Reset(m)
+		proto.Merge(m, decoded)
+	}
+	return nil
+}
+
+// omitNil reports whether the Codec of the unset field f omits it.
+func (c *MessageCodec) omitNil(ec bsoncodec.EncodeContext, f *fieldDescription) bool {
+	if f.msgType == nil {
+		return false
+	}
+	var encoder bsoncodec.ValueEncoder = f.codec
+	if f.codec == nil {
+		if ec.Registry == nil {
+			return false
+		}
+		var err error
+		if encoder, err = ec.LookupEncoder(f.msgType); err != nil {
+			return false
+		}
+	}
+	o, ok := encoder.(NilOmitter)
+	return ok && o.OmitNil()
+}
+
+// isNilField reports whether the empty repeated or map field f of m is a nil slice or map, which
+// is encoded as null. The empty fields of dynamic messages are always considered nil.
+func isNilField(m protoreflect.Message, f *fieldDescription) bool {
+	if !f.fd.IsList() && !f.fd.IsMap() {
+		return false
+	}
+	if f.goIndex == nil {
+		return true
+	}
+	return reflect.ValueOf(m.Interface()).Elem().FieldByIndex(f.goIndex).IsNil()
+}
+
+// setNonNilField sets the nil repeated or map field f of m to an empty slice or map, so that an
+// empty array or document is encoded back as such.
+func setNonNilField(m protoreflect.Message, f *fieldDescription) {
+	if f.goIndex == nil || !isNilField(m, f) {
+		return
+	}
+	fv := reflect.ValueOf(m.Interface()).Elem().FieldByIndex(f.goIndex)
+	if f.fd.IsMap() {
+		fv.Set(reflect.MakeMap(fv.Type()))
+	} else {
+		fv.Set(reflect.MakeSlice(fv.Type(), 0, 0))
+	}
+}
+
+// fieldOptions returns the (protobson.field) options of fd.
+func fieldOptions(fd protoreflect.FieldDescriptor) *protobsonpb.FieldOptions {
+	if opts, ok := proto.GetExtension(fd.Options(), protobsonpb.E_Field).(*protobsonpb.FieldOptions); ok && opts != nil {
+		return opts
+	}
+	return &protobsonpb.FieldOptions{}
+}
+
+func (c *MessageCodec) binarySubtype(f *fieldDescription) byte {
+	if f.subtype != nil {
+		return *f.subtype
+	}
+	return c.BinarySubtype
+}
+
+func isOneofField(fd protoreflect.FieldDescriptor) bool {
+	od := fd.ContainingOneof()
+	return od != nil && !od.IsSynthetic()
+}
+
+// isNullValueField reports whether fd is a singular google.protobuf.NullValue field.
+func isNullValueField(fd protoreflect.FieldDescriptor) bool {
+	return !fd.IsList() && !fd.IsMap() && fd.Enum() != nil && fd.Enum().FullName() == "google.protobuf.NullValue"
+}
+
+// oneofKey returns the default key of the oneof of name name, which is the lowercased name of
+// its interface field in generated messages.
+func oneofKey(name protoreflect.Name) string {
+	s := string(name)
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '_' && i == 0:
+			b.WriteByte('x')
+		case c == '_' && i+1 < len(s) && 'a' <= s[i+1] && s[i+1] <= 'z':
+			// Underscores followed by a lowercase letter are dropped from Go names.
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return strings.ToLower(b.String())
+}
+
+func lessMapKey(a, b protoreflect.MapKey) bool {
+	switch a.Interface().(type) {
+	case bool:
+		return !a.Bool() && b.Bool()
+	case int32, int64:
+		return a.Int() < b.Int()
+	case uint32, uint64:
+		return a.Uint() < b.Uint()
+	default:
+		return a.String() < b.String()
+	}
+}
+
+func parseMapKey(fd protoreflect.FieldDescriptor, s string) (protoreflect.MapKey, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s).MapKey(), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return protoreflect.MapKey{}, err
+		}
+		return protoreflect.ValueOfBool(b).MapKey(), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		i, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return protoreflect.MapKey{}, err
+		}
+		return protoreflect.ValueOfInt32(int32(i)).MapKey(), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return protoreflect.MapKey{}, err
+		}
+		return protoreflect.ValueOfInt64(i).MapKey(), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		u, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return protoreflect.MapKey{}, err
+		}
+		return protoreflect.ValueOfUint32(uint32(u)).MapKey(), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		u, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return protoreflect.MapKey{}, err
+		}
+		return protoreflect.ValueOfUint64(u).MapKey(), nil
+	default:
+		return protoreflect.MapKey{}, fmt.Errorf("invalid map key kind %v", fd.Kind())
+	}
+}
+
+func structTagProps(tag string) map[string]string {
 	rawProps := strings.Split(tag, ",")
 	props := make(map[string]string, len(rawProps))
 	for _, rawProp := range rawProps {
 		k, v, _ := strings.Cut(rawProp, "=")
 		props[k] = v
 	}
-	var st bsoncodec.StructTags
-	jsonName, hasJSONName := props["json"]
-	if !useProtoNames && hasJSONName {
-		st.Name = jsonName
-	} else {
-		st.Name = props["name"]
-	}
-	return st, nil
+	return props
 }
@@ -1,10 +1,27 @@
 package protobsoncodec
 
 import (
+	"fmt"
+	"math"
 	"reflect"
 	"testing"
+	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonoptions"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.vallahaye.net/protobson/internal/testpb"
+	knowncodec "go.vallahaye.net/protobson/protobsoncodec/known"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 	"gotest.tools/v3/assert"
 )
 
@@ -221,3 +238,466 @@ func TestStructTagParsers(t *testing.T) {
 		})
 	}
 }
+
+func newTestRegistry(opts ...*protobsonoptions.MessageCodecOptions) *bsoncodec.Registry {
+	c := NewMessageCodec(opts...)
+	return bson.NewRegistryBuilder().
+		RegisterCodec(knowncodec.TypeTimestamp, knowncodec.NewTimestampCodec()).
+		RegisterCodec(knowncodec.TypeInt64Value, knowncodec.NewInt64ValueCodec()).
+		RegisterCodec(knowncodec.TypeBytesValue, knowncodec.NewBytesValueCodec()).
+		RegisterCodec(knowncodec.TypeUInt64Value, knowncodec.NewUInt64ValueCodec()).
+		RegisterHookEncoder(TypeMessage, c).
+		RegisterHookDecoder(TypeMessage, c).
+		Build()
+}
+
+func TestMessageCodec(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		for _, params := range []struct {
+			name string
+			msg  proto.Message
+		}{
+			{
+				"Empty",
+				&testpb.KitchenSink{},
+			},
+			{
+				"AllFields",
+				&testpb.KitchenSink{
+					Int32Field:           -1,
+					Int64Field:           -2,
+					Uint32Field:          3,
+					Uint64Field:          4,
+					Sint32Field:          -5,
+					Sint64Field:          -6,
+					Fixed32Field:         7,
+					Fixed64Field:         8,
+					Sfixed32Field:        -9,
+					Sfixed64Field:        -10,
+					FloatField:           1.5,
+					DoubleField:          2.5,
+					BoolField:            true,
+					StringField:          "foo",
+					BytesField:           []byte("bar"),
+					EnumField:            testpb.Status_STATUS_ACTIVE,
+					MessageField:         &testpb.Record{Name: "baz", CreatedAt: timestamppb.New(time.Date(2022, 5, 30, 11, 43, 26, 0, time.UTC))},
+					OptionalField:        proto.String(""),
+					RepeatedField:        []uint64{1, 2, 3},
+					RepeatedMessageField: []*testpb.Record{{Name: "qux"}, {}},
+					MapField:             map[string]int32{"a": 1, "b": 2},
+					IntKeyMapField:       map[int64]*testpb.Record{-1: {Name: "quux"}},
+					Choice:               &testpb.KitchenSink_ChoiceMessage{ChoiceMessage: &testpb.Record{Name: "corge"}},
+					Uint64ValueField:     wrapperspb.UInt64(42),
+				},
+			},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				reg := newTestRegistry()
+				b, err := bson.MarshalWithRegistry(reg, params.msg)
+				assert.NilError(t, err)
+				got := params.msg.ProtoReflect().Type().New().Interface()
+				err = bson.UnmarshalWithRegistry(reg, b, got)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.msg, got, protocmp.Transform())
+			})
+		}
+	})
+	t.Run("Document", func(t *testing.T) {
+		reg := newTestRegistry(protobsonoptions.MessageCodec().SetUseProtoNames(true))
+		b, err := bson.MarshalWithRegistry(reg, &testpb.KitchenSink{
+			EnumField:      testpb.Status_STATUS_ARCHIVED,
+			MapField:       map[string]int32{"b": 2, "a": 1},
+			IntKeyMapField: map[int64]*testpb.Record{10: {}, 9: {}},
+			Choice:         &testpb.KitchenSink_ChoiceString{ChoiceString: "foo"},
+		})
+		assert.NilError(t, err)
+		raw := bson.Raw(b)
+		assert.Equal(t, int32(2), raw.Lookup("enum_field").Int32())
+		assert.Equal(t, bsontype.Null, raw.Lookup("optional_field").Type)
+		assert.Equal(t, bsontype.Null, raw.Lookup("repeated_field").Type)
+		assert.Equal(t, `{"a": {"$numberInt":"1"},"b": {"$numberInt":"2"}}`, raw.Lookup("map_field").String())
+		elems, err := raw.Lookup("int_key_map_field").Document().Elements()
+		assert.NilError(t, err)
+		assert.Equal(t, 2, len(elems))
+		assert.Equal(t, "9", elems[0].Key())
+		assert.Equal(t, "10", elems[1].Key())
+		assert.Equal(t, "foo", raw.Lookup("choice", "choice_string").StringValue())
+		_, err = raw.LookupErr("choice", "choice_message")
+		assert.ErrorIs(t, err, bsoncore.ErrElementNotFound)
+	})
+	t.Run("Layout", func(t *testing.T) {
+		reg := newTestRegistry()
+		for _, params := range []struct {
+			name string
+			msg  *testpb.KitchenSink
+			want string
+		}{
+			{
+				"Nil",
+				&testpb.KitchenSink{},
+				`{"repeatedField": null,"mapField": null,"choice": null}`,
+			},
+			{
+				"Empty",
+				&testpb.KitchenSink{RepeatedField: []uint64{}, MapField: map[string]int32{}},
+				`{"repeatedField": [],"mapField": {},"choice": null}`,
+			},
+			{
+				"Oneof",
+				&testpb.KitchenSink{Choice: &testpb.KitchenSink_ChoiceString{ChoiceString: "foo"}},
+				`{"repeatedField": null,"mapField": null,"choice": {"choiceString": "foo"}}`,
+			},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				b, err := bson.MarshalWithRegistry(reg, params.msg)
+				assert.NilError(t, err)
+				raw := bson.Raw(b)
+				got := fmt.Sprintf(`{"repeatedField": %s,"mapField": %s,"choice": %s}`, raw.Lookup("repeatedField"), raw.Lookup("mapField"), raw.Lookup("choice"))
+				assert.Equal(t, params.want, got)
+				decoded := &testpb.KitchenSink{}
+				assert.NilError(t, bson.UnmarshalWithRegistry(reg, b, decoded))
+				assert.Equal(t, params.msg.RepeatedField == nil, decoded.RepeatedField == nil)
+				assert.Equal(t, params.msg.MapField == nil, decoded.MapField == nil)
+				assert.DeepEqual(t, params.msg, decoded, protocmp.Transform())
+			})
+		}
+	})
+	t.Run("UInt64Format", func(t *testing.T) {
+		msg := &testpb.KitchenSink{
+			Uint64Field:      math.MaxUint64,
+			Fixed64Field:     1,
+			RepeatedField:    []uint64{math.MaxInt64 + 1},
+			Uint64ValueField: wrapperspb.UInt64(1),
+		}
+		for _, params := range []struct {
+			name    string
+			format  protobsonoptions.UInt64Format
+			want    bson.RawValue
+			wantErr string
+		}{
+			{
+				"Int64",
+				protobsonoptions.UInt64FormatInt64,
+				bson.RawValue{},
+				"18446744073709551615 overflows int64",
+			},
+			{
+				"Decimal128",
+				protobsonoptions.UInt64FormatDecimal128,
+				bson.RawValue{Type: bsontype.Decimal128, Value: bsoncore.AppendDecimal128(nil, primitive.NewDecimal128(0x3040000000000000, math.MaxUint64))},
+				"",
+			},
+			{
+				"String",
+				protobsonoptions.UInt64FormatString,
+				bson.RawValue{Type: bsontype.String, Value: bsoncore.AppendString(nil, "18446744073709551615")},
+				"",
+			},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				reg := newTestRegistry(protobsonoptions.MessageCodec().SetUInt64Format(params.format))
+				b, err := bson.MarshalWithRegistry(reg, msg)
+				if params.wantErr != "" {
+					assert.ErrorContains(t, err, params.wantErr)
+					return
+				}
+				assert.NilError(t, err)
+				assert.Assert(t, params.want.Equal(bson.Raw(b).Lookup("uint64Field")))
+				got := &testpb.KitchenSink{}
+				err = bson.UnmarshalWithRegistry(reg, b, got)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, msg, got, protocmp.Transform())
+			})
+		}
+	})
+	t.Run("BinarySubtype", func(t *testing.T) {
+		msg := &testpb.Blobs{
+			Data:      []byte{0x01},
+			Uuid:      []byte{0x02},
+			Md5:       wrapperspb.Bytes([]byte{0x03}),
+			Encrypted: [][]byte{{0x04}},
+		}
+		reg := newTestRegistry(protobsonoptions.MessageCodec().SetBinarySubtype(bsontype.BinaryUserDefined))
+		b, err := bson.MarshalWithRegistry(reg, msg)
+		assert.NilError(t, err)
+		want, err := bson.Marshal(bson.D{
+			{Key: "data", Value: primitive.Binary{Subtype: bsontype.BinaryUserDefined, Data: []byte{0x01}}},
+			{Key: "uuid", Value: primitive.Binary{Subtype: bsontype.BinaryUUID, Data: []byte{0x02}}},
+			{Key: "md5", Value: primitive.Binary{Subtype: bsontype.BinaryMD5, Data: []byte{0x03}}},
+			{Key: "encrypted", Value: bson.A{primitive.Binary{Subtype: bsontype.BinaryEncrypted, Data: []byte{0x04}}}},
+		})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, bson.Raw(want), bson.Raw(b))
+		got := &testpb.Blobs{}
+		err = bson.UnmarshalWithRegistry(reg, b, got)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, msg, got, protocmp.Transform())
+		b, err = bson.Marshal(bson.D{
+			{Key: "data", Value: "AQ=="},
+			{Key: "uuid", Value: "Ag"},
+			{Key: "encrypted", Value: bson.A{primitive.Binary{Subtype: bsontype.BinaryGeneric, Data: []byte{0x04}}}},
+		})
+		assert.NilError(t, err)
+		got = &testpb.Blobs{}
+		err = bson.UnmarshalWithRegistry(reg, b, got)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, &testpb.Blobs{Data: []byte("AQ=="), Uuid: []byte("Ag"), Encrypted: [][]byte{{0x04}}}, got, protocmp.Transform())
+		got = &testpb.Blobs{}
+		err = bson.UnmarshalWithRegistry(newTestRegistry(protobsonoptions.MessageCodec().SetDecodeBase64Strings(true)), b, got)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, &testpb.Blobs{Data: []byte{0x01}, Uuid: []byte{0x02}, Encrypted: [][]byte{{0x04}}}, got, protocmp.Transform())
+	})
+	t.Run("StringFormat", func(t *testing.T) {
+		msg := &testpb.Identifiers{
+			Id:        "62a1e4f0c2b1a3d4e5f60718",
+			RequestId: "3f2504e0-4f89-11d3-9a0c-0305e82c3301",
+			ParentIds: []string{"62a1e4f0c2b1a3d4e5f60719"},
+			LegacyId:  "62a1e4f0c2b1a3d4e5f6071a",
+		}
+		reg := newTestRegistry(protobsonoptions.MessageCodec().SetStringFormat("protobson.test.Identifiers.legacy_id", protobsonoptions.StringFormatObjectID))
+		b, err := bson.MarshalWithRegistry(reg, msg)
+		assert.NilError(t, err)
+		oid := func(s string) primitive.ObjectID {
+			oid, err := primitive.ObjectIDFromHex(s)
+			assert.NilError(t, err)
+			return oid
+		}
+		want, err := bson.Marshal(bson.D{
+			{Key: "id", Value: oid("62a1e4f0c2b1a3d4e5f60718")},
+			{Key: "requestId", Value: primitive.Binary{Subtype: bsontype.BinaryUUID, Data: []byte{0x3f, 0x25, 0x04, 0xe0, 0x4f, 0x89, 0x11, 0xd3, 0x9a, 0x0c, 0x03, 0x05, 0xe8, 0x2c, 0x33, 0x01}}},
+			{Key: "parentIds", Value: bson.A{oid("62a1e4f0c2b1a3d4e5f60719")}},
+			{Key: "legacyId", Value: oid("62a1e4f0c2b1a3d4e5f6071a")},
+		})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, bson.Raw(want), bson.Raw(b))
+		got := &testpb.Identifiers{}
+		err = bson.UnmarshalWithRegistry(reg, b, got)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, msg, got, protocmp.Transform())
+
+		_, err = bson.MarshalWithRegistry(reg, &testpb.Identifiers{RequestId: "3f2504e0-4f89-11d3-9a0c-0305e82c3301"})
+		assert.ErrorContains(t, err, `protobson.test.Identifiers.id: invalid ObjectID ""`)
+		_, err = bson.MarshalWithRegistry(reg, &testpb.Identifiers{Id: "62a1e4f0c2b1a3d4e5f60718"})
+		assert.ErrorContains(t, err, `protobson.test.Identifiers.request_id: invalid UUID ""`)
+		b, err = bson.Marshal(bson.D{{Key: "id", Value: nil}, {Key: "requestId", Value: nil}})
+		assert.NilError(t, err)
+		got = &testpb.Identifiers{Id: "62a1e4f0c2b1a3d4e5f60718"}
+		err = bson.UnmarshalWithRegistry(reg, b, got)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, &testpb.Identifiers{}, got, protocmp.Transform())
+
+		b, err = bson.Marshal(bson.D{
+			{Key: "id", Value: "62A1E4F0C2B1A3D4E5F60718"},
+			{Key: "requestId", Value: "3F2504E04F8911D39A0C0305E82C3301"},
+		})
+		assert.NilError(t, err)
+		got = &testpb.Identifiers{}
+		err = bson.UnmarshalWithRegistry(reg, b, got)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, &testpb.Identifiers{Id: "62a1e4f0c2b1a3d4e5f60718", RequestId: "3f2504e0-4f89-11d3-9a0c-0305e82c3301"}, got, protocmp.Transform())
+
+		_, err = bson.MarshalWithRegistry(reg, &testpb.Identifiers{Id: "62a1e4f0c2b1a3d4e5f60718", RequestId: "not-a-uuid"})
+		assert.ErrorContains(t, err, "protobson.test.Identifiers.request_id: invalid UUID")
+		_, err = bson.MarshalWithRegistry(reg, &testpb.Identifiers{Id: "42", RequestId: "3f2504e0-4f89-11d3-9a0c-0305e82c3301"})
+		assert.ErrorContains(t, err, "protobson.test.Identifiers.id: invalid ObjectID")
+		_, err = bson.MarshalWithRegistry(newTestRegistry(protobsonoptions.MessageCodec().SetStringFormat("protobson.test.Blobs.data", protobsonoptions.StringFormatUUID)), &testpb.Blobs{})
+		assert.ErrorContains(t, err, "protobson.test.Blobs.data: string format set on a bytes field")
+	})
+	t.Run("Int64Format", func(t *testing.T) {
+		msg := &testpb.KitchenSink{
+			Int64Field:    math.MinInt64,
+			Sint64Field:   -1,
+			Sfixed64Field: 1,
+		}
+		for _, params := range []struct {
+			name   string
+			format protobsonoptions.Int64Format
+			want   bson.RawValue
+		}{
+			{
+				"Int64",
+				protobsonoptions.Int64FormatInt64,
+				bson.RawValue{Type: bsontype.Int64, Value: bsoncore.AppendInt64(nil, math.MinInt64)},
+			},
+			{
+				"Decimal128",
+				protobsonoptions.Int64FormatDecimal128,
+				bson.RawValue{Type: bsontype.Decimal128, Value: bsoncore.AppendDecimal128(nil, primitive.NewDecimal128(0xb040000000000000, 1<<63))},
+			},
+			{
+				"String",
+				protobsonoptions.Int64FormatString,
+				bson.RawValue{Type: bsontype.String, Value: bsoncore.AppendString(nil, "-9223372036854775808")},
+			},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				reg := newTestRegistry(protobsonoptions.MessageCodec().SetInt64Format(params.format))
+				b, err := bson.MarshalWithRegistry(reg, msg)
+				assert.NilError(t, err)
+				assert.Assert(t, params.want.Equal(bson.Raw(b).Lookup("int64Field")))
+				got := &testpb.KitchenSink{}
+				err = bson.UnmarshalWithRegistry(reg, b, got)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, msg, got, protocmp.Transform())
+			})
+		}
+		b, err := bson.Marshal(bson.D{{Key: "int64Field", Value: int32(-1)}, {Key: "sint64Field", Value: 2.0}, {Key: "sfixed64Field", Value: "3"}})
+		assert.NilError(t, err)
+		got := &testpb.KitchenSink{}
+		err = bson.UnmarshalWithRegistry(newTestRegistry(), b, got)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, &testpb.KitchenSink{Int64Field: -1, Sint64Field: 2, Sfixed64Field: 3}, got, protocmp.Transform())
+	})
+	t.Run("Floats", func(t *testing.T) {
+		reg := newTestRegistry(protobsonoptions.MessageCodec().
+			SetRoundFloat32(true).
+			SetNonFiniteFormat(protobsonoptions.NonFiniteFormatString))
+		msg := &testpb.KitchenSink{FloatField: 0.1, DoubleField: math.NaN()}
+		b, err := bson.MarshalWithRegistry(reg, msg)
+		assert.NilError(t, err)
+		raw := bson.Raw(b)
+		assert.Equal(t, 0.1, raw.Lookup("floatField").Double())
+		assert.Equal(t, "NaN", raw.Lookup("doubleField").StringValue())
+		got := &testpb.KitchenSink{}
+		err = bson.UnmarshalWithRegistry(reg, b, got)
+		assert.NilError(t, err)
+		assert.Equal(t, float32(0.1), got.FloatField)
+		assert.Assert(t, math.IsNaN(got.DoubleField))
+
+		_, err = bson.MarshalWithRegistry(newTestRegistry(protobsonoptions.MessageCodec().SetNonFiniteFormat(protobsonoptions.NonFiniteFormatReject)), msg)
+		assert.ErrorContains(t, err, "protobson.test.KitchenSink.double_field: NaN cannot be encoded")
+		b, err = bson.Marshal(bson.D{{Key: "floatField", Value: 1e39}})
+		assert.NilError(t, err)
+		err = bson.UnmarshalWithRegistry(reg, b, &testpb.KitchenSink{})
+		assert.ErrorContains(t, err, "protobson.test.KitchenSink.float_field: 1e+39 overflows float32")
+	})
+	t.Run("Nulls", func(t *testing.T) {
+		c := NewMessageCodec()
+		reg := bson.NewRegistryBuilder().
+			RegisterCodec(knowncodec.TypeInt64Value, knowncodec.NewInt64ValueCodec(protobsonoptions.Int64ValueCodec().SetNilFormat(protobsonoptions.NilFormatOmit).SetFormat(protobsonoptions.Int64FormatInt64))).
+			RegisterCodec(knowncodec.TypeBytesValue, knowncodec.NewBytesValueCodec(protobsonoptions.BytesValueCodec().SetNilFormat(protobsonoptions.NilFormatOmit).SetSubtype(bsontype.BinaryGeneric))).
+			RegisterHookEncoder(TypeMessage, c).
+			RegisterHookDecoder(TypeMessage, c).
+			Build()
+		b, err := bson.MarshalWithRegistry(reg, &testpb.Record{Name: "a"})
+		assert.NilError(t, err)
+		want, err := bson.Marshal(bson.D{{Key: "name", Value: "a"}, {Key: "count", Value: int64(0)}, {Key: "createdAt", Value: nil}, {Key: "parent", Value: nil}, {Key: "children", Value: nil}})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, bson.Raw(want), bson.Raw(b))
+
+		b, err = bson.Marshal(bson.D{
+			{Key: "name", Value: "a"},
+			{Key: "version", Value: nil},
+			{Key: "parent", Value: bson.D{{Key: "checksum", Value: nil}, {Key: "version", Value: int64(2)}}},
+		})
+		assert.NilError(t, err)
+		got := &testpb.Record{}
+		nulls, err := c.DecodeWithNulls(bsoncodec.DecodeContext{Registry: reg}, bsonrw.NewBSONDocumentReader(b), reflect.ValueOf(&got).Elem())
+		assert.NilError(t, err)
+		assert.DeepEqual(t, &testpb.Record{Name: "a", Parent: &testpb.Record{Version: wrapperspb.Int64(2)}}, got, protocmp.Transform())
+		assert.DeepEqual(t, []string{"version", "parent.checksum"}, nulls)
+
+		b, err = bson.Marshal(bson.D{{Key: "name", Value: "a"}})
+		assert.NilError(t, err)
+		nulls, err = c.DecodeWithNulls(bsoncodec.DecodeContext{Registry: reg}, bsonrw.NewBSONDocumentReader(b), reflect.ValueOf(&got).Elem())
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []string{}, nulls)
+	})
+	t.Run("NullValue", func(t *testing.T) {
+		c := NewMessageCodec()
+		reg := bson.NewRegistryBuilder().
+			RegisterCodec(knowncodec.TypeNullValue, knowncodec.NewNullValueCodec()).
+			RegisterHookEncoder(TypeMessage, c).
+			RegisterHookDecoder(TypeMessage, c).
+			Build()
+		for _, params := range []struct {
+			name string
+			msg  proto.Message
+		}{
+			{
+				"Value",
+				structpb.NewNullValue(),
+			},
+			{
+				"Struct",
+				&structpb.Struct{Fields: map[string]*structpb.Value{"a": structpb.NewNullValue(), "b": structpb.NewBoolValue(true)}},
+			},
+		} {
+			t.Run(params.name, func(t *testing.T) {
+				b, err := bson.MarshalWithRegistry(reg, params.msg)
+				assert.NilError(t, err)
+				got := params.msg.ProtoReflect().Type().New().Interface()
+				nulls, err := c.DecodeWithNulls(bsoncodec.DecodeContext{Registry: reg}, bsonrw.NewBSONDocumentReader(b), reflect.ValueOf(&got).Elem())
+				assert.NilError(t, err)
+				assert.DeepEqual(t, params.msg, got, protocmp.Transform())
+				assert.DeepEqual(t, []string{}, nulls)
+			})
+		}
+		b, err := bson.MarshalWithRegistry(reg, structpb.NewNullValue())
+		assert.NilError(t, err)
+		assert.Equal(t, bsontype.Null, bson.Raw(b).Lookup("kind", "nullValue").Type)
+	})
+	t.Run("Codecs", func(t *testing.T) {
+		reg := newTestRegistry(protobsonoptions.MessageCodec().
+			SetCodec("protobson.test.KitchenSink.int_key_map_field", NewMessageCodec(protobsonoptions.MessageCodec().SetUseProtoNames(true))).
+			SetCodec("protobson.test.Record.created_at", knowncodec.NewTimestampCodec(protobsonoptions.TimestampCodec().SetFormat(protobsonoptions.TimestampFormatString))).
+			SetCodec("google.protobuf.UInt64Value", knowncodec.NewUInt64ValueCodec(protobsonoptions.UInt64ValueCodec().SetFormat(protobsonoptions.UInt64FormatString))))
+		ts := timestamppb.New(time.Date(2022, 5, 30, 11, 43, 26, 0, time.UTC))
+		msg := &testpb.KitchenSink{
+			MessageField:     &testpb.Record{CreatedAt: ts},
+			IntKeyMapField:   map[int64]*testpb.Record{1: {CreatedAt: ts}},
+			Uint64ValueField: wrapperspb.UInt64(42),
+		}
+		b, err := bson.MarshalWithRegistry(reg, msg)
+		assert.NilError(t, err)
+		raw := bson.Raw(b)
+		assert.Equal(t, "2022-05-30T11:43:26Z", raw.Lookup("messageField", "createdAt").StringValue())
+		assert.Equal(t, bsontype.DateTime, raw.Lookup("intKeyMapField", "1", "created_at").Type)
+		assert.Equal(t, "00000000000000000042", raw.Lookup("uint64ValueField").StringValue())
+		got := &testpb.KitchenSink{}
+		err = bson.UnmarshalWithRegistry(reg, b, got)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, msg, got, protocmp.Transform())
+	})
+	t.Run("StructCodecOptions", func(t *testing.T) {
+		c := NewMessageCodec(&protobsonoptions.MessageCodecOptions{
+			StructCodecOptions: bsonoptions.StructCodec().SetEncodeOmitDefaultStruct(true).SetDecodeZeroStruct(true),
+		})
+		c.parser = bsoncodec.StructTagParserFunc(func(sf reflect.StructField) (bsoncodec.StructTags, error) {
+			st, err := JSONPBFallbackStructTagParser(sf)
+			st.OmitEmpty = true
+			return st, err
+		})
+		reg := bson.NewRegistryBuilder().
+			RegisterHookEncoder(TypeMessage, c).
+			RegisterHookDecoder(TypeMessage, c).
+			Build()
+		b, err := bson.MarshalWithRegistry(reg, &testpb.Record{Name: "foo", Parent: &testpb.Record{}})
+		assert.NilError(t, err)
+		want, err := bson.Marshal(bson.D{{Key: "name", Value: "foo"}})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, bson.Raw(want), bson.Raw(b))
+		got := &testpb.Record{Count: 42}
+		err = bson.UnmarshalWithRegistry(reg, b, got)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, &testpb.Record{Name: "foo"}, got, protocmp.Transform())
+
+		for _, opts := range []*bsonoptions.StructCodecOptions{
+			bsonoptions.StructCodec().SetAllowUnexportedFields(true),
+			bsonoptions.StructCodec().SetDecodeDeepZeroInline(true),
+			bsonoptions.StructCodec().SetOverwriteDuplicatedInlinedFields(false),
+		} {
+			reg := newTestRegistry(&protobsonoptions.MessageCodecOptions{StructCodecOptions: opts})
+			msg := &testpb.Record{Name: "foo", Parent: &testpb.Record{Name: "bar"}}
+			b, err := bson.MarshalWithRegistry(reg, msg)
+			assert.NilError(t, err)
+			want, err := bson.MarshalWithRegistry(newTestRegistry(), msg)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, bson.Raw(want), bson.Raw(b))
+			got := &testpb.Record{}
+			err = bson.UnmarshalWithRegistry(reg, b, got)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, msg, got, protocmp.Transform())
+		}
+	})
+}
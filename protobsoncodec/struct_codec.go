@@ -0,0 +1,143 @@
+package protobsoncodec
+
+import (
+	"reflect"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// This file holds the parts of the MessageCodec built on the StructCodec and StructTagParser of
+// the v1 driver, which the v2 driver does not export: protobsonv2 has its own version of it.
+
+// MessageCodec is the Codec used for proto.Message values.
+//
+// Fields are encoded in declaration order by looking up the Codec registered for their Go
+// type, so that messages nested in a proto.Message reuse the Codecs registered for them.
+// Unset oneof fields, and unset message fields whose Codec implements NilOmitter, are omitted
+// while other unset fields with presence, as well as empty repeated and map fields, are
+// encoded as null. DecodeWithNulls also returns the keys explicitly set to null in decoded
+// documents. Bytes fields are encoded as binary of BinarySubtype, unless their
+// (protobson.field).binary_subtype option says otherwise, which also applies to
+// google.protobuf.BytesValue fields, and strings decoded into them are taken as is unless
+// DecodeBase64Strings is true. String fields can be encoded as ObjectIDs
+// or UUIDs with StringFormats or their (protobson.field).string_format option. When
+// EncodeOmitDefaultStruct is true, empty message fields tagged omitempty are omitted as well.
+//
+// The embedded StructCodec holds the struct codec options: only its DecodeZeroStruct and
+// EncodeOmitDefaultStruct fields apply to messages, which are not encoded by it.
+//
+// The values of a field whose full name, or whose message type full name, is a key of Codecs
+// are encoded and decoded with that Codec instead, bypassing the registry and the other
+// options. For instance, a single google.protobuf.Timestamp field can be stored as a string
+// while the others are stored as dates.
+//
+// Dynamic messages (*dynamicpb.Message) are supported as well and encoded to the same
+// documents as their generated counterparts: the values of message types with a generated Go
+// type registered in protoregistry.GlobalTypes, such as google.protobuf.Timestamp, are
+// converted to it so that the Codecs registered for them apply. Dynamic messages can only be
+// decoded into messages created with dynamicpb.NewMessage, as their descriptor must be known.
+type MessageCodec struct {
+	*bsoncodec.StructCodec
+
+	Int64Format         protobsonoptions.Int64Format
+	UInt64Format        protobsonoptions.UInt64Format
+	NonFiniteFormat     protobsonoptions.NonFiniteFormat
+	RoundFloat32        bool
+	BinarySubtype       byte
+	DecodeBase64Strings bool
+	StringFormats       map[protoreflect.FullName]protobsonoptions.StringFormat
+	Codecs              map[protoreflect.FullName]bsoncodec.ValueCodec
+
+	useProtoNames bool
+	parser        bsoncodec.StructTagParser
+	cache         sync.Map // map[messageKey]*messageDescription
+}
+
+// NewMessageCodec returns a MessageCodec with options opts.
+func NewMessageCodec(opts ...*protobsonoptions.MessageCodecOptions) *MessageCodec {
+	mergedOpts := protobsonoptions.MergeMessageCodecOptions(opts...)
+	codec := &MessageCodec{
+		Int64Format:         *mergedOpts.Int64Format,
+		UInt64Format:        *mergedOpts.UInt64Format,
+		NonFiniteFormat:     *mergedOpts.NonFiniteFormat,
+		RoundFloat32:        *mergedOpts.RoundFloat32,
+		BinarySubtype:       *mergedOpts.BinarySubtype,
+		DecodeBase64Strings: *mergedOpts.DecodeBase64Strings,
+		StringFormats:       mergedOpts.StringFormats,
+		Codecs:              mergedOpts.Codecs,
+		useProtoNames:       *mergedOpts.UseProtoNames,
+		parser:              JSONPBFallbackStructTagParser,
+	}
+	if codec.useProtoNames {
+		codec.parser = ProtoNamesFallbackStructTagParser
+	}
+	codec.StructCodec, _ = bsoncodec.NewStructCodec(codec.parser, mergedOpts.StructCodecOptions)
+	return codec
+}
+
+func (c *MessageCodec) decodeZeroStruct() bool {
+	return c.StructCodec != nil && c.DecodeZeroStruct
+}
+
+func (c *MessageCodec) encodeOmitDefaultStruct() bool {
+	return c.StructCodec != nil && c.EncodeOmitDefaultStruct
+}
+
+func (c *MessageCodec) parseStructTags(sf reflect.StructField) (structTags, error) {
+	st, err := c.parser.ParseStructTags(sf)
+	if err != nil {
+		return structTags{}, err
+	}
+	return structTags{Name: st.Name, OmitEmpty: st.OmitEmpty, Skip: st.Skip}, nil
+}
+
+// JSONPBFallbackStructTagParser is the StructTagParser used by the MessageCodec by default.
+// It has the same behavior as bsoncodec.DefaultStructTagParser but will also fallback to
+// parsing the protobuf tag on a field where the bson tag isn't available. In this case, the
+// key will be taken from the json property, or from the name property if there is none.
+//
+// An example:
+//
+//	type T struct {
+//	  Name   string `protobuf:"bytes,1,opt,name=name,proto3"` // Key is "name"
+//	  FooBar string `protobuf:"bytes,2,opt,name=foo_bar,json=fooBar,proto3"` // Key is "fooBar"
+//	  BarFoo string `protobuf:"bytes,3,opt,name=bar_foo,json=barFoo,proto3" bson:"barfoo"` // Key is "barfoo"
+//	}
+var JSONPBFallbackStructTagParser bsoncodec.StructTagParserFunc = func(sf reflect.StructField) (bsoncodec.StructTags, error) {
+	if _, ok := sf.Tag.Lookup("bson"); ok {
+		return bsoncodec.DefaultStructTagParser(sf)
+	}
+	tag, ok := sf.Tag.Lookup("protobuf")
+	if !ok {
+		return bsoncodec.DefaultStructTagParser(sf)
+	}
+	return parseTags(tag, false)
+}
+
+// ProtoNamesFallbackStructTagParser has the same behavior as JSONPBFallbackStructTagParser
+// except it forces the use of the name property as the key when parsing protobuf tags.
+var ProtoNamesFallbackStructTagParser bsoncodec.StructTagParserFunc = func(sf reflect.StructField) (bsoncodec.StructTags, error) {
+	if _, ok := sf.Tag.Lookup("bson"); ok {
+		return bsoncodec.DefaultStructTagParser(sf)
+	}
+	tag, ok := sf.Tag.Lookup("protobuf")
+	if !ok {
+		return bsoncodec.DefaultStructTagParser(sf)
+	}
+	return parseTags(tag, true)
+}
+
+func parseTags(tag string, useProtoNames bool) (bsoncodec.StructTags, error) {
+	props := structTagProps(tag)
+	var st bsoncodec.StructTags
+	jsonName, hasJSONName := props["json"]
+	if !useProtoNames && hasJSONName {
+		st.Name = jsonName
+	} else {
+		st.Name = props["name"]
+	}
+	return st, nil
+}
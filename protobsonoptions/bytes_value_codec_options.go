@@ -0,0 +1,66 @@
+package protobsonoptions
+
+import "go.mongodb.org/mongo-driver/bson/bsontype"
+
+var (
+	defaultBinarySubtype       = bsontype.BinaryGeneric
+	defaultDecodeBase64Strings = false
+)
+
+// BytesValueCodecOptions represents all possible options for *wrapperspb.BytesValue encoding and decoding.
+type BytesValueCodecOptions struct {
+	Subtype             *byte // Specifies the binary subtype of encoded values. Values of any subtype are decoded. Defaults to bsontype.BinaryGeneric.
+	DecodeBase64Strings *bool // Specifies if decoded strings should be base64 decoded rather than taken as is. Defaults to false.
+	*WrapperCodecOptions
+}
+
+// BytesValueCodec creates a new *BytesValueCodecOptions.
+func BytesValueCodec() *BytesValueCodecOptions {
+	return &BytesValueCodecOptions{
+		WrapperCodecOptions: WrapperCodec(),
+	}
+}
+
+// SetSubtype specifies the binary subtype of encoded values. Values of any subtype are decoded. Defaults to bsontype.BinaryGeneric.
+func (t *BytesValueCodecOptions) SetSubtype(b byte) *BytesValueCodecOptions {
+	t.Subtype = &b
+	return t
+}
+
+// SetDecodeBase64Strings specifies if decoded strings should be base64 decoded rather than taken as is. Defaults to false.
+func (t *BytesValueCodecOptions) SetDecodeBase64Strings(b bool) *BytesValueCodecOptions {
+	t.DecodeBase64Strings = &b
+	return t
+}
+
+// SetNilFormat specifies how unset wrapper fields are encoded. Defaults to NilFormatNull.
+func (t *BytesValueCodecOptions) SetNilFormat(f NilFormat) *BytesValueCodecOptions {
+	if t.WrapperCodecOptions == nil {
+		t.WrapperCodecOptions = WrapperCodec()
+	}
+	t.WrapperCodecOptions.SetNilFormat(f)
+	return t
+}
+
+// MergeBytesValueCodecOptions combines the given *BytesValueCodecOptions into a single *BytesValueCodecOptions in a last one wins fashion.
+func MergeBytesValueCodecOptions(opts ...*BytesValueCodecOptions) *BytesValueCodecOptions {
+	t := &BytesValueCodecOptions{
+		Subtype:             &defaultBinarySubtype,
+		DecodeBase64Strings: &defaultDecodeBase64Strings,
+	}
+	wrapperOpts := make([]*WrapperCodecOptions, 0, len(opts))
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		wrapperOpts = append(wrapperOpts, opt.WrapperCodecOptions)
+		if opt.Subtype != nil {
+			t.Subtype = opt.Subtype
+		}
+		if opt.DecodeBase64Strings != nil {
+			t.DecodeBase64Strings = opt.DecodeBase64Strings
+		}
+	}
+	t.WrapperCodecOptions = MergeWrapperCodecOptions(wrapperOpts...)
+	return t
+}
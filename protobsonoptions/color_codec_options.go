@@ -0,0 +1,48 @@
+package protobsonoptions
+
+// ColorFormat specifies the BSON representation of *color.Color values.
+type ColorFormat uint8
+
+// These constants specify the possible BSON representations of *color.Color values.
+const (
+	// ColorFormatHex encodes colors as "#RRGGBB" strings, or "#RRGGBBAA" strings when an alpha
+	// component is set. Components are quantized to 8 bits.
+	ColorFormatHex ColorFormat = iota
+	// ColorFormatDocument encodes colors as {red: <double>, green: <double>, blue: <double>,
+	// alpha: <double>} documents, the alpha element being omitted when unset.
+	ColorFormatDocument
+)
+
+var defaultColorFormat = ColorFormatHex
+
+// ColorCodecOptions represents all possible options for *color.Color encoding and decoding.
+type ColorCodecOptions struct {
+	Format *ColorFormat // Specifies the BSON representation of colors. Defaults to ColorFormatHex.
+}
+
+// ColorCodec creates a new *ColorCodecOptions.
+func ColorCodec() *ColorCodecOptions {
+	return &ColorCodecOptions{}
+}
+
+// SetFormat specifies the BSON representation of colors. Defaults to ColorFormatHex.
+func (t *ColorCodecOptions) SetFormat(f ColorFormat) *ColorCodecOptions {
+	t.Format = &f
+	return t
+}
+
+// MergeColorCodecOptions combines the given *ColorCodecOptions into a single *ColorCodecOptions in a last one wins fashion.
+func MergeColorCodecOptions(opts ...*ColorCodecOptions) *ColorCodecOptions {
+	t := &ColorCodecOptions{
+		Format: &defaultColorFormat,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.Format != nil {
+			t.Format = opt.Format
+		}
+	}
+	return t
+}
@@ -0,0 +1,54 @@
+package protobsonoptions
+
+// DateFormat specifies the BSON representation of *date.Date values.
+type DateFormat uint8
+
+// These constants specify the possible BSON representations of *date.Date values.
+//
+// Partial dates (year only, year and month, or month and day) cannot be represented as BSON
+// dates. With DateFormatDateTime, they are encoded as {year: <int32>, month: <int32>, day: <int32>}
+// documents, with zero values for the missing components.
+const (
+	// DateFormatDateTime encodes dates as BSON dates at midnight UTC.
+	DateFormatDateTime DateFormat = iota
+	// DateFormatString encodes dates as "YYYY-MM-DD" strings. Partial dates are encoded as "YYYY",
+	// "YYYY-MM" or "--MM-DD" strings.
+	DateFormatString
+	// DateFormatInt encodes dates as YYYYMMDD 32-bit integers, with zero values for the missing
+	// components of partial dates.
+	DateFormatInt
+)
+
+var defaultDateFormat = DateFormatDateTime
+
+// DateCodecOptions represents all possible options for *date.Date encoding and decoding.
+type DateCodecOptions struct {
+	Format *DateFormat // Specifies the BSON representation of dates. Defaults to DateFormatDateTime.
+}
+
+// DateCodec creates a new *DateCodecOptions.
+func DateCodec() *DateCodecOptions {
+	return &DateCodecOptions{}
+}
+
+// SetFormat specifies the BSON representation of dates. Defaults to DateFormatDateTime.
+func (t *DateCodecOptions) SetFormat(f DateFormat) *DateCodecOptions {
+	t.Format = &f
+	return t
+}
+
+// MergeDateCodecOptions combines the given *DateCodecOptions into a single *DateCodecOptions in a last one wins fashion.
+func MergeDateCodecOptions(opts ...*DateCodecOptions) *DateCodecOptions {
+	t := &DateCodecOptions{
+		Format: &defaultDateFormat,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.Format != nil {
+			t.Format = opt.Format
+		}
+	}
+	return t
+}
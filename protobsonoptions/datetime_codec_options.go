@@ -0,0 +1,76 @@
+package protobsonoptions
+
+import "time"
+
+// DateTimeFormat specifies the BSON representation of *datetime.DateTime values.
+type DateTimeFormat uint8
+
+// These constants specify the possible BSON representations of *datetime.DateTime values.
+//
+// Date-times without a time offset represent a civil time. They are interpreted in the default
+// location of the codec whenever an instant is needed.
+const (
+	// DateTimeFormatDateTime encodes date-times as BSON dates, truncating them to the millisecond.
+	// The time offset is lost: decoded date-times always carry a UTC offset.
+	DateTimeFormatDateTime DateTimeFormat = iota
+	// DateTimeFormatDocument encodes date-times as {instant: <date>, nanos: <int32>, offset: <int32>,
+	// tz: <string>, tzVersion: <string>} documents, where nanos holds the sub-millisecond part of the
+	// date-time and offset the UTC offset in seconds at that instant. tz and tzVersion are only present
+	// for date-times with a time zone. Date-times without a time offset are encoded as
+	// {instant: <date>, nanos: <int32>, civil: <string>} documents instead, civil holding the
+	// "YYYY-MM-DDTHH:MM:SS.fffffffff" civil time.
+	DateTimeFormatDocument
+	// DateTimeFormatCivil encodes date-times as {civil: <string>, offset: <int32>, tz: <string>,
+	// tzVersion: <string>} documents, where civil holds the "YYYY-MM-DDTHH:MM:SS.fffffffff" civil time.
+	// offset is only present for date-times with a UTC offset, tz and tzVersion only for date-times with
+	// a time zone.
+	DateTimeFormatCivil
+)
+
+var (
+	defaultDateTimeFormat          = DateTimeFormatDateTime
+	defaultDateTimeDefaultLocation = time.UTC
+)
+
+// DateTimeCodecOptions represents all possible options for *datetime.DateTime encoding and decoding.
+type DateTimeCodecOptions struct {
+	Format          *DateTimeFormat // Specifies the BSON representation of date-times. Defaults to DateTimeFormatDateTime.
+	DefaultLocation *time.Location  // Specifies the location of date-times without a time offset. Defaults to time.UTC.
+}
+
+// DateTimeCodec creates a new *DateTimeCodecOptions.
+func DateTimeCodec() *DateTimeCodecOptions {
+	return &DateTimeCodecOptions{}
+}
+
+// SetFormat specifies the BSON representation of date-times. Defaults to DateTimeFormatDateTime.
+func (t *DateTimeCodecOptions) SetFormat(f DateTimeFormat) *DateTimeCodecOptions {
+	t.Format = &f
+	return t
+}
+
+// SetDefaultLocation specifies the location of date-times without a time offset. Defaults to time.UTC.
+func (t *DateTimeCodecOptions) SetDefaultLocation(loc *time.Location) *DateTimeCodecOptions {
+	t.DefaultLocation = loc
+	return t
+}
+
+// MergeDateTimeCodecOptions combines the given *DateTimeCodecOptions into a single *DateTimeCodecOptions in a last one wins fashion.
+func MergeDateTimeCodecOptions(opts ...*DateTimeCodecOptions) *DateTimeCodecOptions {
+	t := &DateTimeCodecOptions{
+		Format:          &defaultDateTimeFormat,
+		DefaultLocation: defaultDateTimeDefaultLocation,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.Format != nil {
+			t.Format = opt.Format
+		}
+		if opt.DefaultLocation != nil {
+			t.DefaultLocation = opt.DefaultLocation
+		}
+	}
+	return t
+}
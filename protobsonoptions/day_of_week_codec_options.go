@@ -0,0 +1,35 @@
+package protobsonoptions
+
+var defaultDayOfWeekFormat = EnumFormatNumber
+
+// DayOfWeekCodecOptions represents all possible options for dayofweek.DayOfWeek encoding and decoding.
+type DayOfWeekCodecOptions struct {
+	Format *EnumFormat // Specifies the BSON representation of day of week values. Defaults to EnumFormatNumber, matching ISO 8601 numbering.
+}
+
+// DayOfWeekCodec creates a new *DayOfWeekCodecOptions.
+func DayOfWeekCodec() *DayOfWeekCodecOptions {
+	return &DayOfWeekCodecOptions{}
+}
+
+// SetFormat specifies the BSON representation of day of week values. Defaults to EnumFormatNumber, matching ISO 8601 numbering.
+func (t *DayOfWeekCodecOptions) SetFormat(f EnumFormat) *DayOfWeekCodecOptions {
+	t.Format = &f
+	return t
+}
+
+// MergeDayOfWeekCodecOptions combines the given *DayOfWeekCodecOptions into a single *DayOfWeekCodecOptions in a last one wins fashion.
+func MergeDayOfWeekCodecOptions(opts ...*DayOfWeekCodecOptions) *DayOfWeekCodecOptions {
+	t := &DayOfWeekCodecOptions{
+		Format: &defaultDayOfWeekFormat,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.Format != nil {
+			t.Format = opt.Format
+		}
+	}
+	return t
+}
@@ -0,0 +1,48 @@
+package protobsonoptions
+
+// DoubleValueCodecOptions represents all possible options for *wrapperspb.DoubleValue encoding and decoding.
+type DoubleValueCodecOptions struct {
+	NonFiniteFormat *NonFiniteFormat // Specifies the BSON representation of NaN and infinite values. Defaults to NonFiniteFormatDouble.
+	*WrapperCodecOptions
+}
+
+// DoubleValueCodec creates a new *DoubleValueCodecOptions.
+func DoubleValueCodec() *DoubleValueCodecOptions {
+	return &DoubleValueCodecOptions{
+		WrapperCodecOptions: WrapperCodec(),
+	}
+}
+
+// SetNonFiniteFormat specifies the BSON representation of NaN and infinite values. Defaults to NonFiniteFormatDouble.
+func (t *DoubleValueCodecOptions) SetNonFiniteFormat(f NonFiniteFormat) *DoubleValueCodecOptions {
+	t.NonFiniteFormat = &f
+	return t
+}
+
+// SetNilFormat specifies how unset wrapper fields are encoded. Defaults to NilFormatNull.
+func (t *DoubleValueCodecOptions) SetNilFormat(f NilFormat) *DoubleValueCodecOptions {
+	if t.WrapperCodecOptions == nil {
+		t.WrapperCodecOptions = WrapperCodec()
+	}
+	t.WrapperCodecOptions.SetNilFormat(f)
+	return t
+}
+
+// MergeDoubleValueCodecOptions combines the given *DoubleValueCodecOptions into a single *DoubleValueCodecOptions in a last one wins fashion.
+func MergeDoubleValueCodecOptions(opts ...*DoubleValueCodecOptions) *DoubleValueCodecOptions {
+	t := &DoubleValueCodecOptions{
+		NonFiniteFormat: &defaultNonFiniteFormat,
+	}
+	wrapperOpts := make([]*WrapperCodecOptions, 0, len(opts))
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		wrapperOpts = append(wrapperOpts, opt.WrapperCodecOptions)
+		if opt.NonFiniteFormat != nil {
+			t.NonFiniteFormat = opt.NonFiniteFormat
+		}
+	}
+	t.WrapperCodecOptions = MergeWrapperCodecOptions(wrapperOpts...)
+	return t
+}
@@ -0,0 +1,56 @@
+package protobsonoptions
+
+// DurationFormat specifies the BSON representation of *durationpb.Duration values.
+type DurationFormat uint8
+
+// These constants specify the possible BSON representations of *durationpb.Duration values.
+const (
+	// DurationFormatNanos encodes durations as 64-bit integers counting nanoseconds. Durations longer
+	// than approximately 292 years cannot be represented.
+	DurationFormatNanos DurationFormat = iota
+	// DurationFormatMillis encodes durations as 64-bit integers counting milliseconds, truncating
+	// sub-millisecond precision.
+	DurationFormatMillis
+	// DurationFormatSeconds encodes durations as doubles counting seconds.
+	DurationFormatSeconds
+	// DurationFormatDocument encodes durations as {seconds: <int64>, nanos: <int32>} documents.
+	DurationFormatDocument
+	// DurationFormatString encodes durations as strings in the protojson format, e.g. "1.500s".
+	DurationFormatString
+	// DurationFormatDecimal128 encodes durations as Decimal128 values counting seconds.
+	DurationFormatDecimal128
+)
+
+var defaultDurationFormat = DurationFormatNanos
+
+// DurationCodecOptions represents all possible options for *durationpb.Duration encoding and decoding.
+type DurationCodecOptions struct {
+	Format *DurationFormat // Specifies the BSON representation of durations. Numbers are decoded in the unit of this format. Defaults to DurationFormatNanos.
+}
+
+// DurationCodec creates a new *DurationCodecOptions.
+func DurationCodec() *DurationCodecOptions {
+	return &DurationCodecOptions{}
+}
+
+// SetFormat specifies the BSON representation of durations. Numbers are decoded in the unit of this format. Defaults to DurationFormatNanos.
+func (t *DurationCodecOptions) SetFormat(f DurationFormat) *DurationCodecOptions {
+	t.Format = &f
+	return t
+}
+
+// MergeDurationCodecOptions combines the given *DurationCodecOptions into a single *DurationCodecOptions in a last one wins fashion.
+func MergeDurationCodecOptions(opts ...*DurationCodecOptions) *DurationCodecOptions {
+	t := &DurationCodecOptions{
+		Format: &defaultDurationFormat,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.Format != nil {
+			t.Format = opt.Format
+		}
+	}
+	return t
+}
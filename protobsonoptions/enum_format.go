@@ -0,0 +1,12 @@
+package protobsonoptions
+
+// EnumFormat specifies the BSON representation of enum values.
+type EnumFormat uint8
+
+// These constants specify the possible BSON representations of enum values.
+const (
+	// EnumFormatNumber encodes enum values as 32-bit integers holding their number.
+	EnumFormatNumber EnumFormat = iota
+	// EnumFormatName encodes enum values as strings holding their name, e.g. "MONDAY".
+	EnumFormatName
+)
@@ -0,0 +1,20 @@
+package protobsonoptions
+
+// NonFiniteFormat specifies the BSON representation of NaN and infinite floating-point values.
+type NonFiniteFormat uint8
+
+// These constants specify the possible BSON representations of NaN and infinite floating-point values.
+const (
+	// NonFiniteFormatDouble encodes NaN and infinite values as BSON doubles.
+	NonFiniteFormatDouble NonFiniteFormat = iota
+	// NonFiniteFormatString encodes NaN and infinite values as the "NaN", "Infinity" and
+	// "-Infinity" strings, like protojson does.
+	NonFiniteFormatString
+	// NonFiniteFormatReject returns an error when encoding NaN and infinite values.
+	NonFiniteFormatReject
+)
+
+var (
+	defaultNonFiniteFormat = NonFiniteFormatDouble
+	defaultRoundFloat32    = false
+)
@@ -0,0 +1,59 @@
+package protobsonoptions
+
+// FloatValueCodecOptions represents all possible options for *wrapperspb.FloatValue encoding and decoding.
+type FloatValueCodecOptions struct {
+	NonFiniteFormat *NonFiniteFormat // Specifies the BSON representation of NaN and infinite values. Defaults to NonFiniteFormatDouble.
+	RoundFloat32    *bool            // Specifies if values should be rounded to their shortest decimal representation when widened to doubles, e.g. 0.1 rather than 0.10000000149011612. Defaults to false.
+	*WrapperCodecOptions
+}
+
+// FloatValueCodec creates a new *FloatValueCodecOptions.
+func FloatValueCodec() *FloatValueCodecOptions {
+	return &FloatValueCodecOptions{
+		WrapperCodecOptions: WrapperCodec(),
+	}
+}
+
+// SetNonFiniteFormat specifies the BSON representation of NaN and infinite values. Defaults to NonFiniteFormatDouble.
+func (t *FloatValueCodecOptions) SetNonFiniteFormat(f NonFiniteFormat) *FloatValueCodecOptions {
+	t.NonFiniteFormat = &f
+	return t
+}
+
+// SetRoundFloat32 specifies if values should be rounded to their shortest decimal representation when widened to doubles, e.g. 0.1 rather than 0.10000000149011612. Defaults to false.
+func (t *FloatValueCodecOptions) SetRoundFloat32(b bool) *FloatValueCodecOptions {
+	t.RoundFloat32 = &b
+	return t
+}
+
+// SetNilFormat specifies how unset wrapper fields are encoded. Defaults to NilFormatNull.
+func (t *FloatValueCodecOptions) SetNilFormat(f NilFormat) *FloatValueCodecOptions {
+	if t.WrapperCodecOptions == nil {
+		t.WrapperCodecOptions = WrapperCodec()
+	}
+	t.WrapperCodecOptions.SetNilFormat(f)
+	return t
+}
+
+// MergeFloatValueCodecOptions combines the given *FloatValueCodecOptions into a single *FloatValueCodecOptions in a last one wins fashion.
+func MergeFloatValueCodecOptions(opts ...*FloatValueCodecOptions) *FloatValueCodecOptions {
+	t := &FloatValueCodecOptions{
+		NonFiniteFormat: &defaultNonFiniteFormat,
+		RoundFloat32:    &defaultRoundFloat32,
+	}
+	wrapperOpts := make([]*WrapperCodecOptions, 0, len(opts))
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		wrapperOpts = append(wrapperOpts, opt.WrapperCodecOptions)
+		if opt.NonFiniteFormat != nil {
+			t.NonFiniteFormat = opt.NonFiniteFormat
+		}
+		if opt.RoundFloat32 != nil {
+			t.RoundFloat32 = opt.RoundFloat32
+		}
+	}
+	t.WrapperCodecOptions = MergeWrapperCodecOptions(wrapperOpts...)
+	return t
+}
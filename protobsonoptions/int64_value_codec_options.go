@@ -0,0 +1,63 @@
+package protobsonoptions
+
+// Int64Format specifies the BSON representation of signed 64-bit integers.
+type Int64Format uint8
+
+// These constants specify the possible BSON representations of signed 64-bit integers.
+const (
+	// Int64FormatInt64 encodes signed 64-bit integers as BSON 64-bit integers.
+	Int64FormatInt64 Int64Format = iota
+	// Int64FormatDecimal128 encodes signed 64-bit integers as Decimal128 values.
+	Int64FormatDecimal128
+	// Int64FormatString encodes signed 64-bit integers as decimal strings, like protojson does.
+	Int64FormatString
+)
+
+var defaultInt64Format = Int64FormatInt64
+
+// Int64ValueCodecOptions represents all possible options for *wrapperspb.Int64Value encoding and decoding.
+type Int64ValueCodecOptions struct {
+	Format *Int64Format // Specifies the BSON representation of values. Defaults to Int64FormatInt64.
+	*WrapperCodecOptions
+}
+
+// Int64ValueCodec creates a new *Int64ValueCodecOptions.
+func Int64ValueCodec() *Int64ValueCodecOptions {
+	return &Int64ValueCodecOptions{
+		WrapperCodecOptions: WrapperCodec(),
+	}
+}
+
+// SetFormat specifies the BSON representation of values. Defaults to Int64FormatInt64.
+func (t *Int64ValueCodecOptions) SetFormat(f Int64Format) *Int64ValueCodecOptions {
+	t.Format = &f
+	return t
+}
+
+// SetNilFormat specifies how unset wrapper fields are encoded. Defaults to NilFormatNull.
+func (t *Int64ValueCodecOptions) SetNilFormat(f NilFormat) *Int64ValueCodecOptions {
+	if t.WrapperCodecOptions == nil {
+		t.WrapperCodecOptions = WrapperCodec()
+	}
+	t.WrapperCodecOptions.SetNilFormat(f)
+	return t
+}
+
+// MergeInt64ValueCodecOptions combines the given *Int64ValueCodecOptions into a single *Int64ValueCodecOptions in a last one wins fashion.
+func MergeInt64ValueCodecOptions(opts ...*Int64ValueCodecOptions) *Int64ValueCodecOptions {
+	t := &Int64ValueCodecOptions{
+		Format: &defaultInt64Format,
+	}
+	wrapperOpts := make([]*WrapperCodecOptions, 0, len(opts))
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		wrapperOpts = append(wrapperOpts, opt.WrapperCodecOptions)
+		if opt.Format != nil {
+			t.Format = opt.Format
+		}
+	}
+	t.WrapperCodecOptions = MergeWrapperCodecOptions(wrapperOpts...)
+	return t
+}
@@ -0,0 +1,47 @@
+package protobsonoptions
+
+// LatLngFormat specifies the BSON representation of *latlng.LatLng values.
+type LatLngFormat uint8
+
+// These constants specify the possible BSON representations of *latlng.LatLng values.
+const (
+	// LatLngFormatGeoJSON encodes coordinates as GeoJSON {type: "Point", coordinates: [<lng>, <lat>]} documents,
+	// usable with 2dsphere indexes.
+	LatLngFormatGeoJSON LatLngFormat = iota
+	// LatLngFormatLegacyPair encodes coordinates as legacy [<lng>, <lat>] pairs, usable with 2d indexes.
+	LatLngFormatLegacyPair
+)
+
+var defaultLatLngFormat = LatLngFormatGeoJSON
+
+// LatLngCodecOptions represents all possible options for *latlng.LatLng encoding and decoding.
+type LatLngCodecOptions struct {
+	Format *LatLngFormat // Specifies the BSON representation of coordinates. Defaults to LatLngFormatGeoJSON.
+}
+
+// LatLngCodec creates a new *LatLngCodecOptions.
+func LatLngCodec() *LatLngCodecOptions {
+	return &LatLngCodecOptions{}
+}
+
+// SetFormat specifies the BSON representation of coordinates. Defaults to LatLngFormatGeoJSON.
+func (t *LatLngCodecOptions) SetFormat(f LatLngFormat) *LatLngCodecOptions {
+	t.Format = &f
+	return t
+}
+
+// MergeLatLngCodecOptions combines the given *LatLngCodecOptions into a single *LatLngCodecOptions in a last one wins fashion.
+func MergeLatLngCodecOptions(opts ...*LatLngCodecOptions) *LatLngCodecOptions {
+	t := &LatLngCodecOptions{
+		Format: &defaultLatLngFormat,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.Format != nil {
+			t.Format = opt.Format
+		}
+	}
+	return t
+}
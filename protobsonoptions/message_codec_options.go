@@ -1,12 +1,27 @@
 package protobsonoptions
 
-import "go.mongodb.org/mongo-driver/bson/bsonoptions"
+import (
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonoptions"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
 
 var defaultUseProtoNames = false
 
 // MessageCodecOptions represents all possible options for proto.Message encoding and decoding.
 type MessageCodecOptions struct {
-	UseProtoNames *bool // Specifies if field names should be marshaled/unmarshaled using their proto names. Defaults to false.
+	UseProtoNames       *bool                                          // Specifies if field names should be marshaled/unmarshaled using their proto names. Defaults to false.
+	Int64Format         *Int64Format                                   // Specifies the BSON representation of int64, sint64 and sfixed64 fields. Defaults to Int64FormatInt64.
+	UInt64Format        *UInt64Format                                  // Specifies the BSON representation of uint64 and fixed64 fields. Defaults to UInt64FormatInt64.
+	NonFiniteFormat     *NonFiniteFormat                               // Specifies the BSON representation of NaN and infinite float and double fields. Defaults to NonFiniteFormatDouble.
+	RoundFloat32        *bool                                          // Specifies if float fields should be rounded to their shortest decimal representation when widened to doubles. Defaults to false.
+	BinarySubtype       *byte                                          // Specifies the binary subtype of bytes fields, unless overridden by the (protobson.field).binary_subtype option. Defaults to bsontype.BinaryGeneric.
+	DecodeBase64Strings *bool                                          // Specifies if strings decoded into bytes fields should be base64 decoded rather than taken as is. Defaults to false.
+	StringFormats       map[protoreflect.FullName]StringFormat         // Specifies the BSON representation of string fields by full name, taking precedence over the (protobson.field).string_format option. Defaults to StringFormatString.
+	Codecs              map[protoreflect.FullName]bsoncodec.ValueCodec // Specifies the Codecs of fields by field full name or by message type full name, taking precedence over the registry and the other options. Defaults to none.
+	// Only DecodeZeroStruct and EncodeOmitDefaultStruct apply to messages. AllowUnexportedFields,
+	// DecodeDeepZeroInline and OverwriteDuplicatedInlinedFields have no effect, as messages have no
+	// unexported or inlined fields to encode.
 	*bsonoptions.StructCodecOptions
 }
 
@@ -16,6 +31,60 @@ func (t *MessageCodecOptions) SetUseProtoNames(b bool) *MessageCodecOptions {
 	return t
 }
 
+// SetInt64Format specifies the BSON representation of int64, sint64 and sfixed64 fields. Defaults to Int64FormatInt64.
+func (t *MessageCodecOptions) SetInt64Format(f Int64Format) *MessageCodecOptions {
+	t.Int64Format = &f
+	return t
+}
+
+// SetUInt64Format specifies the BSON representation of uint64 and fixed64 fields. Defaults to UInt64FormatInt64.
+func (t *MessageCodecOptions) SetUInt64Format(f UInt64Format) *MessageCodecOptions {
+	t.UInt64Format = &f
+	return t
+}
+
+// SetNonFiniteFormat specifies the BSON representation of NaN and infinite float and double fields. Defaults to NonFiniteFormatDouble.
+func (t *MessageCodecOptions) SetNonFiniteFormat(f NonFiniteFormat) *MessageCodecOptions {
+	t.NonFiniteFormat = &f
+	return t
+}
+
+// SetRoundFloat32 specifies if float fields should be rounded to their shortest decimal representation when widened to doubles. Defaults to false.
+func (t *MessageCodecOptions) SetRoundFloat32(b bool) *MessageCodecOptions {
+	t.RoundFloat32 = &b
+	return t
+}
+
+// SetBinarySubtype specifies the binary subtype of bytes fields, unless overridden by the (protobson.field).binary_subtype option. Defaults to bsontype.BinaryGeneric.
+func (t *MessageCodecOptions) SetBinarySubtype(b byte) *MessageCodecOptions {
+	t.BinarySubtype = &b
+	return t
+}
+
+// SetDecodeBase64Strings specifies if strings decoded into bytes fields should be base64 decoded rather than taken as is. Defaults to false.
+func (t *MessageCodecOptions) SetDecodeBase64Strings(b bool) *MessageCodecOptions {
+	t.DecodeBase64Strings = &b
+	return t
+}
+
+// SetStringFormat specifies the BSON representation of the string field of full name field, taking precedence over the (protobson.field).string_format option. Defaults to StringFormatString.
+func (t *MessageCodecOptions) SetStringFormat(field protoreflect.FullName, f StringFormat) *MessageCodecOptions {
+	if t.StringFormats == nil {
+		t.StringFormats = make(map[protoreflect.FullName]StringFormat)
+	}
+	t.StringFormats[field] = f
+	return t
+}
+
+// SetCodec specifies the Codec of the fields of full name name, or of message type of full name name, taking precedence over the registry and the other options. Defaults to none.
+func (t *MessageCodecOptions) SetCodec(name protoreflect.FullName, c bsoncodec.ValueCodec) *MessageCodecOptions {
+	if t.Codecs == nil {
+		t.Codecs = make(map[protoreflect.FullName]bsoncodec.ValueCodec)
+	}
+	t.Codecs[name] = c
+	return t
+}
+
 // MessageCodec creates a new *MessageCodecOptions.
 func MessageCodec() *MessageCodecOptions {
 	return &MessageCodecOptions{
@@ -26,7 +95,15 @@ func MessageCodec() *MessageCodecOptions {
 // MergeMessageCodecOptions combines the given *MessageCodecOptions into a single *MessageCodecOptions in a last one wins fashion.
 func MergeMessageCodecOptions(opts ...*MessageCodecOptions) *MessageCodecOptions {
 	msgOpts := &MessageCodecOptions{
-		UseProtoNames: &defaultUseProtoNames,
+		UseProtoNames:       &defaultUseProtoNames,
+		Int64Format:         &defaultInt64Format,
+		UInt64Format:        &defaultUInt64Format,
+		NonFiniteFormat:     &defaultNonFiniteFormat,
+		RoundFloat32:        &defaultRoundFloat32,
+		BinarySubtype:       &defaultBinarySubtype,
+		DecodeBase64Strings: &defaultDecodeBase64Strings,
+		StringFormats:       make(map[protoreflect.FullName]StringFormat),
+		Codecs:              make(map[protoreflect.FullName]bsoncodec.ValueCodec),
 	}
 	structOpts := make([]*bsonoptions.StructCodecOptions, 0, len(opts))
 	for _, opt := range opts {
@@ -36,6 +113,30 @@ func MergeMessageCodecOptions(opts ...*MessageCodecOptions) *MessageCodecOptions
 		if opt.UseProtoNames != nil {
 			msgOpts.UseProtoNames = opt.UseProtoNames
 		}
+		if opt.Int64Format != nil {
+			msgOpts.Int64Format = opt.Int64Format
+		}
+		if opt.UInt64Format != nil {
+			msgOpts.UInt64Format = opt.UInt64Format
+		}
+		if opt.NonFiniteFormat != nil {
+			msgOpts.NonFiniteFormat = opt.NonFiniteFormat
+		}
+		if opt.RoundFloat32 != nil {
+			msgOpts.RoundFloat32 = opt.RoundFloat32
+		}
+		if opt.BinarySubtype != nil {
+			msgOpts.BinarySubtype = opt.BinarySubtype
+		}
+		if opt.DecodeBase64Strings != nil {
+			msgOpts.DecodeBase64Strings = opt.DecodeBase64Strings
+		}
+		for field, f := range opt.StringFormats {
+			msgOpts.StringFormats[field] = f
+		}
+		for name, c := range opt.Codecs {
+			msgOpts.Codecs[name] = c
+		}
 		structOpts = append(structOpts, opt.StructCodecOptions)
 	}
 	msgOpts.StructCodecOptions = bsonoptions.MergeStructCodecOptions(structOpts...)
@@ -0,0 +1,48 @@
+package protobsonoptions
+
+// MoneyFormat specifies the BSON representation of the amount of *money.Money values.
+type MoneyFormat uint8
+
+// These constants specify the possible BSON representations of the amount of *money.Money values.
+// Money values are always encoded as {currency: <string>, amount: <amount>} documents.
+const (
+	// MoneyFormatDecimal128 encodes amounts as Decimal128 values in major units, e.g. 12.34 for 12.34 EUR.
+	MoneyFormatDecimal128 MoneyFormat = iota
+	// MoneyFormatMinorUnits encodes amounts as 64-bit integers counting the minor units of the currency
+	// as defined by ISO 4217, e.g. 1234 for 12.34 EUR.
+	MoneyFormatMinorUnits
+)
+
+var defaultMoneyFormat = MoneyFormatDecimal128
+
+// MoneyCodecOptions represents all possible options for *money.Money encoding and decoding.
+type MoneyCodecOptions struct {
+	Format *MoneyFormat // Specifies the BSON representation of amounts. Integers are decoded in the unit of this format. Defaults to MoneyFormatDecimal128.
+}
+
+// MoneyCodec creates a new *MoneyCodecOptions.
+func MoneyCodec() *MoneyCodecOptions {
+	return &MoneyCodecOptions{}
+}
+
+// SetFormat specifies the BSON representation of amounts. Integers are decoded in the unit of this format. Defaults to MoneyFormatDecimal128.
+func (t *MoneyCodecOptions) SetFormat(f MoneyFormat) *MoneyCodecOptions {
+	t.Format = &f
+	return t
+}
+
+// MergeMoneyCodecOptions combines the given *MoneyCodecOptions into a single *MoneyCodecOptions in a last one wins fashion.
+func MergeMoneyCodecOptions(opts ...*MoneyCodecOptions) *MoneyCodecOptions {
+	t := &MoneyCodecOptions{
+		Format: &defaultMoneyFormat,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.Format != nil {
+			t.Format = opt.Format
+		}
+	}
+	return t
+}
@@ -0,0 +1,35 @@
+package protobsonoptions
+
+var defaultMonthFormat = EnumFormatNumber
+
+// MonthCodecOptions represents all possible options for month.Month encoding and decoding.
+type MonthCodecOptions struct {
+	Format *EnumFormat // Specifies the BSON representation of month values. Defaults to EnumFormatNumber, matching ISO 8601 numbering.
+}
+
+// MonthCodec creates a new *MonthCodecOptions.
+func MonthCodec() *MonthCodecOptions {
+	return &MonthCodecOptions{}
+}
+
+// SetFormat specifies the BSON representation of month values. Defaults to EnumFormatNumber, matching ISO 8601 numbering.
+func (t *MonthCodecOptions) SetFormat(f EnumFormat) *MonthCodecOptions {
+	t.Format = &f
+	return t
+}
+
+// MergeMonthCodecOptions combines the given *MonthCodecOptions into a single *MonthCodecOptions in a last one wins fashion.
+func MergeMonthCodecOptions(opts ...*MonthCodecOptions) *MonthCodecOptions {
+	t := &MonthCodecOptions{
+		Format: &defaultMonthFormat,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.Format != nil {
+			t.Format = opt.Format
+		}
+	}
+	return t
+}
@@ -0,0 +1,37 @@
+package protobsonoptions
+
+import "google.golang.org/protobuf/reflect/protoregistry"
+
+var defaultOperationResolver = protoregistry.GlobalTypes
+
+// OperationCodecOptions represents all possible options for google.longrunning.Operation encoding and decoding.
+type OperationCodecOptions struct {
+	Resolver *protoregistry.Types // Specifies the types used to expand metadata and responses. Defaults to protoregistry.GlobalTypes.
+}
+
+// OperationCodec creates a new *OperationCodecOptions.
+func OperationCodec() *OperationCodecOptions {
+	return &OperationCodecOptions{}
+}
+
+// SetResolver specifies the types used to expand metadata and responses. Defaults to protoregistry.GlobalTypes.
+func (t *OperationCodecOptions) SetResolver(r *protoregistry.Types) *OperationCodecOptions {
+	t.Resolver = r
+	return t
+}
+
+// MergeOperationCodecOptions combines the given *OperationCodecOptions into a single *OperationCodecOptions in a last one wins fashion.
+func MergeOperationCodecOptions(opts ...*OperationCodecOptions) *OperationCodecOptions {
+	t := &OperationCodecOptions{
+		Resolver: defaultOperationResolver,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.Resolver != nil {
+			t.Resolver = opt.Resolver
+		}
+	}
+	return t
+}
@@ -0,0 +1,35 @@
+package protobsonoptions
+
+var defaultPhoneNumberSearchKeys = false
+
+// PhoneNumberCodecOptions represents all possible options for *phone_number.PhoneNumber encoding and decoding.
+type PhoneNumberCodecOptions struct {
+	SearchKeys *bool // Specifies if a search element holding the lowercase RFC 3966 URI of the phone number should be encoded. Defaults to false.
+}
+
+// PhoneNumberCodec creates a new *PhoneNumberCodecOptions.
+func PhoneNumberCodec() *PhoneNumberCodecOptions {
+	return &PhoneNumberCodecOptions{}
+}
+
+// SetSearchKeys specifies if a search element holding the lowercase RFC 3966 URI of the phone number should be encoded. Defaults to false.
+func (t *PhoneNumberCodecOptions) SetSearchKeys(b bool) *PhoneNumberCodecOptions {
+	t.SearchKeys = &b
+	return t
+}
+
+// MergePhoneNumberCodecOptions combines the given *PhoneNumberCodecOptions into a single *PhoneNumberCodecOptions in a last one wins fashion.
+func MergePhoneNumberCodecOptions(opts ...*PhoneNumberCodecOptions) *PhoneNumberCodecOptions {
+	t := &PhoneNumberCodecOptions{
+		SearchKeys: &defaultPhoneNumberSearchKeys,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.SearchKeys != nil {
+			t.SearchKeys = opt.SearchKeys
+		}
+	}
+	return t
+}
@@ -0,0 +1,35 @@
+package protobsonoptions
+
+var defaultPostalAddressSearchKeys = false
+
+// PostalAddressCodecOptions represents all possible options for *postaladdress.PostalAddress encoding and decoding.
+type PostalAddressCodecOptions struct {
+	SearchKeys *bool // Specifies if a search sub-document holding lowercase copies of the searchable address components should be encoded. Defaults to false.
+}
+
+// PostalAddressCodec creates a new *PostalAddressCodecOptions.
+func PostalAddressCodec() *PostalAddressCodecOptions {
+	return &PostalAddressCodecOptions{}
+}
+
+// SetSearchKeys specifies if a search sub-document holding lowercase copies of the searchable address components should be encoded. Defaults to false.
+func (t *PostalAddressCodecOptions) SetSearchKeys(b bool) *PostalAddressCodecOptions {
+	t.SearchKeys = &b
+	return t
+}
+
+// MergePostalAddressCodecOptions combines the given *PostalAddressCodecOptions into a single *PostalAddressCodecOptions in a last one wins fashion.
+func MergePostalAddressCodecOptions(opts ...*PostalAddressCodecOptions) *PostalAddressCodecOptions {
+	t := &PostalAddressCodecOptions{
+		SearchKeys: &defaultPostalAddressSearchKeys,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.SearchKeys != nil {
+			t.SearchKeys = opt.SearchKeys
+		}
+	}
+	return t
+}
@@ -0,0 +1,155 @@
+package protobsonoptions
+
+// RegistryOptions represents all possible options for the registries built by protobson, given
+// to the Codecs they register.
+type RegistryOptions struct {
+	Message     *MessageCodecOptions     // Specifies the options of the proto.Message Codec. Defaults to none.
+	Timestamp   *TimestampCodecOptions   // Specifies the options of the *timestamppb.Timestamp Codec. Defaults to none.
+	Duration    *DurationCodecOptions    // Specifies the options of the *durationpb.Duration Codec. Defaults to none.
+	BoolValue   *WrapperCodecOptions     // Specifies the options of the *wrapperspb.BoolValue Codec. Defaults to none.
+	BytesValue  *BytesValueCodecOptions  // Specifies the options of the *wrapperspb.BytesValue Codec. Defaults to none.
+	DoubleValue *DoubleValueCodecOptions // Specifies the options of the *wrapperspb.DoubleValue Codec. Defaults to none.
+	FloatValue  *FloatValueCodecOptions  // Specifies the options of the *wrapperspb.FloatValue Codec. Defaults to none.
+	Int32Value  *WrapperCodecOptions     // Specifies the options of the *wrapperspb.Int32Value Codec. Defaults to none.
+	Int64Value  *Int64ValueCodecOptions  // Specifies the options of the *wrapperspb.Int64Value Codec. Defaults to none.
+	StringValue *WrapperCodecOptions     // Specifies the options of the *wrapperspb.StringValue Codec. Defaults to none.
+	UInt32Value *WrapperCodecOptions     // Specifies the options of the *wrapperspb.UInt32Value Codec. Defaults to none.
+	UInt64Value *UInt64ValueCodecOptions // Specifies the options of the *wrapperspb.UInt64Value Codec. Defaults to none.
+	DateTime    *DateTimeCodecOptions    // Specifies the options of the *datetime.DateTime Codec. Defaults to none.
+}
+
+// Registry creates a new *RegistryOptions.
+func Registry() *RegistryOptions {
+	return &RegistryOptions{}
+}
+
+// SetMessage specifies the options of the proto.Message Codec. Defaults to none.
+func (t *RegistryOptions) SetMessage(opts *MessageCodecOptions) *RegistryOptions {
+	t.Message = opts
+	return t
+}
+
+// SetTimestamp specifies the options of the *timestamppb.Timestamp Codec. Defaults to none.
+func (t *RegistryOptions) SetTimestamp(opts *TimestampCodecOptions) *RegistryOptions {
+	t.Timestamp = opts
+	return t
+}
+
+// SetDuration specifies the options of the *durationpb.Duration Codec. Defaults to none.
+func (t *RegistryOptions) SetDuration(opts *DurationCodecOptions) *RegistryOptions {
+	t.Duration = opts
+	return t
+}
+
+// SetBoolValue specifies the options of the *wrapperspb.BoolValue Codec. Defaults to none.
+func (t *RegistryOptions) SetBoolValue(opts *WrapperCodecOptions) *RegistryOptions {
+	t.BoolValue = opts
+	return t
+}
+
+// SetBytesValue specifies the options of the *wrapperspb.BytesValue Codec. Defaults to none.
+func (t *RegistryOptions) SetBytesValue(opts *BytesValueCodecOptions) *RegistryOptions {
+	t.BytesValue = opts
+	return t
+}
+
+// SetDoubleValue specifies the options of the *wrapperspb.DoubleValue Codec. Defaults to none.
+func (t *RegistryOptions) SetDoubleValue(opts *DoubleValueCodecOptions) *RegistryOptions {
+	t.DoubleValue = opts
+	return t
+}
+
+// SetFloatValue specifies the options of the *wrapperspb.FloatValue Codec. Defaults to none.
+func (t *RegistryOptions) SetFloatValue(opts *FloatValueCodecOptions) *RegistryOptions {
+	t.FloatValue = opts
+	return t
+}
+
+// SetInt32Value specifies the options of the *wrapperspb.Int32Value Codec. Defaults to none.
+func (t *RegistryOptions) SetInt32Value(opts *WrapperCodecOptions) *RegistryOptions {
+	t.Int32Value = opts
+	return t
+}
+
+// SetInt64Value specifies the options of the *wrapperspb.Int64Value Codec. Defaults to none.
+func (t *RegistryOptions) SetInt64Value(opts *Int64ValueCodecOptions) *RegistryOptions {
+	t.Int64Value = opts
+	return t
+}
+
+// SetStringValue specifies the options of the *wrapperspb.StringValue Codec. Defaults to none.
+func (t *RegistryOptions) SetStringValue(opts *WrapperCodecOptions) *RegistryOptions {
+	t.StringValue = opts
+	return t
+}
+
+// SetUInt32Value specifies the options of the *wrapperspb.UInt32Value Codec. Defaults to none.
+func (t *RegistryOptions) SetUInt32Value(opts *WrapperCodecOptions) *RegistryOptions {
+	t.UInt32Value = opts
+	return t
+}
+
+// SetUInt64Value specifies the options of the *wrapperspb.UInt64Value Codec. Defaults to none.
+func (t *RegistryOptions) SetUInt64Value(opts *UInt64ValueCodecOptions) *RegistryOptions {
+	t.UInt64Value = opts
+	return t
+}
+
+// SetDateTime specifies the options of the *datetime.DateTime Codec. Defaults to none.
+func (t *RegistryOptions) SetDateTime(opts *DateTimeCodecOptions) *RegistryOptions {
+	t.DateTime = opts
+	return t
+}
+
+// MergeRegistryOptions combines the given *RegistryOptions into a single *RegistryOptions in a last one wins fashion.
+// The options of each Codec are combined with the Merge function of their type.
+func MergeRegistryOptions(opts ...*RegistryOptions) *RegistryOptions {
+	var (
+		messageOpts     []*MessageCodecOptions
+		timestampOpts   []*TimestampCodecOptions
+		durationOpts    []*DurationCodecOptions
+		boolValueOpts   []*WrapperCodecOptions
+		bytesValueOpts  []*BytesValueCodecOptions
+		doubleValueOpts []*DoubleValueCodecOptions
+		floatValueOpts  []*FloatValueCodecOptions
+		int32ValueOpts  []*WrapperCodecOptions
+		int64ValueOpts  []*Int64ValueCodecOptions
+		stringValueOpts []*WrapperCodecOptions
+		uint32ValueOpts []*WrapperCodecOptions
+		uint64ValueOpts []*UInt64ValueCodecOptions
+		dateTimeOpts    []*DateTimeCodecOptions
+	)
+	t := &RegistryOptions{}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		messageOpts = append(messageOpts, opt.Message)
+		timestampOpts = append(timestampOpts, opt.Timestamp)
+		durationOpts = append(durationOpts, opt.Duration)
+		boolValueOpts = append(boolValueOpts, opt.BoolValue)
+		bytesValueOpts = append(bytesValueOpts, opt.BytesValue)
+		doubleValueOpts = append(doubleValueOpts, opt.DoubleValue)
+		floatValueOpts = append(floatValueOpts, opt.FloatValue)
+		int32ValueOpts = append(int32ValueOpts, opt.Int32Value)
+		int64ValueOpts = append(int64ValueOpts, opt.Int64Value)
+		stringValueOpts = append(stringValueOpts, opt.StringValue)
+		uint32ValueOpts = append(uint32ValueOpts, opt.UInt32Value)
+		uint64ValueOpts = append(uint64ValueOpts, opt.UInt64Value)
+		dateTimeOpts = append(dateTimeOpts, opt.DateTime)
+	}
+	t.Message = MergeMessageCodecOptions(messageOpts...)
+	t.Timestamp = MergeTimestampCodecOptions(timestampOpts...)
+	t.Duration = MergeDurationCodecOptions(durationOpts...)
+	t.BoolValue = MergeWrapperCodecOptions(boolValueOpts...)
+	t.BytesValue = MergeBytesValueCodecOptions(bytesValueOpts...)
+	t.DoubleValue = MergeDoubleValueCodecOptions(doubleValueOpts...)
+	t.FloatValue = MergeFloatValueCodecOptions(floatValueOpts...)
+	t.Int32Value = MergeWrapperCodecOptions(int32ValueOpts...)
+	t.Int64Value = MergeInt64ValueCodecOptions(int64ValueOpts...)
+	t.StringValue = MergeWrapperCodecOptions(stringValueOpts...)
+	t.UInt32Value = MergeWrapperCodecOptions(uint32ValueOpts...)
+	t.UInt64Value = MergeUInt64ValueCodecOptions(uint64ValueOpts...)
+	t.DateTime = MergeDateTimeCodecOptions(dateTimeOpts...)
+	return t
+}
@@ -0,0 +1,37 @@
+package protobsonoptions
+
+import "google.golang.org/protobuf/reflect/protoregistry"
+
+var defaultStatusResolver = protoregistry.GlobalTypes
+
+// StatusCodecOptions represents all possible options for *status.Status encoding and decoding.
+type StatusCodecOptions struct {
+	Resolver *protoregistry.Types // Specifies the types used to expand details. Defaults to protoregistry.GlobalTypes.
+}
+
+// StatusCodec creates a new *StatusCodecOptions.
+func StatusCodec() *StatusCodecOptions {
+	return &StatusCodecOptions{}
+}
+
+// SetResolver specifies the types used to expand details. Defaults to protoregistry.GlobalTypes.
+func (t *StatusCodecOptions) SetResolver(r *protoregistry.Types) *StatusCodecOptions {
+	t.Resolver = r
+	return t
+}
+
+// MergeStatusCodecOptions combines the given *StatusCodecOptions into a single *StatusCodecOptions in a last one wins fashion.
+func MergeStatusCodecOptions(opts ...*StatusCodecOptions) *StatusCodecOptions {
+	t := &StatusCodecOptions{
+		Resolver: defaultStatusResolver,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.Resolver != nil {
+			t.Resolver = opt.Resolver
+		}
+	}
+	return t
+}
@@ -0,0 +1,18 @@
+package protobsonoptions
+
+// StringFormat specifies the BSON representation of string fields.
+type StringFormat uint8
+
+// These constants specify the possible BSON representations of string fields.
+const (
+	// StringFormatString encodes strings as BSON strings.
+	StringFormatString StringFormat = iota
+	// StringFormatObjectID encodes strings holding hexadecimal ObjectIDs as BSON ObjectIDs, and
+	// decodes them back to lowercase hexadecimal strings. Empty strings are rejected: fields with
+	// presence are needed to store absent ObjectIDs, as null, which is decoded as an empty string.
+	StringFormatObjectID
+	// StringFormatUUID encodes strings holding UUIDs as binary of the UUID subtype, and decodes
+	// them back to lowercase hyphenated strings. Empty strings are rejected like with
+	// StringFormatObjectID.
+	StringFormatUUID
+)
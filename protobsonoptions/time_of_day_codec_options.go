@@ -0,0 +1,50 @@
+package protobsonoptions
+
+// TimeOfDayFormat specifies the BSON representation of *timeofday.TimeOfDay values.
+type TimeOfDayFormat uint8
+
+// These constants specify the possible BSON representations of *timeofday.TimeOfDay values.
+// The end of day "24:00:00" is encoded as 24 hours since midnight.
+const (
+	// TimeOfDayFormatMillis encodes times of day as 32-bit integers counting milliseconds since
+	// midnight, truncating sub-millisecond precision.
+	TimeOfDayFormatMillis TimeOfDayFormat = iota
+	// TimeOfDayFormatNanos encodes times of day as 64-bit integers counting nanoseconds since midnight.
+	TimeOfDayFormatNanos
+	// TimeOfDayFormatString encodes times of day as "HH:MM:SS.fffffffff" strings.
+	TimeOfDayFormatString
+)
+
+var defaultTimeOfDayFormat = TimeOfDayFormatMillis
+
+// TimeOfDayCodecOptions represents all possible options for *timeofday.TimeOfDay encoding and decoding.
+type TimeOfDayCodecOptions struct {
+	Format *TimeOfDayFormat // Specifies the BSON representation of times of day. Numbers are decoded in the unit of this format. Defaults to TimeOfDayFormatMillis.
+}
+
+// TimeOfDayCodec creates a new *TimeOfDayCodecOptions.
+func TimeOfDayCodec() *TimeOfDayCodecOptions {
+	return &TimeOfDayCodecOptions{}
+}
+
+// SetFormat specifies the BSON representation of times of day. Numbers are decoded in the unit of this format. Defaults to TimeOfDayFormatMillis.
+func (t *TimeOfDayCodecOptions) SetFormat(f TimeOfDayFormat) *TimeOfDayCodecOptions {
+	t.Format = &f
+	return t
+}
+
+// MergeTimeOfDayCodecOptions combines the given *TimeOfDayCodecOptions into a single *TimeOfDayCodecOptions in a last one wins fashion.
+func MergeTimeOfDayCodecOptions(opts ...*TimeOfDayCodecOptions) *TimeOfDayCodecOptions {
+	t := &TimeOfDayCodecOptions{
+		Format: &defaultTimeOfDayFormat,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.Format != nil {
+			t.Format = opt.Format
+		}
+	}
+	return t
+}
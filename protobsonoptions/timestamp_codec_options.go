@@ -0,0 +1,74 @@
+package protobsonoptions
+
+// TimestampFormat specifies the BSON representation of *timestamppb.Timestamp values.
+type TimestampFormat uint8
+
+// These constants specify the possible BSON representations of *timestamppb.Timestamp values.
+const (
+	// TimestampFormatDateTime encodes timestamps as BSON dates, truncating them to the millisecond.
+	TimestampFormatDateTime TimestampFormat = iota
+	// TimestampFormatDocument encodes timestamps as {seconds: <int64>, nanos: <int32>} documents.
+	TimestampFormatDocument
+	// TimestampFormatString encodes timestamps as RFC 3339 strings with nanosecond precision.
+	TimestampFormatString
+	// TimestampFormatNanos encodes timestamps as 64-bit integers counting nanoseconds since the Unix epoch.
+	// As 64-bit integers in the range of valid timestamps in milliseconds are decoded as milliseconds,
+	// timestamps between 1969-12-31T06:44:24Z and 1970-01-03T22:23:23Z, apart from the Unix epoch, do not round-trip.
+	TimestampFormatNanos
+	// TimestampFormatDateTimeNanos encodes timestamps as {date: <date>, nanos: <int32>} documents, where
+	// nanos holds the sub-millisecond part of the timestamp. The date element stays queryable as a BSON date.
+	TimestampFormatDateTimeNanos
+	// TimestampFormatBSONTimestamp encodes timestamps as BSON timestamps, storing the seconds in the time
+	// component and 1 in the increment component, which is an ordinal rather than a fraction of a second and
+	// is ignored when decoding. Nanoseconds are not preserved. Only timestamps between the Unix epoch and
+	// year 2106 can be represented.
+	TimestampFormatBSONTimestamp
+)
+
+var (
+	defaultTimestampFormat          = TimestampFormatDateTime
+	defaultTimestampErrorOnTruncate = false
+)
+
+// TimestampCodecOptions represents all possible options for *timestamppb.Timestamp encoding and decoding.
+type TimestampCodecOptions struct {
+	Format          *TimestampFormat // Specifies the BSON representation of timestamps. Defaults to TimestampFormatDateTime.
+	ErrorOnTruncate *bool            // Specifies if an error should be returned rather than losing precision on encoding. Defaults to false.
+}
+
+// TimestampCodec creates a new *TimestampCodecOptions.
+func TimestampCodec() *TimestampCodecOptions {
+	return &TimestampCodecOptions{}
+}
+
+// SetFormat specifies the BSON representation of timestamps. Defaults to TimestampFormatDateTime.
+func (t *TimestampCodecOptions) SetFormat(f TimestampFormat) *TimestampCodecOptions {
+	t.Format = &f
+	return t
+}
+
+// SetErrorOnTruncate specifies if an error should be returned rather than losing precision on encoding. Defaults to false.
+func (t *TimestampCodecOptions) SetErrorOnTruncate(b bool) *TimestampCodecOptions {
+	t.ErrorOnTruncate = &b
+	return t
+}
+
+// MergeTimestampCodecOptions combines the given *TimestampCodecOptions into a single *TimestampCodecOptions in a last one wins fashion.
+func MergeTimestampCodecOptions(opts ...*TimestampCodecOptions) *TimestampCodecOptions {
+	t := &TimestampCodecOptions{
+		Format:          &defaultTimestampFormat,
+		ErrorOnTruncate: &defaultTimestampErrorOnTruncate,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.Format != nil {
+			t.Format = opt.Format
+		}
+		if opt.ErrorOnTruncate != nil {
+			t.ErrorOnTruncate = opt.ErrorOnTruncate
+		}
+	}
+	return t
+}
@@ -0,0 +1,65 @@
+package protobsonoptions
+
+// UInt64Format specifies the BSON representation of unsigned 64-bit integers.
+type UInt64Format uint8
+
+// These constants specify the possible BSON representations of unsigned 64-bit integers.
+const (
+	// UInt64FormatInt64 encodes unsigned 64-bit integers as BSON 64-bit integers. Values above
+	// math.MaxInt64 cannot be represented and return an error.
+	UInt64FormatInt64 UInt64Format = iota
+	// UInt64FormatDecimal128 encodes unsigned 64-bit integers as Decimal128 values.
+	UInt64FormatDecimal128
+	// UInt64FormatString encodes unsigned 64-bit integers as 20 digits zero-padded decimal strings,
+	// which sort in numerical order.
+	UInt64FormatString
+)
+
+var defaultUInt64Format = UInt64FormatInt64
+
+// UInt64ValueCodecOptions represents all possible options for *wrapperspb.UInt64Value encoding and decoding.
+type UInt64ValueCodecOptions struct {
+	Format *UInt64Format // Specifies the BSON representation of values. Defaults to UInt64FormatInt64.
+	*WrapperCodecOptions
+}
+
+// UInt64ValueCodec creates a new *UInt64ValueCodecOptions.
+func UInt64ValueCodec() *UInt64ValueCodecOptions {
+	return &UInt64ValueCodecOptions{
+		WrapperCodecOptions: WrapperCodec(),
+	}
+}
+
+// SetFormat specifies the BSON representation of values. Defaults to UInt64FormatInt64.
+func (t *UInt64ValueCodecOptions) SetFormat(f UInt64Format) *UInt64ValueCodecOptions {
+	t.Format = &f
+	return t
+}
+
+// SetNilFormat specifies how unset wrapper fields are encoded. Defaults to NilFormatNull.
+func (t *UInt64ValueCodecOptions) SetNilFormat(f NilFormat) *UInt64ValueCodecOptions {
+	if t.WrapperCodecOptions == nil {
+		t.WrapperCodecOptions = WrapperCodec()
+	}
+	t.WrapperCodecOptions.SetNilFormat(f)
+	return t
+}
+
+// MergeUInt64ValueCodecOptions combines the given *UInt64ValueCodecOptions into a single *UInt64ValueCodecOptions in a last one wins fashion.
+func MergeUInt64ValueCodecOptions(opts ...*UInt64ValueCodecOptions) *UInt64ValueCodecOptions {
+	t := &UInt64ValueCodecOptions{
+		Format: &defaultUInt64Format,
+	}
+	wrapperOpts := make([]*WrapperCodecOptions, 0, len(opts))
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		wrapperOpts = append(wrapperOpts, opt.WrapperCodecOptions)
+		if opt.Format != nil {
+			t.Format = opt.Format
+		}
+	}
+	t.WrapperCodecOptions = MergeWrapperCodecOptions(wrapperOpts...)
+	return t
+}
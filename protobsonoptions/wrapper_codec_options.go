@@ -0,0 +1,47 @@
+package protobsonoptions
+
+// NilFormat specifies how unset wrapper fields are encoded.
+type NilFormat uint8
+
+// These constants specify how unset wrapper fields are encoded.
+const (
+	// NilFormatNull encodes unset wrapper fields as null.
+	NilFormatNull NilFormat = iota
+	// NilFormatOmit omits unset wrapper fields from the documents encoded by the MessageCodec.
+	// Nil wrappers encoded on their own are still written as null.
+	NilFormatOmit
+)
+
+var defaultNilFormat = NilFormatNull
+
+// WrapperCodecOptions represents the options shared by all the wrapperspb codecs.
+type WrapperCodecOptions struct {
+	NilFormat *NilFormat // Specifies how unset wrapper fields are encoded. Defaults to NilFormatNull.
+}
+
+// WrapperCodec creates a new *WrapperCodecOptions.
+func WrapperCodec() *WrapperCodecOptions {
+	return &WrapperCodecOptions{}
+}
+
+// SetNilFormat specifies how unset wrapper fields are encoded. Defaults to NilFormatNull.
+func (t *WrapperCodecOptions) SetNilFormat(f NilFormat) *WrapperCodecOptions {
+	t.NilFormat = &f
+	return t
+}
+
+// MergeWrapperCodecOptions combines the given *WrapperCodecOptions into a single *WrapperCodecOptions in a last one wins fashion.
+func MergeWrapperCodecOptions(opts ...*WrapperCodecOptions) *WrapperCodecOptions {
+	t := &WrapperCodecOptions{
+		NilFormat: &defaultNilFormat,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.NilFormat != nil {
+			t.NilFormat = opt.NilFormat
+		}
+	}
+	return t
+}
@@ -0,0 +1,222 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: protobsonpb/options.proto
+
+package protobsonpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type FieldOptions_StringFormat int32
+
+const (
+	FieldOptions_STRING    FieldOptions_StringFormat = 0
+	FieldOptions_OBJECT_ID FieldOptions_StringFormat = 1
+	FieldOptions_UUID      FieldOptions_StringFormat = 2
+)
+
+// Enum value maps for FieldOptions_StringFormat.
+var (
+	FieldOptions_StringFormat_name = map[int32]string{
+		0: "STRING",
+		1: "OBJECT_ID",
+		2: "UUID",
+	}
+	FieldOptions_StringFormat_value = map[string]int32{
+		"STRING":    0,
+		"OBJECT_ID": 1,
+		"UUID":      2,
+	}
+)
+
+func (x FieldOptions_StringFormat) Enum() *FieldOptions_StringFormat {
+	p := new(FieldOptions_StringFormat)
+	*p = x
+	return p
+}
+
+func (x FieldOptions_StringFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FieldOptions_StringFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_protobsonpb_options_proto_enumTypes[0].Descriptor()
+}
+
+func (FieldOptions_StringFormat) Type() protoreflect.EnumType {
+	return &file_protobsonpb_options_proto_enumTypes[0]
+}
+
+func (x FieldOptions_StringFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Do not use.
+func (x *FieldOptions_StringFormat) UnmarshalJSON(b []byte) error {
+	num, err := protoimpl.X.UnmarshalJSONEnum(x.Descriptor(), b)
+	if err != nil {
+		return err
+	}
+	*x = FieldOptions_StringFormat(num)
+	return nil
+}
+
+// Deprecated: Use FieldOptions_StringFormat.Descriptor instead.
+func (FieldOptions_StringFormat) EnumDescriptor() ([]byte, []int) {
+	return file_protobsonpb_options_proto_rawDescGZIP(), []int{0, 0}
+}
+
+type FieldOptions struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	BinarySubtype *uint32                    `protobuf:"varint,1,opt,name=binary_subtype,json=binarySubtype" json:"binary_subtype,omitempty"`
+	StringFormat  *FieldOptions_StringFormat `protobuf:"varint,2,opt,name=string_format,json=stringFormat,enum=protobson.FieldOptions_StringFormat" json:"string_format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldOptions) Reset() {
+	*x = FieldOptions{}
+	mi := &file_protobsonpb_options_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldOptions) ProtoMessage() {}
+
+func (x *FieldOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_protobsonpb_options_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldOptions.ProtoReflect.Descriptor instead.
+func (*FieldOptions) Descriptor() ([]byte, []int) {
+	return file_protobsonpb_options_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FieldOptions) GetBinarySubtype() uint32 {
+	if x != nil && x.BinarySubtype != nil {
+		return *x.BinarySubtype
+	}
+	return 0
+}
+
+func (x *FieldOptions) GetStringFormat() FieldOptions_StringFormat {
+	if x != nil && x.StringFormat != nil {
+		return *x.StringFormat
+	}
+	return FieldOptions_STRING
+}
+
+var file_protobsonpb_options_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*FieldOptions)(nil),
+		Field:         51200,
+		Name:          "protobson.field",
+		Tag:           "bytes,51200,opt,name=field",
+		Filename:      "protobsonpb/options.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// optional protobson.FieldOptions field = 51200;
+	E_Field = &file_protobsonpb_options_proto_extTypes[0]
+)
+
+var File_protobsonpb_options_proto protoreflect.FileDescriptor
+
+const file_protobsonpb_options_proto_rawDesc = "" +
+	"\n" +
+	"\x19protobsonpb/options.proto\x12\tprotobson\x1a google/protobuf/descriptor.proto\"\xb5\x01\n" +
+	"\fFieldOptions\x12%\n" +
+	"\x0ebinary_subtype\x18\x01 \x01(\rR\rbinarySubtype\x12I\n" +
+	"\rstring_format\x18\x02 \x01(\x0e2$.protobson.FieldOptions.StringFormatR\fstringFormat\"3\n" +
+	"\fStringFormat\x12\n" +
+	"\n" +
+	"\x06STRING\x10\x00\x12\r\n" +
+	"\tOBJECT_ID\x10\x01\x12\b\n" +
+	"\x04UUID\x10\x02:N\n" +
+	"\x05field\x12\x1d.google.protobuf.FieldOptions\x18\x80\x90\x03 \x01(\v2\x17.protobson.FieldOptionsR\x05fieldB(Z&go.vallahaye.net/protobson/protobsonpb"
+
+var (
+	file_protobsonpb_options_proto_rawDescOnce sync.Once
+	file_protobsonpb_options_proto_rawDescData []byte
+)
+
+func file_protobsonpb_options_proto_rawDescGZIP() []byte {
+	file_protobsonpb_options_proto_rawDescOnce.Do(func() {
+		file_protobsonpb_options_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_protobsonpb_options_proto_rawDesc), len(file_protobsonpb_options_proto_rawDesc)))
+	})
+	return file_protobsonpb_options_proto_rawDescData
+}
+
+var file_protobsonpb_options_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_protobsonpb_options_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_protobsonpb_options_proto_goTypes = []any{
+	(FieldOptions_StringFormat)(0),    // 0: protobson.FieldOptions.StringFormat
+	(*FieldOptions)(nil),              // 1: protobson.FieldOptions
+	(*descriptorpb.FieldOptions)(nil), // 2: google.protobuf.FieldOptions
+}
+var file_protobsonpb_options_proto_depIdxs = []int32{
+	0, // 0: protobson.FieldOptions.string_format:type_name -> protobson.FieldOptions.StringFormat
+	2, // 1: protobson.field:extendee -> google.protobuf.FieldOptions
+	1, // 2: protobson.field:type_name -> protobson.FieldOptions
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	2, // [2:3] is the sub-list for extension type_name
+	1, // [1:2] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_protobsonpb_options_proto_init() }
+func file_protobsonpb_options_proto_init() {
+	if File_protobsonpb_options_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protobsonpb_options_proto_rawDesc), len(file_protobsonpb_options_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_protobsonpb_options_proto_goTypes,
+		DependencyIndexes: file_protobsonpb_options_proto_depIdxs,
+		EnumInfos:         file_protobsonpb_options_proto_enumTypes,
+		MessageInfos:      file_protobsonpb_options_proto_msgTypes,
+		ExtensionInfos:    file_protobsonpb_options_proto_extTypes,
+	}.Build()
+	File_protobsonpb_options_proto = out.File
+	file_protobsonpb_options_proto_goTypes = nil
+	file_protobsonpb_options_proto_depIdxs = nil
+}
@@ -0,0 +1,139 @@
+package bsonutil
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/x/bsonx/bsoncore"
+)
+
+// The helpers of this file copy raw BSON between readers, writers and bytes. Unlike the v1
+// driver, the v2 driver does not export its Copier: values are copied with the default Codecs of
+// bson.Raw and bson.RawValue instead.
+
+// rawRegistry holds the default Codecs of bson.Raw and bson.RawValue, which do not look up other
+// Codecs.
+var rawRegistry = bson.NewRegistry()
+
+// CopyDocumentToBytes reads a document from vr and returns its bytes.
+func CopyDocumentToBytes(vr bson.ValueReader) ([]byte, error) {
+	var doc bson.Raw
+	if err := decodeRaw(vr, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// CopyDocumentElements writes the elements of the document doc to dw.
+func CopyDocumentElements(dw bson.DocumentWriter, doc []byte) error {
+	elems, err := bson.Raw(doc).Elements()
+	if err != nil {
+		return err
+	}
+	for _, elem := range elems {
+		vw, err := dw.WriteDocumentElement(elem.Key())
+		if err != nil {
+			return err
+		}
+		v := elem.Value()
+		if err := CopyValue(vw, v.Type, v.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CopyValue writes the value of type t and bytes b to vw.
+func CopyValue(vw bson.ValueWriter, t bson.Type, b []byte) error {
+	// Documents are copied as bson.Raw, which unlike bson.RawValue can be written at the top
+	// level.
+	if t == bson.TypeEmbeddedDocument {
+		return encodeRaw(vw, bson.Raw(b))
+	}
+	return encodeRaw(vw, bson.RawValue{Type: t, Value: b})
+}
+
+// CopyValueToBytes reads a value from vr and returns its type and bytes.
+func CopyValueToBytes(vr bson.ValueReader) (bson.Type, []byte, error) {
+	var rv bson.RawValue
+	if err := decodeRaw(vr, &rv); err != nil {
+		return 0, nil, err
+	}
+	return rv.Type, rv.Value, nil
+}
+
+// NewValueReader returns a ValueReader reading the value of type t and bytes b.
+func NewValueReader(t bson.Type, b []byte) (bson.ValueReader, error) {
+	// The v2 driver only reads values from documents: read the single element of one.
+	idx, doc := bsoncore.AppendDocumentStart(nil)
+	doc = bsoncore.AppendHeader(doc, bsoncore.Type(t), "v")
+	doc = append(doc, b...)
+	doc, err := bsoncore.AppendDocumentEnd(doc, idx)
+	if err != nil {
+		return nil, err
+	}
+	dr, err := bson.NewDocumentReader(bytes.NewReader(doc)).ReadDocument()
+	if err != nil {
+		return nil, err
+	}
+	_, vr, err := dr.ReadElement()
+	if errors.Is(err, bson.ErrEOD) {
+		return nil, errors.New("invalid empty value")
+	}
+	return vr, err
+}
+
+// MarshalValue returns the type and bytes of the BSON value of v encoded with reg.
+func MarshalValue(reg *bson.Registry, v interface{}) (bson.Type, []byte, error) {
+	rv, err := EncodeRawValue(reg, func(ec bson.EncodeContext, vw bson.ValueWriter) error {
+		encoder, err := ec.LookupEncoder(reflect.TypeOf(v))
+		if err != nil {
+			return err
+		}
+		return encoder.EncodeValue(ec, vw, reflect.ValueOf(v))
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return rv.Type, rv.Value, nil
+}
+
+// EncodeRawValue returns the BSON value written by encode using reg.
+func EncodeRawValue(reg *bson.Registry, encode func(ec bson.EncodeContext, vw bson.ValueWriter) error) (bson.RawValue, error) {
+	var buf bytes.Buffer
+	dw, err := bson.NewDocumentWriter(&buf).WriteDocument()
+	if err != nil {
+		return bson.RawValue{}, err
+	}
+	if err := WriteElement(dw, "v", func(vw bson.ValueWriter) error {
+		return encode(bson.EncodeContext{Registry: reg}, vw)
+	}); err != nil {
+		return bson.RawValue{}, err
+	}
+	if err := dw.WriteDocumentEnd(); err != nil {
+		return bson.RawValue{}, err
+	}
+	return bson.Raw(buf.Bytes()).Lookup("v"), nil
+}
+
+// encodeRaw writes v, a bson.Raw or bson.RawValue, to vw.
+func encodeRaw(vw bson.ValueWriter, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	encoder, err := rawRegistry.LookupEncoder(rv.Type())
+	if err != nil {
+		return err
+	}
+	return encoder.EncodeValue(bson.EncodeContext{Registry: rawRegistry}, vw, rv)
+}
+
+// decodeRaw reads ptr, a pointer to a bson.Raw or bson.RawValue, from vr.
+func decodeRaw(vr bson.ValueReader, ptr interface{}) error {
+	rv := reflect.ValueOf(ptr).Elem()
+	decoder, err := rawRegistry.LookupDecoder(rv.Type())
+	if err != nil {
+		return err
+	}
+	return decoder.DecodeValue(bson.DecodeContext{Registry: rawRegistry}, vr, rv)
+}
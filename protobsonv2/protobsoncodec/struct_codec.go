@@ -0,0 +1,138 @@
+package protobsoncodec
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// This file holds the parts of the MessageCodec which the v1 driver builds on its StructCodec and
+// StructTagParser, which the v2 driver does not export.
+
+// ValueCodec is implemented by the Codecs of Codecs, which encode and decode values.
+type ValueCodec interface {
+	bson.ValueEncoder
+	bson.ValueDecoder
+}
+
+// MessageCodec is the Codec used for proto.Message values.
+//
+// Fields are encoded in declaration order by looking up the Codec registered for their Go
+// type, so that messages nested in a proto.Message reuse the Codecs registered for them.
+// Unset oneof fields, and unset message fields whose Codec implements NilOmitter, are omitted
+// while other unset fields with presence, as well as empty repeated and map fields, are
+// encoded as null. DecodeWithNulls also returns the keys explicitly set to null in decoded
+// documents. Bytes fields are encoded as binary of BinarySubtype, unless their
+// (protobson.field).binary_subtype option says otherwise, which also applies to
+// google.protobuf.BytesValue fields, and strings decoded into them are taken as is unless
+// DecodeBase64Strings is true. String fields can be encoded as ObjectIDs
+// or UUIDs with StringFormats or their (protobson.field).string_format option. When
+// EncodeOmitDefaultStruct is true, empty message fields tagged omitempty are omitted as well,
+// and when DecodeZeroStruct is true, messages are reset before being decoded into.
+//
+// The values of a field whose full name, or whose message type full name, is a key of Codecs
+// are encoded and decoded with that Codec instead, bypassing the registry and the other
+// options. For instance, a single google.protobuf.Timestamp field can be stored as a string
+// while the others are stored as dates.
+//
+// Dynamic messages (*dynamicpb.Message) are supported as well and encoded to the same
+// documents as their generated counterparts: the values of message types with a generated Go
+// type registered in protoregistry.GlobalTypes, such as google.protobuf.Timestamp, are
+// converted to it so that the Codecs registered for them apply. Dynamic messages can only be
+// decoded into messages created with dynamicpb.NewMessage, as their descriptor must be known.
+//
+// It stores the same documents as the MessageCodec of the v1 driver given the same options.
+type MessageCodec struct {
+	DecodeZeroStruct        bool
+	EncodeOmitDefaultStruct bool
+
+	Int64Format         protobsonoptions.Int64Format
+	UInt64Format        protobsonoptions.UInt64Format
+	NonFiniteFormat     protobsonoptions.NonFiniteFormat
+	RoundFloat32        bool
+	BinarySubtype       byte
+	DecodeBase64Strings bool
+	StringFormats       map[protoreflect.FullName]protobsonoptions.StringFormat
+	Codecs              map[protoreflect.FullName]ValueCodec
+
+	useProtoNames bool
+	cache         sync.Map // map[messageKey]*messageDescription
+}
+
+// NewMessageCodec returns a MessageCodec with options opts. The struct codec options of opts
+// set DecodeZeroStruct and EncodeOmitDefaultStruct, and its Codecs, which are Codecs of the v1
+// driver, are adapted with NewV1Codec.
+func NewMessageCodec(opts ...*protobsonoptions.MessageCodecOptions) *MessageCodec {
+	mergedOpts := protobsonoptions.MergeMessageCodecOptions(opts...)
+	codec := &MessageCodec{
+		Int64Format:         *mergedOpts.Int64Format,
+		UInt64Format:        *mergedOpts.UInt64Format,
+		NonFiniteFormat:     *mergedOpts.NonFiniteFormat,
+		RoundFloat32:        *mergedOpts.RoundFloat32,
+		BinarySubtype:       *mergedOpts.BinarySubtype,
+		DecodeBase64Strings: *mergedOpts.DecodeBase64Strings,
+		StringFormats:       mergedOpts.StringFormats,
+		Codecs:              make(map[protoreflect.FullName]ValueCodec, len(mergedOpts.Codecs)),
+		useProtoNames:       *mergedOpts.UseProtoNames,
+	}
+	if structOpts := mergedOpts.StructCodecOptions; structOpts != nil {
+		if structOpts.DecodeZeroStruct != nil {
+			codec.DecodeZeroStruct = *structOpts.DecodeZeroStruct
+		}
+		if structOpts.EncodeOmitDefaultStruct != nil {
+			codec.EncodeOmitDefaultStruct = *structOpts.EncodeOmitDefaultStruct
+		}
+	}
+	for name, c := range mergedOpts.Codecs {
+		codec.Codecs[name] = NewV1Codec(c)
+	}
+	return codec
+}
+
+func (c *MessageCodec) decodeZeroStruct() bool {
+	return c.DecodeZeroStruct
+}
+
+func (c *MessageCodec) encodeOmitDefaultStruct() bool {
+	return c.EncodeOmitDefaultStruct
+}
+
+// parseStructTags parses the struct tags of sf like the JSONPBFallbackStructTagParser and
+// ProtoNamesFallbackStructTagParser of the v1 driver do: bson tags are parsed like the default
+// struct tags of the driver, and protobuf tags give the json or name property as the key.
+func (c *MessageCodec) parseStructTags(sf reflect.StructField) (structTags, error) {
+	if tag, ok := sf.Tag.Lookup("bson"); ok {
+		return parseBSONTag(strings.ToLower(sf.Name), tag), nil
+	}
+	tag, ok := sf.Tag.Lookup("protobuf")
+	if !ok {
+		return structTags{Name: strings.ToLower(sf.Name)}, nil
+	}
+	props := structTagProps(tag)
+	if jsonName, ok := props["json"]; ok && !c.useProtoNames {
+		return structTags{Name: jsonName}, nil
+	}
+	return structTags{Name: props["name"]}, nil
+}
+
+// parseBSONTag parses the bson tag of a field of default key key.
+func parseBSONTag(key, tag string) structTags {
+	st := structTags{Name: key}
+	if tag == "-" {
+		st.Skip = true
+		return st
+	}
+	for i, s := range strings.Split(tag, ",") {
+		if i == 0 && s != "" {
+			st.Name = s
+		}
+		if s == "omitempty" {
+			st.OmitEmpty = true
+		}
+	}
+	return st
+}
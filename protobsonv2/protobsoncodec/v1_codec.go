@@ -0,0 +1,49 @@
+package protobsoncodec
+
+import (
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.vallahaye.net/protobson"
+	bsonutilv1 "go.vallahaye.net/protobson/internal/bsonutil"
+	"go.vallahaye.net/protobson/protobsonv2/internal/bsonutil"
+)
+
+// V1Codec adapts Codec, a Codec of the v1 driver, to the v2 driver. Values are copied as raw BSON
+// between the drivers, and the Codecs looked up by Codec are those of Registry.
+type V1Codec struct {
+	Codec    bsoncodec.ValueCodec
+	Registry *bsoncodec.Registry
+}
+
+// EncodeValue is the ValueEncoderFunc of the values encoded by Codec.
+func (c *V1Codec) EncodeValue(_ bson.EncodeContext, vw bson.ValueWriter, v reflect.Value) error {
+	rv, err := bsonutilv1.EncodeRawValue(c.Registry, func(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter) error {
+		return c.Codec.EncodeValue(ec, vw, v)
+	})
+	if err != nil {
+		return err
+	}
+	return bsonutil.CopyValue(vw, bson.Type(rv.Type), rv.Value)
+}
+
+// DecodeValue is the ValueDecoderFunc of the values decoded by Codec.
+func (c *V1Codec) DecodeValue(_ bson.DecodeContext, vr bson.ValueReader, v reflect.Value) error {
+	t, b, err := bsonutil.CopyValueToBytes(vr)
+	if err != nil {
+		return err
+	}
+	vrv1, err := bsonutilv1.NewValueReader(bsontype.Type(t), b)
+	if err != nil {
+		return err
+	}
+	return c.Codec.DecodeValue(bsoncodec.DecodeContext{Registry: c.Registry}, vrv1, v)
+}
+
+// NewV1Codec returns a V1Codec adapting c, which looks up the Codecs of protobson.DefaultRegistry.
+func NewV1Codec(c bsoncodec.ValueCodec) *V1Codec {
+	return &V1Codec{Codec: c, Registry: protobson.DefaultRegistry}
+}
@@ -0,0 +1,77 @@
+// Package protobsonv2 provides the protobson codecs to the v2 driver
+// (go.mongodb.org/mongo-driver/v2).
+//
+// Its protobsoncodec packages are the v2 versions of those of protobson, mostly generated from
+// them by v2gen, so that both drivers store the exact same documents given the same options.
+package protobsonv2
+
+//go:generate go run ../internal/cmd/v2gen
+
+import (
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.vallahaye.net/protobson"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"go.vallahaye.net/protobson/protobsonv2/protobsoncodec"
+	googleapiscodec "go.vallahaye.net/protobson/protobsonv2/protobsoncodec/googleapis"
+	knowncodec "go.vallahaye.net/protobson/protobsonv2/protobsoncodec/known"
+)
+
+// DefaultRegistry is the default v2 bson.Registry with all default protobson codecs
+// registered, storing the same documents as protobson.DefaultRegistry.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry returns a v2 bson.Registry with the default encoders and decoders of the bson
+// package and all default protobson codecs, created with options opts. It stores the same
+// documents as the v1 registry built by protobson.NewRegistryBuilder with the same options.
+func NewRegistry(opts ...*protobsonoptions.RegistryOptions) *bson.Registry {
+	mergedOpts := protobsonoptions.MergeRegistryOptions(opts...)
+	messageCodec := protobsoncodec.NewMessageCodec(mergedOpts.Message)
+	// The v1 Codecs of the options look up the Codecs of a v1 registry built with the same
+	// options.
+	if len(mergedOpts.Message.Codecs) > 0 {
+		rv1 := protobson.NewRegistryBuilder(opts...).Build()
+		for _, c := range messageCodec.Codecs {
+			c.(*protobsoncodec.V1Codec).Registry = rv1
+		}
+	}
+	reg := bson.NewRegistry()
+	registerCodec(reg, knowncodec.TypeBoolValue, knowncodec.NewBoolValueCodec(mergedOpts.BoolValue))
+	registerCodec(reg, knowncodec.TypeBytesValue, knowncodec.NewBytesValueCodec(mergedOpts.BytesValue))
+	registerCodec(reg, knowncodec.TypeDoubleValue, knowncodec.NewDoubleValueCodec(mergedOpts.DoubleValue))
+	registerCodec(reg, knowncodec.TypeDuration, knowncodec.NewDurationCodec(mergedOpts.Duration))
+	registerCodec(reg, knowncodec.TypeEmpty, knowncodec.NewEmptyCodec())
+	registerCodec(reg, knowncodec.TypeFloatValue, knowncodec.NewFloatValueCodec(mergedOpts.FloatValue))
+	registerCodec(reg, knowncodec.TypeInt32Value, knowncodec.NewInt32ValueCodec(mergedOpts.Int32Value))
+	registerCodec(reg, knowncodec.TypeInt64Value, knowncodec.NewInt64ValueCodec(mergedOpts.Int64Value))
+	reg.RegisterInterfaceEncoder(protobsoncodec.TypeMessage, messageCodec)
+	reg.RegisterInterfaceDecoder(protobsoncodec.TypeMessage, messageCodec)
+	registerCodec(reg, knowncodec.TypeNullValue, knowncodec.NewNullValueCodec())
+	registerCodec(reg, knowncodec.TypeStringValue, knowncodec.NewStringValueCodec(mergedOpts.StringValue))
+	registerCodec(reg, knowncodec.TypeTimestamp, knowncodec.NewTimestampCodec(mergedOpts.Timestamp))
+	registerCodec(reg, knowncodec.TypeUInt32Value, knowncodec.NewUInt32ValueCodec(mergedOpts.UInt32Value))
+	registerCodec(reg, knowncodec.TypeUInt64Value, knowncodec.NewUInt64ValueCodec(mergedOpts.UInt64Value))
+	registerCodec(reg, googleapiscodec.TypeColor, googleapiscodec.NewColorCodec())
+	registerCodec(reg, googleapiscodec.TypeDate, googleapiscodec.NewDateCodec())
+	registerCodec(reg, googleapiscodec.TypeDateTime, googleapiscodec.NewDateTimeCodec(mergedOpts.DateTime))
+	registerCodec(reg, googleapiscodec.TypeDayOfWeek, googleapiscodec.NewDayOfWeekCodec())
+	registerCodec(reg, googleapiscodec.TypeDecimal, googleapiscodec.NewDecimalCodec())
+	registerCodec(reg, googleapiscodec.TypeFraction, googleapiscodec.NewFractionCodec())
+	registerCodec(reg, googleapiscodec.TypeInterval, googleapiscodec.NewIntervalCodec())
+	registerCodec(reg, googleapiscodec.TypeLatLng, googleapiscodec.NewLatLngCodec())
+	registerCodec(reg, googleapiscodec.TypeMoney, googleapiscodec.NewMoneyCodec())
+	registerCodec(reg, googleapiscodec.TypeMonth, googleapiscodec.NewMonthCodec())
+	registerCodec(reg, googleapiscodec.TypePhoneNumber, googleapiscodec.NewPhoneNumberCodec())
+	registerCodec(reg, googleapiscodec.TypePostalAddress, googleapiscodec.NewPostalAddressCodec())
+	registerCodec(reg, googleapiscodec.TypeStatus, googleapiscodec.NewStatusCodec())
+	registerCodec(reg, googleapiscodec.TypeTimeOfDay, googleapiscodec.NewTimeOfDayCodec())
+	return reg
+}
+
+// registerCodec registers c as both the encoder and the decoder of type t, as the RegisterCodec
+// method of the v1 registry builder does.
+func registerCodec(reg *bson.Registry, t reflect.Type, c protobsoncodec.ValueCodec) {
+	reg.RegisterTypeEncoder(t, c)
+	reg.RegisterTypeDecoder(t, c)
+}
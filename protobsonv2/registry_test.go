@@ -0,0 +1,350 @@
+package protobsonv2
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	bsonv1 "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsonoptions"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.vallahaye.net/protobson"
+	"go.vallahaye.net/protobson/internal/testpb"
+	"go.vallahaye.net/protobson/protobsoncodec"
+	knowncodec "go.vallahaye.net/protobson/protobsoncodec/known"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	v2codec "go.vallahaye.net/protobson/protobsonv2/protobsoncodec"
+	v2knowncodec "go.vallahaye.net/protobson/protobsonv2/protobsoncodec/known"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/genproto/googleapis/type/color"
+	"google.golang.org/genproto/googleapis/type/date"
+	"google.golang.org/genproto/googleapis/type/datetime"
+	"google.golang.org/genproto/googleapis/type/dayofweek"
+	"google.golang.org/genproto/googleapis/type/decimal"
+	"google.golang.org/genproto/googleapis/type/fraction"
+	"google.golang.org/genproto/googleapis/type/interval"
+	"google.golang.org/genproto/googleapis/type/latlng"
+	"google.golang.org/genproto/googleapis/type/money"
+	"google.golang.org/genproto/googleapis/type/month"
+	"google.golang.org/genproto/googleapis/type/phone_number"
+	"google.golang.org/genproto/googleapis/type/postaladdress"
+	"google.golang.org/genproto/googleapis/type/timeofday"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"gotest.tools/v3/assert"
+)
+
+type envelope struct {
+	ID  int32         `bson:"_id"`
+	Msg proto.Message `bson:"msg"`
+}
+
+func marshal(t *testing.T, r *bson.Registry, v interface{}) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	enc := bson.NewEncoder(bson.NewDocumentWriter(&buf))
+	enc.SetRegistry(r)
+	assert.NilError(t, enc.Encode(v))
+	return buf.Bytes()
+}
+
+func unmarshal(t *testing.T, r *bson.Registry, b []byte, v interface{}) {
+	t.Helper()
+	dec := bson.NewDecoder(bson.NewDocumentReader(bytes.NewReader(b)))
+	dec.SetRegistry(r)
+	assert.NilError(t, dec.Decode(v))
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	for _, params := range []struct {
+		name string
+		msg  proto.Message
+	}{
+		{
+			"Empty",
+			&testpb.KitchenSink{},
+		},
+		{
+			"KitchenSink",
+			&testpb.KitchenSink{
+				Int64Field:           -2,
+				Uint64Field:          4,
+				FloatField:           1.5,
+				StringField:          "foo",
+				BytesField:           []byte("bar"),
+				EnumField:            testpb.Status_STATUS_ACTIVE,
+				MessageField:         &testpb.Record{Name: "baz", CreatedAt: timestamppb.New(time.Date(2022, 5, 30, 11, 43, 26, 0, time.UTC))},
+				OptionalField:        proto.String(""),
+				RepeatedField:        []uint64{1, 2, 3},
+				RepeatedMessageField: []*testpb.Record{{Name: "qux"}, {}},
+				MapField:             map[string]int32{"a": 1, "b": 2},
+				IntKeyMapField:       map[int64]*testpb.Record{-1: {Name: "quux", Version: wrapperspb.Int64(7)}},
+				Choice:               &testpb.KitchenSink_ChoiceString{ChoiceString: "corge"},
+				Uint64ValueField:     wrapperspb.UInt64(42),
+			},
+		},
+		{
+			"Timestamp",
+			timestamppb.New(time.Date(2022, 5, 30, 11, 43, 26, 0, time.UTC)),
+		},
+		{
+			"Duration",
+			durationpb.New(90 * time.Second),
+		},
+	} {
+		t.Run(params.name, func(t *testing.T) {
+			want, err := bsonv1.MarshalWithRegistry(protobson.DefaultRegistry, bsonv1.D{{Key: "_id", Value: int32(1)}, {Key: "msg", Value: params.msg}})
+			assert.NilError(t, err)
+			b := marshal(t, DefaultRegistry, envelope{ID: 1, Msg: params.msg})
+			assert.DeepEqual(t, want, b)
+			got := params.msg.ProtoReflect().Type().New().Interface()
+			err = bson.Raw(b).Lookup("msg").UnmarshalWithRegistry(DefaultRegistry, got)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, params.msg, got, protocmp.Transform())
+		})
+	}
+	t.Run("Document", func(t *testing.T) {
+		rec := &testpb.Record{Name: "foo", Children: []*testpb.Record{{Name: "bar"}}}
+		want, err := bsonv1.MarshalWithRegistry(protobson.DefaultRegistry, rec)
+		assert.NilError(t, err)
+		b := marshal(t, DefaultRegistry, rec)
+		assert.DeepEqual(t, want, b)
+		var got testpb.Record
+		unmarshal(t, DefaultRegistry, b, &got)
+		assert.DeepEqual(t, rec, &got, protocmp.Transform())
+	})
+	t.Run("Nil", func(t *testing.T) {
+		var v struct {
+			Rec *testpb.Record `bson:"rec"`
+		}
+		b := marshal(t, DefaultRegistry, v)
+		assert.Equal(t, bson.TypeNull, bson.Raw(b).Lookup("rec").Type)
+		v.Rec = &testpb.Record{Name: "foo"}
+		unmarshal(t, DefaultRegistry, b, &v)
+		assert.Assert(t, v.Rec == nil)
+	})
+}
+
+func TestNewRegistry(t *testing.T) {
+	details, err := anypb.New(wrapperspb.String("hint"))
+	assert.NilError(t, err)
+	opts := protobsonoptions.Registry().
+		SetMessage(protobsonoptions.MessageCodec().SetUseProtoNames(true)).
+		SetTimestamp(protobsonoptions.TimestampCodec().SetFormat(protobsonoptions.TimestampFormatString))
+	r := NewRegistry(opts)
+	rv1 := protobson.NewRegistryBuilder(opts).Build()
+	for _, params := range []struct {
+		name string
+		msg  proto.Message
+	}{
+		{
+			"Record",
+			&testpb.Record{Name: "foo", Count: 42, CreatedAt: timestamppb.New(time.Date(2022, 5, 30, 11, 43, 26, 0, time.UTC))},
+		},
+		{
+			"LatLng",
+			&latlng.LatLng{Latitude: 48.8584, Longitude: 2.2945},
+		},
+		{
+			"Status",
+			&status.Status{Code: 5, Message: "not found", Details: []*anypb.Any{details}},
+		},
+	} {
+		t.Run(params.name, func(t *testing.T) {
+			want, err := bsonv1.MarshalWithRegistry(rv1, params.msg)
+			assert.NilError(t, err)
+			b := marshal(t, r, params.msg)
+			assert.DeepEqual(t, want, b)
+			got := params.msg.ProtoReflect().Type().New().Interface()
+			unmarshal(t, r, b, got)
+			assert.DeepEqual(t, params.msg, got, protocmp.Transform())
+		})
+	}
+	b := marshal(t, r, &testpb.Record{CreatedAt: timestamppb.New(time.Date(2022, 5, 30, 11, 43, 26, 0, time.UTC))})
+	assert.Equal(t, "2022-05-30T11:43:26Z", bson.Raw(b).Lookup("created_at").StringValue())
+	b = marshal(t, r, &latlng.LatLng{Latitude: 48.8584, Longitude: 2.2945})
+	assert.Equal(t, "Point", bson.Raw(b).Lookup("type").StringValue())
+}
+
+func TestNewRegistryFromV1(t *testing.T) {
+	c := protobsoncodec.NewMessageCodec(protobsonoptions.MessageCodec().SetUseProtoNames(true))
+	r := bsonv1.NewRegistryBuilder().
+		RegisterHookEncoder(protobsoncodec.TypeMessage, c).
+		RegisterHookDecoder(protobsoncodec.TypeMessage, c).
+		Build()
+	rec := &testpb.Record{Name: "foo", Count: 42}
+	want, err := bsonv1.MarshalWithRegistry(r, rec)
+	assert.NilError(t, err)
+	b := marshal(t, NewRegistryFromV1(r), rec)
+	assert.DeepEqual(t, want, b)
+	assert.Equal(t, int64(42), bson.Raw(b).Lookup("count").Int64())
+	_, err = bson.Raw(b).LookupErr("created_at")
+	assert.NilError(t, err)
+}
+
+func TestRegistryEquivalence(t *testing.T) {
+	created := timestamppb.New(time.Date(2022, 5, 30, 11, 43, 26, 500000000, time.UTC))
+	details, err := anypb.New(&testpb.Record{Name: "hint", CreatedAt: created})
+	assert.NilError(t, err)
+	dynamic := dynamicpb.NewMessage((&testpb.Record{}).ProtoReflect().Descriptor())
+	proto.Merge(dynamic, &testpb.Record{Name: "foo", CreatedAt: created, Version: wrapperspb.Int64(7)})
+	doc, err := structpb.NewStruct(map[string]interface{}{"a": 1.5, "b": []interface{}{"c", nil, true}})
+	assert.NilError(t, err)
+	values := []struct {
+		name string
+		v    interface{}
+	}{
+		{"Empty", &testpb.KitchenSink{}},
+		{
+			"KitchenSink",
+			&testpb.KitchenSink{
+				Int32Field:           -1,
+				Int64Field:           -2,
+				Uint32Field:          3,
+				Uint64Field:          math.MaxInt64,
+				Sint64Field:          -6,
+				Fixed64Field:         8,
+				Sfixed64Field:        -10,
+				FloatField:           0.1,
+				DoubleField:          math.Inf(1),
+				BoolField:            true,
+				StringField:          "foo",
+				BytesField:           []byte("bar"),
+				EnumField:            testpb.Status_STATUS_ARCHIVED,
+				MessageField:         &testpb.Record{Name: "baz", CreatedAt: created, Checksum: wrapperspb.Bytes([]byte{1})},
+				OptionalField:        proto.String(""),
+				RepeatedField:        []uint64{1, 2, 3},
+				RepeatedMessageField: []*testpb.Record{{Name: "qux"}, {}},
+				MapField:             map[string]int32{"a": 1, "b": 2},
+				IntKeyMapField:       map[int64]*testpb.Record{-1: {Name: "quux", Version: wrapperspb.Int64(7)}},
+				Choice:               &testpb.KitchenSink_ChoiceMessage{ChoiceMessage: &testpb.Record{}},
+				Uint64ValueField:     wrapperspb.UInt64(42),
+			},
+		},
+		{"Record", &testpb.Record{Name: "foo", Parent: &testpb.Record{}, Children: []*testpb.Record{{Name: "bar", Version: wrapperspb.Int64(-1)}}}},
+		{"Blobs", &testpb.Blobs{Data: []byte("a"), Uuid: make([]byte, 16), Md5: wrapperspb.Bytes([]byte("b")), Encrypted: [][]byte{[]byte("c")}}},
+		{"Identifiers", &testpb.Identifiers{Id: "62949d0e8d1b3c6a1f5e4d3c", RequestId: "0f8fad5b-d9cb-469f-a165-70867728950e", ParentIds: []string{"62949d0e8d1b3c6a1f5e4d3d"}, LegacyId: "62949d0e8d1b3c6a1f5e4d3e"}},
+		{"Dynamic", dynamic},
+		{"Timestamp", created},
+		{"Duration", durationpb.New(-90*time.Second - time.Millisecond)},
+		{"BoolValue", wrapperspb.Bool(true)},
+		{"BytesValue", wrapperspb.Bytes([]byte("foo"))},
+		{"DoubleValue", wrapperspb.Double(math.Inf(-1))},
+		{"FloatValue", wrapperspb.Float(0.1)},
+		{"Int32Value", wrapperspb.Int32(-1)},
+		{"Int64Value", wrapperspb.Int64(-2)},
+		{"StringValue", wrapperspb.String("foo")},
+		{"UInt32Value", wrapperspb.UInt32(3)},
+		{"UInt64Value", wrapperspb.UInt64(math.MaxInt64)},
+		{"Empty", &emptypb.Empty{}},
+		{"Struct", doc},
+		{"NullValue", structpb.NullValue_NULL_VALUE},
+		{"Color", &color.Color{Red: 1, Green: 0.2, Blue: 0}},
+		{"Date", &date.Date{Year: 2022, Month: 5, Day: 30}},
+		{"DateTime", &datetime.DateTime{Year: 2022, Month: 5, Day: 30, Hours: 11, TimeOffset: &datetime.DateTime_UtcOffset{UtcOffset: durationpb.New(0)}}},
+		{"DayOfWeek", dayofweek.DayOfWeek_MONDAY},
+		{"Decimal", &decimal.Decimal{Value: "12.34"}},
+		{"Fraction", &fraction.Fraction{Numerator: 1, Denominator: 3}},
+		{"Interval", &interval.Interval{StartTime: created, EndTime: timestamppb.New(created.AsTime().Add(time.Hour))}},
+		{"LatLng", &latlng.LatLng{Latitude: 48.8584, Longitude: 2.2945}},
+		{"Money", &money.Money{CurrencyCode: "EUR", Units: 12, Nanos: 340000000}},
+		{"Month", month.Month_MAY},
+		{"PhoneNumber", &phone_number.PhoneNumber{Kind: &phone_number.PhoneNumber_E164Number{E164Number: "+33123456789"}, Extension: "12"}},
+		{"PostalAddress", &postaladdress.PostalAddress{RegionCode: "FR", PostalCode: "75007", AddressLines: []string{"5 Av. Anatole France"}}},
+		{"Status", &status.Status{Code: 5, Message: "not found", Details: []*anypb.Any{details}}},
+		{"TimeOfDay", &timeofday.TimeOfDay{Hours: 11, Minutes: 43, Seconds: 26, Nanos: 500000000}},
+	}
+	for _, opts := range []struct {
+		name string
+		opts *protobsonoptions.RegistryOptions
+	}{
+		{"Default", protobsonoptions.Registry()},
+		{
+			"Formats",
+			protobsonoptions.Registry().
+				SetMessage(protobsonoptions.MessageCodec().
+					SetUseProtoNames(true).
+					SetInt64Format(protobsonoptions.Int64FormatString).
+					SetUInt64Format(protobsonoptions.UInt64FormatDecimal128).
+					SetNonFiniteFormat(protobsonoptions.NonFiniteFormatString).
+					SetRoundFloat32(true).
+					SetBinarySubtype(bson.TypeBinaryUserDefined).
+					SetStringFormat("protobson.test.Identifiers.legacy_id", protobsonoptions.StringFormatObjectID)).
+				SetTimestamp(protobsonoptions.TimestampCodec().SetFormat(protobsonoptions.TimestampFormatString)).
+				SetDuration(protobsonoptions.DurationCodec().SetFormat(protobsonoptions.DurationFormatSeconds)).
+				SetInt64Value(protobsonoptions.Int64ValueCodec().SetFormat(protobsonoptions.Int64FormatDecimal128)),
+		},
+		{
+			"Codecs",
+			protobsonoptions.Registry().
+				SetMessage(protobsonoptions.MessageCodec().
+					SetCodec("protobson.test.Record.created_at", knowncodec.NewTimestampCodec(protobsonoptions.TimestampCodec().SetFormat(protobsonoptions.TimestampFormatString))).
+					SetCodec("google.protobuf.Int64Value", knowncodec.NewInt64ValueCodec(protobsonoptions.Int64ValueCodec().SetFormat(protobsonoptions.Int64FormatString)))),
+		},
+		{
+			"StructCodec",
+			protobsonoptions.Registry().
+				SetMessage(&protobsonoptions.MessageCodecOptions{
+					StructCodecOptions: bsonoptions.StructCodec().SetDecodeZeroStruct(true).SetEncodeOmitDefaultStruct(true),
+				}),
+		},
+	} {
+		rv1 := protobson.NewRegistryBuilder(opts.opts).Build()
+		r := NewRegistry(opts.opts)
+		for _, params := range values {
+			t.Run(opts.name+"/"+params.name, func(t *testing.T) {
+				v := struct {
+					ID  int32       `bson:"_id"`
+					Msg interface{} `bson:"msg"`
+				}{ID: 1, Msg: params.v}
+				want, err := bsonv1.MarshalWithRegistry(rv1, v)
+				assert.NilError(t, err)
+				b := marshal(t, r, v)
+				assert.DeepEqual(t, want, b)
+				got := reflect.New(reflect.TypeOf(params.v))
+				if m, ok := params.v.(proto.Message); ok {
+					got.Elem().Set(reflect.ValueOf(m.ProtoReflect().Type().New().Interface()))
+				}
+				assert.NilError(t, bson.Raw(b).Lookup("msg").UnmarshalWithRegistry(r, got.Interface()))
+				assert.DeepEqual(t, params.v, got.Elem().Interface(), protocmp.Transform())
+			})
+		}
+		t.Run(opts.name+"/Decode", func(t *testing.T) {
+			b, err := bsonv1.MarshalWithRegistry(rv1, &testpb.Record{Name: "foo"})
+			assert.NilError(t, err)
+			want := &testpb.Record{Count: 42}
+			assert.NilError(t, bsonv1.UnmarshalWithRegistry(rv1, b, want))
+			got := &testpb.Record{Count: 42}
+			unmarshal(t, r, b, got)
+			assert.DeepEqual(t, want, got, protocmp.Transform())
+		})
+	}
+	t.Run("NativeCodecs", func(t *testing.T) {
+		rec := &testpb.Record{Name: "foo", CreatedAt: created}
+		rv1 := protobson.NewRegistryBuilder(protobsonoptions.Registry().SetMessage(protobsonoptions.MessageCodec().
+			SetCodec("protobson.test.Record.created_at", knowncodec.NewTimestampCodec(protobsonoptions.TimestampCodec().SetFormat(protobsonoptions.TimestampFormatString))))).
+			Build()
+		want, err := bsonv1.MarshalWithRegistry(rv1, rec)
+		assert.NilError(t, err)
+		c := v2codec.NewMessageCodec()
+		c.Codecs["protobson.test.Record.created_at"] = v2knowncodec.NewTimestampCodec(protobsonoptions.TimestampCodec().SetFormat(protobsonoptions.TimestampFormatString))
+		r := NewRegistry()
+		r.RegisterInterfaceEncoder(v2codec.TypeMessage, c)
+		r.RegisterInterfaceDecoder(v2codec.TypeMessage, c)
+		b := marshal(t, r, rec)
+		assert.DeepEqual(t, want, b)
+		assert.Equal(t, bson.TypeString, bson.Raw(b).Lookup("createdAt").Type)
+		var got testpb.Record
+		unmarshal(t, r, b, &got)
+		assert.DeepEqual(t, rec, &got, protocmp.Transform())
+	})
+}
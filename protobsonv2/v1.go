@@ -0,0 +1,124 @@
+package protobsonv2
+
+import (
+	"reflect"
+
+	bsonv1 "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.vallahaye.net/protobson/protobsonv2/protobsoncodec"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	tRaw      = reflect.TypeOf(bson.Raw(nil))
+	tRawValue = reflect.TypeOf(bson.RawValue{})
+)
+
+// NewRegistryFromV1 returns a v2 bson.Registry with the default encoders and decoders of the
+// bson package, encoding and decoding messages with the v1 registry r. It allows reusing a v1
+// registry holding custom codecs, such as one built by protobson.NewRegistryBuilder, while
+// migrating to the v2 driver.
+//
+// Messages are marshaled with r and copied as raw BSON to the v2 driver: the codecs registered
+// in the returned registry only apply to the values surrounding messages. Prefer NewRegistry,
+// which encodes messages natively.
+func NewRegistryFromV1(r *bsoncodec.Registry) *bson.Registry {
+	reg := bson.NewRegistry()
+	c := &v1MessageCodec{registry: r}
+	reg.RegisterInterfaceEncoder(protobsoncodec.TypeMessage, c)
+	reg.RegisterInterfaceDecoder(protobsoncodec.TypeMessage, c)
+	return reg
+}
+
+// v1MessageCodec is the v2 Codec of proto.Message values encoding and decoding them with a v1
+// registry.
+type v1MessageCodec struct {
+	registry *bsoncodec.Registry
+}
+
+// EncodeValue is the ValueEncoderFunc for proto.Message.
+func (c *v1MessageCodec) EncodeValue(ec bson.EncodeContext, vw bson.ValueWriter, v reflect.Value) error {
+	if !v.IsValid() || (!v.Type().Implements(protobsoncodec.TypeMessage) && !reflect.PtrTo(v.Type()).Implements(protobsoncodec.TypeMessage)) {
+		return bson.ValueEncoderError{
+			Name:     "v1MessageCodec.EncodeValue",
+			Types:    []reflect.Type{protobsoncodec.TypeMessage},
+			Received: v,
+		}
+	}
+	if v.Kind() == reflect.Struct {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		v = ptr
+	}
+	t, data, err := bsonv1.MarshalValueWithRegistry(c.registry, v.Interface())
+	if err != nil {
+		return err
+	}
+	// Documents are copied as bson.Raw, which unlike bson.RawValue can be written at the top
+	// level.
+	if t == bsontype.EmbeddedDocument {
+		encoder, err := ec.LookupEncoder(tRaw)
+		if err != nil {
+			return err
+		}
+		return encoder.EncodeValue(ec, vw, reflect.ValueOf(bson.Raw(data)))
+	}
+	encoder, err := ec.LookupEncoder(tRawValue)
+	if err != nil {
+		return err
+	}
+	return encoder.EncodeValue(ec, vw, reflect.ValueOf(bson.RawValue{Type: bson.Type(t), Value: data}))
+}
+
+// DecodeValue is the ValueDecoderFunc for proto.Message.
+func (c *v1MessageCodec) DecodeValue(dc bson.DecodeContext, vr bson.ValueReader, v reflect.Value) error {
+	if !v.CanSet() || (!v.Type().Implements(protobsoncodec.TypeMessage) && !reflect.PtrTo(v.Type()).Implements(protobsoncodec.TypeMessage)) {
+		return bson.ValueDecoderError{
+			Name:     "v1MessageCodec.DecodeValue",
+			Types:    []reflect.Type{protobsoncodec.TypeMessage},
+			Received: v,
+		}
+	}
+	raw := bson.RawValue{Type: vr.Type()}
+	// The v2 readers report no type at the top level, where only documents can be read.
+	if raw.Type == 0 || raw.Type == bson.TypeEmbeddedDocument {
+		raw.Type = bson.TypeEmbeddedDocument
+		decoder, err := dc.LookupDecoder(tRaw)
+		if err != nil {
+			return err
+		}
+		var doc bson.Raw
+		if err := decoder.DecodeValue(dc, vr, reflect.ValueOf(&doc).Elem()); err != nil {
+			return err
+		}
+		raw.Value = doc
+	} else {
+		decoder, err := dc.LookupDecoder(tRawValue)
+		if err != nil {
+			return err
+		}
+		if err := decoder.DecodeValue(dc, vr, reflect.ValueOf(&raw).Elem()); err != nil {
+			return err
+		}
+	}
+	rawv1 := bsonv1.RawValue{Type: bsontype.Type(raw.Type), Value: raw.Value}
+	if v.Kind() != reflect.Struct {
+		return rawv1.UnmarshalWithRegistry(c.registry, v.Addr().Interface())
+	}
+	// Decode into a pointer so that the codecs registered for the pointer type apply.
+	ptr := reflect.New(v.Addr().Type())
+	ptr.Elem().Set(v.Addr())
+	if err := rawv1.UnmarshalWithRegistry(c.registry, ptr.Interface()); err != nil {
+		return err
+	}
+	if ptr.Elem().IsNil() {
+		v.Set(reflect.Zero(v.Type()))
+	} else if ptr.Elem().Pointer() != v.Addr().Pointer() {
+		m := v.Addr().Interface().(proto.Message)
+		proto.Reset(m)
+		proto.Merge(m, ptr.Elem().Interface().(proto.Message))
+	}
+	return nil
+}
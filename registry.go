@@ -2,42 +2,51 @@ package protobson
 
 import (
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.vallahaye.net/protobson/protobsoncodec"
 	googleapiscodec "go.vallahaye.net/protobson/protobsoncodec/googleapis"
 	knowncodec "go.vallahaye.net/protobson/protobsoncodec/known"
-)
-
-var (
-	defaultBoolValueCodec    = knowncodec.NewBoolValueCodec()
-	defaultBytesValueCodec   = knowncodec.NewBytesValueCodec()
-	defaultDoubleValueCodec  = knowncodec.NewDoubleValueCodec()
-	defaultDurationCodec     = knowncodec.NewDurationCodec()
-	defaultFloatValueCodec   = knowncodec.NewFloatValueCodec()
-	defaultInt32ValueCodec   = knowncodec.NewInt32ValueCodec()
-	defaultInt64ValueCodec   = knowncodec.NewInt64ValueCodec()
-	defaultMessageCodec      = protobsoncodec.NewMessageCodec()
-	defaultStringValueCodec  = knowncodec.NewStringValueCodec()
-	defaultTimestampCodec    = knowncodec.NewTimestampCodec()
-	defaultUInt32ValueCodec  = knowncodec.NewUInt32ValueCodec()
-	defaultUInt64ValueCodec  = knowncodec.NewUInt64ValueCodec()
-	defaultGAPIDateTimeCodec = googleapiscodec.NewDateTimeCodec()
+	"go.vallahaye.net/protobson/protobsonoptions"
 )
 
 // DefaultRegistry is the default bsoncodec.Registry with all default protobson
 // codecs registered.
-var DefaultRegistry = bson.NewRegistryBuilder().
-	RegisterCodec(knowncodec.TypeBoolValue, defaultBoolValueCodec).
-	RegisterCodec(knowncodec.TypeBytesValue, defaultBytesValueCodec).
-	RegisterCodec(knowncodec.TypeDoubleValue, defaultDoubleValueCodec).
-	RegisterCodec(knowncodec.TypeDuration, defaultDurationCodec).
-	RegisterCodec(knowncodec.TypeFloatValue, defaultFloatValueCodec).
-	RegisterCodec(knowncodec.TypeInt32Value, defaultInt32ValueCodec).
-	RegisterCodec(knowncodec.TypeInt64Value, defaultInt64ValueCodec).
-	RegisterHookEncoder(protobsoncodec.TypeMessage, defaultMessageCodec).
-	RegisterHookDecoder(protobsoncodec.TypeMessage, defaultMessageCodec).
-	RegisterCodec(knowncodec.TypeStringValue, defaultStringValueCodec).
-	RegisterCodec(knowncodec.TypeTimestamp, defaultTimestampCodec).
-	RegisterCodec(knowncodec.TypeUInt32Value, defaultUInt32ValueCodec).
-	RegisterCodec(knowncodec.TypeUInt64Value, defaultUInt64ValueCodec).
-	RegisterCodec(googleapiscodec.TypeDateTime, defaultGAPIDateTimeCodec).
-	Build()
+var DefaultRegistry = NewRegistryBuilder().Build()
+
+// NewRegistryBuilder creates a new RegistryBuilder configured with the default
+// encoders and decoders of the bson package and all default protobson codecs,
+// created with options opts, to which other codecs can be added.
+func NewRegistryBuilder(opts ...*protobsonoptions.RegistryOptions) *bsoncodec.RegistryBuilder {
+	mergedOpts := protobsonoptions.MergeRegistryOptions(opts...)
+	messageCodec := protobsoncodec.NewMessageCodec(mergedOpts.Message)
+	return bson.NewRegistryBuilder().
+		RegisterCodec(knowncodec.TypeBoolValue, knowncodec.NewBoolValueCodec(mergedOpts.BoolValue)).
+		RegisterCodec(knowncodec.TypeBytesValue, knowncodec.NewBytesValueCodec(mergedOpts.BytesValue)).
+		RegisterCodec(knowncodec.TypeDoubleValue, knowncodec.NewDoubleValueCodec(mergedOpts.DoubleValue)).
+		RegisterCodec(knowncodec.TypeDuration, knowncodec.NewDurationCodec(mergedOpts.Duration)).
+		RegisterCodec(knowncodec.TypeEmpty, knowncodec.NewEmptyCodec()).
+		RegisterCodec(knowncodec.TypeFloatValue, knowncodec.NewFloatValueCodec(mergedOpts.FloatValue)).
+		RegisterCodec(knowncodec.TypeInt32Value, knowncodec.NewInt32ValueCodec(mergedOpts.Int32Value)).
+		RegisterCodec(knowncodec.TypeInt64Value, knowncodec.NewInt64ValueCodec(mergedOpts.Int64Value)).
+		RegisterHookEncoder(protobsoncodec.TypeMessage, messageCodec).
+		RegisterHookDecoder(protobsoncodec.TypeMessage, messageCodec).
+		RegisterCodec(knowncodec.TypeNullValue, knowncodec.NewNullValueCodec()).
+		RegisterCodec(knowncodec.TypeStringValue, knowncodec.NewStringValueCodec(mergedOpts.StringValue)).
+		RegisterCodec(knowncodec.TypeTimestamp, knowncodec.NewTimestampCodec(mergedOpts.Timestamp)).
+		RegisterCodec(knowncodec.TypeUInt32Value, knowncodec.NewUInt32ValueCodec(mergedOpts.UInt32Value)).
+		RegisterCodec(knowncodec.TypeUInt64Value, knowncodec.NewUInt64ValueCodec(mergedOpts.UInt64Value)).
+		RegisterCodec(googleapiscodec.TypeColor, googleapiscodec.NewColorCodec()).
+		RegisterCodec(googleapiscodec.TypeDate, googleapiscodec.NewDateCodec()).
+		RegisterCodec(googleapiscodec.TypeDateTime, googleapiscodec.NewDateTimeCodec(mergedOpts.DateTime)).
+		RegisterCodec(googleapiscodec.TypeDayOfWeek, googleapiscodec.NewDayOfWeekCodec()).
+		RegisterCodec(googleapiscodec.TypeDecimal, googleapiscodec.NewDecimalCodec()).
+		RegisterCodec(googleapiscodec.TypeFraction, googleapiscodec.NewFractionCodec()).
+		RegisterCodec(googleapiscodec.TypeInterval, googleapiscodec.NewIntervalCodec()).
+		RegisterCodec(googleapiscodec.TypeLatLng, googleapiscodec.NewLatLngCodec()).
+		RegisterCodec(googleapiscodec.TypeMoney, googleapiscodec.NewMoneyCodec()).
+		RegisterCodec(googleapiscodec.TypeMonth, googleapiscodec.NewMonthCodec()).
+		RegisterCodec(googleapiscodec.TypePhoneNumber, googleapiscodec.NewPhoneNumberCodec()).
+		RegisterCodec(googleapiscodec.TypePostalAddress, googleapiscodec.NewPostalAddressCodec()).
+		RegisterCodec(googleapiscodec.TypeStatus, googleapiscodec.NewStatusCodec()).
+		RegisterCodec(googleapiscodec.TypeTimeOfDay, googleapiscodec.NewTimeOfDayCodec())
+}
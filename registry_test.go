@@ -0,0 +1,58 @@
+package protobson
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	googleapiscodec "go.vallahaye.net/protobson/protobsoncodec/googleapis"
+	"go.vallahaye.net/protobson/protobsonoptions"
+	"google.golang.org/genproto/googleapis/type/datetime"
+	"google.golang.org/genproto/googleapis/type/latlng"
+	"google.golang.org/genproto/googleapis/type/money"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"gotest.tools/v3/assert"
+)
+
+func TestDefaultRegistry(t *testing.T) {
+	for _, typ := range []reflect.Type{
+		googleapiscodec.TypeColor,
+		googleapiscodec.TypeDate,
+		googleapiscodec.TypeDateTime,
+		googleapiscodec.TypeDayOfWeek,
+		googleapiscodec.TypeDecimal,
+		googleapiscodec.TypeFraction,
+		googleapiscodec.TypeInterval,
+		googleapiscodec.TypeLatLng,
+		googleapiscodec.TypeMoney,
+		googleapiscodec.TypeMonth,
+		googleapiscodec.TypePhoneNumber,
+		googleapiscodec.TypePostalAddress,
+		googleapiscodec.TypeStatus,
+		googleapiscodec.TypeTimeOfDay,
+	} {
+		encoder, err := DefaultRegistry.LookupEncoder(typ)
+		assert.NilError(t, err)
+		assert.Equal(t, reflect.TypeOf(googleapiscodec.ColorCodec{}).PkgPath(), reflect.TypeOf(encoder).Elem().PkgPath(), typ)
+	}
+	b, err := bson.MarshalWithRegistry(DefaultRegistry, bson.D{
+		{Key: "location", Value: &latlng.LatLng{Latitude: 1, Longitude: 2}},
+		{Key: "price", Value: &money.Money{CurrencyCode: "EUR", Units: 3}},
+	})
+	assert.NilError(t, err)
+	raw := bson.Raw(b)
+	assert.Equal(t, "Point", raw.Lookup("location", "type").StringValue())
+	assert.Equal(t, bsontype.Decimal128, raw.Lookup("price", "amount").Type)
+}
+
+func TestNewRegistryBuilder(t *testing.T) {
+	reg := NewRegistryBuilder(protobsonoptions.Registry().
+		SetDateTime(protobsonoptions.DateTimeCodec().SetFormat(protobsonoptions.DateTimeFormatDocument))).
+		Build()
+	b, err := bson.MarshalWithRegistry(reg, bson.D{
+		{Key: "at", Value: &datetime.DateTime{Year: 2022, Month: 5, Day: 30, Hours: 11, TimeOffset: &datetime.DateTime_UtcOffset{UtcOffset: durationpb.New(0)}}},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, bsontype.EmbeddedDocument, bson.Raw(b).Lookup("at").Type)
+}